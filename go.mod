@@ -49,5 +49,5 @@ require (
 	github.com/joho/godotenv v1.5.1
 	golang.org/x/crypto v0.37.0
 	golang.org/x/sync v0.13.0 // indirect
-	golang.org/x/text v0.24.0 // indirect
+	golang.org/x/text v0.24.0
 )
@@ -1,11 +1,14 @@
 package app
 
 import (
+	"chat-app/internal/config"
 	v1Handler "chat-app/internal/handlers/v1"
 	"chat-app/internal/repository"
 	"chat-app/internal/routes"
 	v1Routes "chat-app/internal/routes/v1"
 	services "chat-app/internal/services/v1"
+	"chat-app/pkg/auth"
+	"chat-app/pkg/cache"
 )
 
 type AdminModule struct {
@@ -15,14 +18,25 @@ type AdminModule struct {
 func NewAdminModule(ctx *ModuleContext) *AdminModule {
 	// init repositories
 	userRepo := repository.NewSqlUserRepository(ctx.DB)
-	roomRepo := repository.NewSqlRoomRepository(ctx.DB)
+	roomRepo := repository.NewSqlRoomRepository(ctx.DB, ctx.Pool)
+	messageRepo := repository.NewSqlMessageRepository(ctx.DB, ctx.Pool)
+	blockRepo := repository.NewSqlBlockRepository(ctx.DB)
+	accountRepo := repository.NewSqlAccountRepository(ctx.DB)
+
+	// init token service để thu hồi phiên đăng nhập khi xóa tài khoản
+	tokenService := auth.NewJWTService(cache.NewRedisCacheService(config.NewRedisClient()))
 
 	// init services
+	auditService := newAuditService(ctx)
 	userService := services.NewUserService(userRepo)
-	roomService := services.NewRoomService(roomRepo, userRepo)
+	roomService := services.NewRoomService(roomRepo, userRepo, messageRepo, blockRepo, auditService, newEmailVerificationPolicy(config.NewEmailVerificationConfig()))
+	accountService := services.NewAccountService(accountRepo, userRepo, tokenService, auditService)
 
 	// init handlers
-	adminHandler := v1Handler.NewAdminHandler(userService, roomService)
+	adminHandler := v1Handler.NewAdminHandler(userService, roomService, accountService, ctx.WSManager)
+
+	// Tài khoản bị tạm khóa, vô hiệu hóa hoặc xóa thì đóng các kết nối websocket đang mở
+	accountService.SetAccountStatusCallback(adminHandler.DisconnectAccount)
 
 	// init routes
 	adminRoutes := v1Routes.NewAdminRoutes(adminHandler)
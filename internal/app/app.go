@@ -8,6 +8,8 @@ import (
 	"chat-app/internal/validation"
 	"chat-app/pkg/auth"
 	"chat-app/pkg/cache"
+	"chat-app/pkg/mailer"
+	"chat-app/pkg/storage"
 	"chat-app/pkg/websocket"
 	"context"
 	"log"
@@ -18,6 +20,7 @@ import (
 	"time"
 
 	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type Module interface {
@@ -31,7 +34,10 @@ type Application struct {
 }
 type ModuleContext struct {
 	DB        sqlc.Querier
+	Pool      *pgxpool.Pool
 	WSManager *websocket.Manager
+	Storage   storage.Storage
+	Mailer    mailer.Mailer
 }
 
 func NewApplication(cfg *config.Config) *Application {
@@ -51,7 +57,10 @@ func NewApplication(cfg *config.Config) *Application {
 
 	ctx := &ModuleContext{
 		DB:        db.DB,
+		Pool:      db.Pool,
 		WSManager: wsManager,
+		Storage:   config.NewStorage(),
+		Mailer:    config.NewMailer(),
 	}
 	modules := []Module{
 		NewUserModule(ctx),
@@ -59,6 +68,9 @@ func NewApplication(cfg *config.Config) *Application {
 		NewRoomModule(ctx), // thêm module Room
 		NewChatModule(ctx),
 		NewAdminModule(ctx), // thêm module Admin
+		NewDataExportModule(ctx), // thêm module xuất dữ liệu cá nhân
+		NewImportModule(ctx), // thêm module import từ Slack/Discord
+		NewAuditModule(ctx), // thêm module audit log
 	}
 	routes.RegisterRoutes(r,tokenService , GetModuleRoutes(modules)...)
 
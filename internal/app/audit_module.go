@@ -0,0 +1,37 @@
+package app
+
+import (
+	v1Handler "chat-app/internal/handlers/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/routes"
+	v1Routes "chat-app/internal/routes/v1"
+	"chat-app/internal/services/v1"
+)
+
+type AuditModule struct {
+	routes routes.Routes
+}
+
+func NewAuditModule(ctx *ModuleContext) *AuditModule {
+	// init service
+	auditService := newAuditService(ctx)
+
+	// init handler
+	auditHandler := v1Handler.NewAuditHandler(auditService)
+
+	// init routes
+	auditRoutes := v1Routes.NewAuditRoutes(auditHandler)
+
+	return &AuditModule{
+		routes: auditRoutes,
+	}
+}
+
+func (am *AuditModule) GetRoutes() routes.Routes {
+	return am.routes
+}
+
+// newAuditService dùng chung cho các module cần ghi audit log
+func newAuditService(ctx *ModuleContext) services.AuditService {
+	return services.NewAuditService(repository.NewSqlAuditRepository(ctx.DB))
+}
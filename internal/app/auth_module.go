@@ -1,7 +1,9 @@
 package app
 
 import (
+	"chat-app/internal/config"
 	v1Handler "chat-app/internal/handlers/v1"
+	"chat-app/internal/middleware"
 	"chat-app/internal/repository"
 	"chat-app/internal/routes"
 	v1Routes "chat-app/internal/routes/v1"
@@ -17,14 +19,39 @@ type AuthModule struct {
 func NewAuthModule(ctx *ModuleContext, tokenService auth.TokenService, cache cache.RedisCacheService) *AuthModule {
 	// init repositories
 	userRepo := repository.NewSqlUserRepository(ctx.DB)
+	verificationRepo := repository.NewSqlEmailVerificationRepository(ctx.DB, ctx.Pool)
+	passwordResetRepo := repository.NewSqlPasswordResetRepository(ctx.DB, ctx.Pool)
 	// TokenService auth.TokenService, cacheService cache.RedisCacheService
 
 	// init services
 	userService := services.NewUserService(userRepo)
-	authService := services.NewAuthService(userRepo, tokenService, cache)
+	auditService := newAuditService(ctx)
+	verificationCfg := config.NewEmailVerificationConfig()
+	policy := newEmailVerificationPolicy(verificationCfg)
+	authService := services.NewAuthService(userRepo, tokenService, cache, auditService, policy)
+	verificationService := services.NewEmailVerificationService(verificationRepo, userRepo, ctx.Mailer, services.EmailVerificationOptions{
+		Secret:         verificationCfg.TokenSecret,
+		TTL:            verificationCfg.TokenTTL,
+		ResendInterval: verificationCfg.ResendInterval,
+		DailyLimit:     verificationCfg.DailyLimit,
+		BaseURL:        verificationCfg.BaseURL,
+	})
+	passwordResetCfg := config.NewPasswordResetConfig()
+	passwordService := services.NewPasswordService(userRepo, passwordResetRepo, tokenService, ctx.Mailer, auditService, services.PasswordResetOptions{
+		TTL:             passwordResetCfg.TokenTTL,
+		RequestInterval: passwordResetCfg.RequestInterval,
+		DailyLimit:      passwordResetCfg.DailyLimit,
+		BaseURL:         passwordResetCfg.BaseURL,
+	})
+
+	// Tài khoản bị tạm khóa, vô hiệu hóa hoặc xóa không dùng được token đã cấp
+	middleware.SetAccountStatusCheck(userService.EnsureAccountActive)
 
 	// init handlers
-	authHandler := v1Handler.NewAuthHandler(userService, authService , tokenService)
+	authHandler := v1Handler.NewAuthHandler(userService, authService , tokenService, verificationService, passwordService, policy, ctx.WSManager)
+
+	// Đổi mật khẩu thì đóng luôn các kết nối websocket đang mở
+	passwordService.SetSessionsRevokedCallback(authHandler.DisconnectSessions)
 
 	// init routes
 	authRoutes := v1Routes.NewAuthRoutes(authHandler)
@@ -37,3 +64,12 @@ func NewAuthModule(ctx *ModuleContext, tokenService auth.TokenService, cache cac
 func (am *AuthModule) GetRoutes() routes.Routes {
 	return am.routes
 }
+
+// newEmailVerificationPolicy lấy những hành động yêu cầu xác minh email từ cấu hình
+func newEmailVerificationPolicy(cfg config.EmailVerificationConfig) services.EmailVerificationPolicy {
+	return services.EmailVerificationPolicy{
+		RequireForLogin:        cfg.RequireForLogin,
+		RequireForJoinRooms:    cfg.RequireForJoinRooms,
+		RequireForSendMessages: cfg.RequireForSendMessages,
+	}
+}
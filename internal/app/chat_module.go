@@ -2,6 +2,7 @@ package app
 
 import (
 	"chat-app/internal/config"
+	"chat-app/internal/db/sqlc"
 	v1Handler "chat-app/internal/handlers/v1"
 	"chat-app/internal/repository"
 	"chat-app/internal/routes"
@@ -9,6 +10,8 @@ import (
 	"chat-app/internal/services/v1"
 	"chat-app/pkg/auth"
 	"chat-app/pkg/cache"
+	"chat-app/pkg/storage"
+	"context"
 )
 
 type ChatModule struct {
@@ -17,14 +20,35 @@ type ChatModule struct {
 
 func NewChatModule(ctx *ModuleContext) *ChatModule {
 	// init repositories
-	messageRepo := repository.NewSqlMessageRepository(ctx.DB)
-	roomRepo := repository.NewSqlRoomRepository(ctx.DB)
+	messageRepo := repository.NewSqlMessageRepository(ctx.DB, ctx.Pool)
+	roomRepo := repository.NewSqlRoomRepository(ctx.DB, ctx.Pool)
 	userRepo := repository.NewSqlUserRepository(ctx.DB)
+	attachmentRepo := repository.NewSqlAttachmentRepository(ctx.DB)
+	notificationRepo := repository.NewSqlNotificationRepository(ctx.DB)
+	pushDeviceRepo := repository.NewSqlPushDeviceRepository(ctx.DB)
+	blockRepo := repository.NewSqlBlockRepository(ctx.DB)
+
+	// init signer cho URL tải file đính kèm
+	attachmentCfg := config.NewAttachmentConfig()
+	urlSigner := storage.NewURLSigner(attachmentCfg.URLSecret, attachmentCfg.URLTTL)
+
+	// init push dispatcher (Web Push, FCM, APNs hoặc endpoint HTTP cục bộ)
+	pushCfg := config.NewPushConfig()
+	pushDispatcher := config.NewPushDispatcher(pushCfg)
+
+	// Hành động yêu cầu email đã xác minh
+	verificationPolicy := newEmailVerificationPolicy(config.NewEmailVerificationConfig())
 
 	// init services
-	messageService := services.NewMessageService(messageRepo, roomRepo, userRepo)
-	roomService := services.NewRoomService(roomRepo, userRepo)
+	messageService := services.NewMessageService(messageRepo, roomRepo, userRepo, attachmentRepo, blockRepo, urlSigner, verificationPolicy)
+	roomService := services.NewRoomService(roomRepo, userRepo, messageRepo, blockRepo, newAuditService(ctx), verificationPolicy)
 	userService := services.NewUserService(userRepo)
+	blockService := services.NewBlockService(blockRepo, userRepo)
+	attachmentProcessor := services.NewAttachmentProcessor(attachmentRepo, ctx.Storage, urlSigner, attachmentCfg.MaxSizeBytes)
+	notificationService := services.NewNotificationService(notificationRepo, roomRepo, userRepo)
+	pushService := services.NewPushService(pushDeviceRepo, roomRepo, blockRepo, userRepo, pushDispatcher)
+	roomExportService := services.NewRoomExportService(roomRepo, messageRepo, attachmentRepo)
+	attachmentService := services.NewAttachmentService(attachmentRepo, roomRepo, messageService, ctx.Storage, urlSigner, attachmentProcessor, attachmentCfg.MaxSizeBytes)
 
 	// init Redis cache service for JWT
 	redisClient := config.NewRedisClient()
@@ -37,14 +61,40 @@ func NewChatModule(ctx *ModuleContext) *ChatModule {
 		roomService,
 		messageService,
 		userService,
+		blockService,
 		jwtService,
 	)
 
+	// init Notification handler, thông báo mới được gửi tới mọi kết nối của người nhận
+	notificationHandler := v1Handler.NewNotificationHandler(notificationService, ctx.WSManager)
+	notificationService.SetNotificationCallback(notificationHandler.DeliverNotification)
+
+	// Gửi sự kiện mention và lưu thông báo cho người được nhắc, @here dựa trên kết nối websocket hiện tại
+	messageService.SetMentionCallback(func(message sqlc.Message, mentions []sqlc.MessageMention) {
+		wsHandler.NotifyMentions(message, mentions)
+		notificationService.NotifyMentions(context.Background(), message, mentions)
+	})
+	messageService.SetPresenceCallback(ctx.WSManager.IsUserOnline)
+
+	// Push cho thành viên offline khi có tin nhắn mới
+	pushService.SetPresenceCallback(ctx.WSManager.IsUserOnline)
+	messageService.SetNewMessageCallback(pushService.NotifyNewMessage)
+
+	// init Push handler
+	pushHandler := v1Handler.NewPushHandler(pushService, pushCfg.WebPush.PublicKey)
+
 	// init Message handler
-	messageHandler := v1Handler.NewMessageHandler(messageService)
+	messageHandler := v1Handler.NewMessageHandler(messageService, roomExportService)
+
+	// init Attachment handler
+	attachmentHandler := v1Handler.NewAttachmentHandler(attachmentService, userService, ctx.WSManager, attachmentCfg.MaxSizeBytes)
+
+	// Start thumbnail workers, thông báo client qua websocket khi xử lý xong
+	attachmentProcessor.SetProcessedCallback(attachmentHandler.NotifyAttachmentProcessed)
+	attachmentProcessor.StartWorkerPool(4)
 
 	// init routes
-	chatRoutes := v1Routes.NewChatRoutes(wsHandler, messageHandler)
+	chatRoutes := v1Routes.NewChatRoutes(wsHandler, messageHandler, attachmentHandler, notificationHandler, pushHandler)
 
 	return &ChatModule{
 		routes: chatRoutes,
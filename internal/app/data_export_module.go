@@ -0,0 +1,49 @@
+package app
+
+import (
+	"chat-app/internal/config"
+	v1Handler "chat-app/internal/handlers/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/routes"
+	v1Routes "chat-app/internal/routes/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/pkg/storage"
+)
+
+type DataExportModule struct {
+	routes routes.Routes
+}
+
+func NewDataExportModule(ctx *ModuleContext) *DataExportModule {
+	// init repositories
+	exportRepo := repository.NewSqlDataExportRepository(ctx.DB)
+	userRepo := repository.NewSqlUserRepository(ctx.DB)
+	roomRepo := repository.NewSqlRoomRepository(ctx.DB, ctx.Pool)
+	messageRepo := repository.NewSqlMessageRepository(ctx.DB, ctx.Pool)
+	attachmentRepo := repository.NewSqlAttachmentRepository(ctx.DB)
+
+	// init signer cho link tải file ZIP
+	exportCfg := config.NewDataExportConfig()
+	urlSigner := storage.NewURLSigner(exportCfg.URLSecret, exportCfg.URLTTL)
+
+	// init service
+	dataExportService := services.NewDataExportService(exportRepo, userRepo, roomRepo, messageRepo, attachmentRepo, ctx.Storage, urlSigner, newAuditService(ctx), exportCfg.Retention)
+
+	// init handler
+	dataExportHandler := v1Handler.NewDataExportHandler(dataExportService, ctx.WSManager)
+
+	// Báo cho người yêu cầu qua websocket khi job xong, rồi chạy worker và job dọn file hết hạn
+	dataExportService.SetFinishedCallback(dataExportHandler.NotifyExportFinished)
+	dataExportService.Start(exportCfg.Workers, exportCfg.CleanupInterval)
+
+	// init routes
+	dataExportRoutes := v1Routes.NewDataExportRoutes(dataExportHandler)
+
+	return &DataExportModule{
+		routes: dataExportRoutes,
+	}
+}
+
+func (dm *DataExportModule) GetRoutes() routes.Routes {
+	return dm.routes
+}
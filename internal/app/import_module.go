@@ -0,0 +1,39 @@
+package app
+
+import (
+	"chat-app/internal/config"
+	v1Handler "chat-app/internal/handlers/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/routes"
+	v1Routes "chat-app/internal/routes/v1"
+	"chat-app/internal/services/v1"
+)
+
+type ImportModule struct {
+	routes routes.Routes
+}
+
+func NewImportModule(ctx *ModuleContext) *ImportModule {
+	// init repositories
+	importRepo := repository.NewSqlImportRepository(ctx.DB, ctx.Pool)
+	userRepo := repository.NewSqlUserRepository(ctx.DB)
+	roomRepo := repository.NewSqlRoomRepository(ctx.DB, ctx.Pool)
+
+	// init service
+	importCfg := config.NewImportConfig()
+	importService := services.NewImportService(importRepo, userRepo, roomRepo, newAuditService(ctx), importCfg.BatchSize)
+
+	// init handler
+	importHandler := v1Handler.NewImportHandler(importService, importCfg.MaxSizeBytes)
+
+	// init routes
+	importRoutes := v1Routes.NewImportRoutes(importHandler)
+
+	return &ImportModule{
+		routes: importRoutes,
+	}
+}
+
+func (im *ImportModule) GetRoutes() routes.Routes {
+	return im.routes
+}
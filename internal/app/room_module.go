@@ -1,6 +1,7 @@
 package app
 
 import (
+	"chat-app/internal/config"
 	v1Handler "chat-app/internal/handlers/v1"
 	"chat-app/internal/repository"
 	"chat-app/internal/routes"
@@ -14,14 +15,17 @@ type RoomModule struct {
 
 func NewRoomModule(ctx *ModuleContext) *RoomModule {
 	// init repository
-	roomRepo := repository.NewSqlRoomRepository(ctx.DB)
+	roomRepo := repository.NewSqlRoomRepository(ctx.DB, ctx.Pool)
+	messageRepo := repository.NewSqlMessageRepository(ctx.DB, ctx.Pool)
+	blockRepo := repository.NewSqlBlockRepository(ctx.DB)
 	userRepo := repository.NewSqlUserRepository(ctx.DB)
 
 	// init service
-	roomService := services.NewRoomService(roomRepo, userRepo)
+	roomService := services.NewRoomService(roomRepo, userRepo, messageRepo, blockRepo, newAuditService(ctx), newEmailVerificationPolicy(config.NewEmailVerificationConfig()))
+	userService := services.NewUserService(userRepo)
 
 	// init handler
-	roomHandler := v1Handler.NewRoomHandler(roomService)
+	roomHandler := v1Handler.NewRoomHandler(roomService, userService, ctx.WSManager)
 
 	// init routes
 	roomRoutes := v1Routes.NewRoomRoutes(roomHandler)
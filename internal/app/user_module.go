@@ -1,11 +1,14 @@
 package app
 
 import (
+	"chat-app/internal/config"
 	v1Handler "chat-app/internal/handlers/v1"
 	"chat-app/internal/repository"
 	"chat-app/internal/routes"
 	v1Routes "chat-app/internal/routes/v1"
 	"chat-app/internal/services/v1"
+	"chat-app/pkg/auth"
+	"chat-app/pkg/cache"
 )
 
 type UserModule struct {
@@ -15,12 +18,31 @@ type UserModule struct {
 func NewUserModule(ctx *ModuleContext) *UserModule {
 	// init repository
 	userRepo := repository.NewSqlUserRepository(ctx.DB)
+	digestRepo := repository.NewSqlDigestRepository(ctx.DB)
+	blockRepo := repository.NewSqlBlockRepository(ctx.DB)
+	accountRepo := repository.NewSqlAccountRepository(ctx.DB)
+	// init token service để thu hồi phiên đăng nhập khi xóa tài khoản
+	tokenService := auth.NewJWTService(cache.NewRedisCacheService(config.NewRedisClient()))
 	// init service
 	userService := services.NewUserService(userRepo)
+	digestCfg := config.NewDigestConfig()
+	digestService := services.NewDigestService(digestRepo, userRepo, ctx.Mailer, digestCfg.BaseURL, digestCfg.OfflineAfter)
+	blockService := services.NewBlockService(blockRepo, userRepo)
+	accountService := services.NewAccountService(accountRepo, userRepo, tokenService, newAuditService(ctx))
 	// init handler
-	userHandler := v1Handler.NewUserHandler(userService)
+	userHandler := v1Handler.NewUserHandler(userService, accountService, ctx.WSManager)
+	digestHandler := v1Handler.NewDigestHandler(digestService)
+	blockHandler := v1Handler.NewBlockHandler(blockService)
 	// init routes
-	userRoutes := v1Routes.NewUserRoutes(userHandler)
+	userRoutes := v1Routes.NewUserRoutes(userHandler, digestHandler, blockHandler)
+
+	// Xóa tài khoản thì đóng các kết nối websocket đang mở
+	accountService.SetAccountStatusCallback(userHandler.DisconnectAccount)
+
+	// Lưu thời điểm online/offline và gửi email digest cho user offline lâu
+	ctx.WSManager.SetPresenceChangeCallback(digestService.TrackPresence)
+	digestService.SetPresenceCallback(ctx.WSManager.IsUserOnline)
+	digestService.Start(digestCfg.Interval)
 
 	return &UserModule{
 		routes: userRoutes,
@@ -0,0 +1,25 @@
+package config
+
+import (
+	"chat-app/internal/utils"
+	"time"
+)
+
+// DataExportConfig cấu hình job xuất dữ liệu cá nhân và link tải file ZIP
+type DataExportConfig struct {
+	Retention       time.Duration // file ZIP được giữ trong storage bao lâu sau khi tạo xong
+	URLSecret       string
+	URLTTL          time.Duration // thời hạn của mỗi link tải
+	Workers         int
+	CleanupInterval time.Duration // chu kỳ xóa các file đã hết hạn
+}
+
+func NewDataExportConfig() DataExportConfig {
+	return DataExportConfig{
+		Retention:       time.Duration(utils.GetIntEnv("DATA_EXPORT_RETENTION_HOURS", 168)) * time.Hour,
+		URLSecret:       utils.GetEnv("DATA_EXPORT_URL_SECRET", utils.GetEnv("JWT_SECRET", "your_secret_key_here")),
+		URLTTL:          time.Duration(utils.GetIntEnv("DATA_EXPORT_URL_TTL_MINUTES", 15)) * time.Minute,
+		Workers:         utils.GetIntEnv("DATA_EXPORT_WORKERS", 2),
+		CleanupInterval: time.Duration(utils.GetIntEnv("DATA_EXPORT_CLEANUP_MINUTES", 60)) * time.Minute,
+	}
+}
@@ -0,0 +1,16 @@
+package config
+
+import "chat-app/internal/utils"
+
+// ImportConfig cấu hình import lịch sử chat từ Slack/Discord
+type ImportConfig struct {
+	MaxSizeBytes int64 // dung lượng tối đa của file ZIP tải lên
+	BatchSize    int   // số tin nhắn ghi mỗi lần COPY
+}
+
+func NewImportConfig() ImportConfig {
+	return ImportConfig{
+		MaxSizeBytes: int64(utils.GetIntEnv("IMPORT_MAX_SIZE_MB", 512)) << 20,
+		BatchSize:    utils.GetIntEnv("IMPORT_BATCH_SIZE", 5000),
+	}
+}
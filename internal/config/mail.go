@@ -0,0 +1,45 @@
+package config
+
+import (
+	"chat-app/internal/utils"
+	"chat-app/pkg/mailer"
+	"log"
+	"strings"
+	"time"
+)
+
+// NewMailer tạo SMTP mailer. Mặc định trỏ tới SMTP giả lập cục bộ (Mailpit: localhost:1025, web UI :8025)
+func NewMailer() mailer.Mailer {
+	m, err := mailer.NewSMTPMailer(mailer.SMTPConfig{
+		Host:     utils.GetEnv("SMTP_HOST", "localhost"),
+		Port:     utils.GetIntEnv("SMTP_PORT", 1025),
+		Username: utils.GetEnv("SMTP_USERNAME", ""),
+		Password: utils.GetEnv("SMTP_PASSWORD", ""),
+		From:     utils.GetEnv("SMTP_FROM", "Chat App <no-reply@chat-app.local>"),
+		TLSMode:  strings.ToLower(utils.GetEnv("SMTP_TLS_MODE", mailer.TLSModeNone)),
+	})
+	if err != nil {
+		log.Fatal("Failed to initialize mailer:", err)
+	}
+	return m
+}
+
+// AppBaseURL là địa chỉ frontend, dùng để tạo link trong email
+func AppBaseURL() string {
+	return strings.TrimRight(utils.GetEnv("APP_BASE_URL", "http://localhost:3000"), "/")
+}
+
+// DigestConfig cấu hình job gửi email digest
+type DigestConfig struct {
+	Interval     time.Duration // chu kỳ quét user cần gửi digest
+	OfflineAfter time.Duration // chỉ gửi cho user đã offline ít nhất khoảng này
+	BaseURL      string
+}
+
+func NewDigestConfig() DigestConfig {
+	return DigestConfig{
+		Interval:     time.Duration(utils.GetIntEnv("DIGEST_INTERVAL_MINUTES", 15)) * time.Minute,
+		OfflineAfter: time.Duration(utils.GetIntEnv("DIGEST_OFFLINE_HOURS", 2)) * time.Hour,
+		BaseURL:      AppBaseURL(),
+	}
+}
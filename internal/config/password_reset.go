@@ -0,0 +1,23 @@
+package config
+
+import (
+	"chat-app/internal/utils"
+	"time"
+)
+
+// PasswordResetConfig cấu hình token đặt lại mật khẩu và giới hạn số lần yêu cầu
+type PasswordResetConfig struct {
+	TokenTTL        time.Duration
+	RequestInterval time.Duration // khoảng cách tối thiểu giữa 2 email đặt lại mật khẩu
+	DailyLimit      int           // số email đặt lại mật khẩu tối đa mỗi 24 giờ
+	BaseURL         string
+}
+
+func NewPasswordResetConfig() PasswordResetConfig {
+	return PasswordResetConfig{
+		TokenTTL:        time.Duration(utils.GetIntEnv("PASSWORD_RESET_TTL_MINUTES", 60)) * time.Minute,
+		RequestInterval: time.Duration(utils.GetIntEnv("PASSWORD_RESET_REQUEST_SECONDS", 60)) * time.Second,
+		DailyLimit:      utils.GetIntEnv("PASSWORD_RESET_DAILY_LIMIT", 5),
+		BaseURL:         AppBaseURL(),
+	}
+}
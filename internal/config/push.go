@@ -0,0 +1,77 @@
+package config
+
+import (
+	"chat-app/internal/utils"
+	"chat-app/pkg/push"
+	"log"
+)
+
+// PushConfig là cấu hình push, nền tảng nào không có cấu hình thật sẽ dùng PUSH_HTTP_ENDPOINT (nếu có)
+type PushConfig struct {
+	WebPush            push.WebPushConfig
+	FCMCredentialsFile string
+	APNs               push.APNsConfig
+	HTTPEndpoint       string
+}
+
+func NewPushConfig() PushConfig {
+	return PushConfig{
+		WebPush: push.WebPushConfig{
+			PublicKey:  utils.GetEnv("VAPID_PUBLIC_KEY", ""),
+			PrivateKey: utils.GetEnv("VAPID_PRIVATE_KEY", ""),
+			Subject:    utils.GetEnv("VAPID_SUBJECT", "mailto:admin@example.com"),
+		},
+		FCMCredentialsFile: utils.GetEnv("FCM_CREDENTIALS_FILE", ""),
+		APNs: push.APNsConfig{
+			KeyFile:    utils.GetEnv("APNS_KEY_FILE", ""),
+			KeyID:      utils.GetEnv("APNS_KEY_ID", ""),
+			TeamID:     utils.GetEnv("APNS_TEAM_ID", ""),
+			Topic:      utils.GetEnv("APNS_TOPIC", ""),
+			Production: utils.GetEnv("APNS_PRODUCTION", "false") == "true",
+		},
+		HTTPEndpoint: utils.GetEnv("PUSH_HTTP_ENDPOINT", ""),
+	}
+}
+
+// NewPushDispatcher tạo dispatcher với provider cho từng nền tảng đã cấu hình và chạy worker
+func NewPushDispatcher(cfg PushConfig) *push.Dispatcher {
+	dispatcher := push.NewDispatcher(push.DefaultDispatcherConfig())
+
+	if cfg.WebPush.PrivateKey != "" {
+		if cfg.WebPush.PublicKey == "" {
+			log.Fatal("VAPID_PUBLIC_KEY is required when VAPID_PRIVATE_KEY is set")
+		}
+		provider, err := push.NewWebPushProvider(cfg.WebPush)
+		if err != nil {
+			log.Fatal("Failed to initialize web push:", err)
+		}
+		dispatcher.Register(push.PlatformWebPush, provider)
+	}
+	if cfg.FCMCredentialsFile != "" {
+		provider, err := push.NewFCMProvider(cfg.FCMCredentialsFile)
+		if err != nil {
+			log.Fatal("Failed to initialize FCM:", err)
+		}
+		dispatcher.Register(push.PlatformFCM, provider)
+	}
+	if cfg.APNs.KeyFile != "" {
+		provider, err := push.NewAPNsProvider(cfg.APNs)
+		if err != nil {
+			log.Fatal("Failed to initialize APNs:", err)
+		}
+		dispatcher.Register(push.PlatformAPNs, provider)
+	}
+
+	// Endpoint HTTP cục bộ nhận push của các nền tảng còn lại (dev/test)
+	if cfg.HTTPEndpoint != "" {
+		provider := push.NewHTTPProvider(cfg.HTTPEndpoint)
+		for _, platform := range []string{push.PlatformWebPush, push.PlatformFCM, push.PlatformAPNs} {
+			if !dispatcher.Supports(platform) {
+				dispatcher.Register(platform, provider)
+			}
+		}
+	}
+
+	dispatcher.Start()
+	return dispatcher
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"chat-app/internal/utils"
+	"chat-app/pkg/storage"
+	"context"
+	"log"
+	"strings"
+	"time"
+)
+
+// AttachmentConfig giới hạn upload và thời hạn của URL tải file
+type AttachmentConfig struct {
+	MaxSizeBytes int64
+	URLSecret    string
+	URLTTL       time.Duration
+}
+
+func NewAttachmentConfig() AttachmentConfig {
+	return AttachmentConfig{
+		MaxSizeBytes: int64(utils.GetIntEnv("ATTACHMENT_MAX_SIZE_MB", 25)) << 20,
+		URLSecret:    utils.GetEnv("ATTACHMENT_URL_SECRET", utils.GetEnv("JWT_SECRET", "your_secret_key_here")),
+		URLTTL:       time.Duration(utils.GetIntEnv("ATTACHMENT_URL_TTL_MINUTES", 15)) * time.Minute,
+	}
+}
+
+// NewStorage chọn backend lưu file theo STORAGE_DRIVER: "local" (mặc định) hoặc "s3" (AWS S3, MinIO...)
+func NewStorage() storage.Storage {
+	driver := strings.ToLower(utils.GetEnv("STORAGE_DRIVER", "local"))
+
+	var (
+		store storage.Storage
+		err   error
+	)
+	switch driver {
+	case "s3":
+		store, err = storage.NewS3Storage(storage.S3Config{
+			Endpoint:     utils.GetEnv("S3_ENDPOINT", "http://localhost:9000"),
+			Region:       utils.GetEnv("S3_REGION", "us-east-1"),
+			Bucket:       utils.GetEnv("S3_BUCKET", "chat-app"),
+			AccessKey:    utils.GetEnv("S3_ACCESS_KEY", ""),
+			SecretKey:    utils.GetEnv("S3_SECRET_KEY", ""),
+			UsePathStyle: utils.GetEnv("S3_USE_PATH_STYLE", "true") == "true",
+		})
+		if err == nil {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			err = storage.EnsureBucket(ctx, store)
+		}
+	case "local":
+		store, err = storage.NewLocalStorage(utils.GetEnv("STORAGE_LOCAL_DIR", "./uploads"))
+	default:
+		log.Fatalf("Unknown STORAGE_DRIVER %q (expected local or s3)", driver)
+	}
+	if err != nil {
+		log.Fatal("Failed to initialize storage:", err)
+	}
+	log.Printf("Storage initialized (driver=%s)", driver)
+	return store
+}
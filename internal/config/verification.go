@@ -0,0 +1,43 @@
+package config
+
+import (
+	"chat-app/internal/utils"
+	"strings"
+	"time"
+)
+
+// EmailVerificationConfig cấu hình token xác minh email và những gì user chưa xác minh bị chặn
+type EmailVerificationConfig struct {
+	TokenSecret    string
+	TokenTTL       time.Duration
+	ResendInterval time.Duration // khoảng cách tối thiểu giữa 2 lần gửi
+	DailyLimit     int           // số email xác minh tối đa mỗi 24 giờ
+	BaseURL        string
+
+	// EMAIL_VERIFICATION_REQUIRED_FOR: danh sách phân cách bởi dấu phẩy gồm login, join_rooms, send_messages
+	RequireForLogin        bool
+	RequireForJoinRooms    bool
+	RequireForSendMessages bool
+}
+
+func NewEmailVerificationConfig() EmailVerificationConfig {
+	cfg := EmailVerificationConfig{
+		TokenSecret:    utils.GetEnv("EMAIL_TOKEN_SECRET", utils.GetEnv("JWT_SECRET", "your_secret_key_here")),
+		TokenTTL:       time.Duration(utils.GetIntEnv("EMAIL_VERIFICATION_TTL_HOURS", 24)) * time.Hour,
+		ResendInterval: time.Duration(utils.GetIntEnv("EMAIL_VERIFICATION_RESEND_SECONDS", 60)) * time.Second,
+		DailyLimit:     utils.GetIntEnv("EMAIL_VERIFICATION_DAILY_LIMIT", 5),
+		BaseURL:        AppBaseURL(),
+	}
+
+	for _, action := range strings.Split(utils.GetEnv("EMAIL_VERIFICATION_REQUIRED_FOR", "send_messages"), ",") {
+		switch strings.TrimSpace(strings.ToLower(action)) {
+		case "login":
+			cfg.RequireForLogin = true
+		case "join_rooms":
+			cfg.RequireForJoinRooms = true
+		case "send_messages":
+			cfg.RequireForSendMessages = true
+		}
+	}
+	return cfg
+}
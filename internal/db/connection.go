@@ -13,6 +13,9 @@ import (
 
 var DB sqlc.Querier
 
+// Pool dùng khi cần mở transaction (Queries.WithTx)
+var Pool *pgxpool.Pool
+
 func InitDB() error {
 	connStr := config.NewConfig().DNS()
 
@@ -34,6 +37,7 @@ func InitDB() error {
 		return fmt.Errorf("failed to create database connection pool: %v", err)
 	}
 	DB = sqlc.New(DBpool) // Khởi tạo sqlc với DBpool
+	Pool = DBpool
 	if err := DBpool.Ping(ctx); err != nil {
 		return fmt.Errorf("failed to ping database: %v", err)
 	} // Kiểm tra kết nối đến cơ sở dữ liệu
@@ -0,0 +1,10 @@
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS chk_message_type;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS message_type;
+
+ALTER TABLE rooms DROP CONSTRAINT IF EXISTS chk_room_description_length;
+
+ALTER TABLE rooms
+DROP COLUMN IF EXISTS room_avatar_url,
+DROP COLUMN IF EXISTS room_description,
+DROP COLUMN IF EXISTS room_topic;
@@ -0,0 +1,23 @@
+-- Thông tin hiển thị của phòng: chủ đề, mô tả, ảnh đại diện
+ALTER TABLE rooms
+ADD COLUMN room_topic VARCHAR(255), -- Chủ đề ngắn hiển thị trên header phòng
+ADD COLUMN room_description TEXT, -- Mô tả chi tiết của phòng
+ADD COLUMN room_avatar_url VARCHAR(500), -- Đường dẫn ảnh đại diện phòng
+ADD CONSTRAINT chk_room_description_length CHECK (
+    LENGTH(room_description) <= 1000
+);
+
+-- Loại tin nhắn: 'text' do người dùng gửi, 'system' do server sinh ra (đổi tên phòng, đổi chủ đề...)
+ALTER TABLE messages
+ADD COLUMN message_type VARCHAR(20) NOT NULL DEFAULT 'text',
+ADD CONSTRAINT chk_message_type CHECK (
+    message_type IN ('text', 'system')
+);
+
+-- Người tạo phòng trước đây được thêm với role mặc định 'Member' => nâng lên 'Owner'
+UPDATE room_members rm
+SET member_role = 'Owner'
+FROM rooms r
+WHERE
+    r.room_id = rm.room_id
+    AND r.room_created_by = rm.user_uuid;
@@ -0,0 +1,3 @@
+DROP TRIGGER IF EXISTS trigger_promote_room_owner ON room_members;
+
+DROP FUNCTION IF EXISTS promote_room_owner_on_member_removed ();
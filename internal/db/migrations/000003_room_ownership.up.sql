@@ -0,0 +1,61 @@
+-- Tự động chuyển quyền Owner khi Owner cuối cùng rời phòng hoặc tài khoản bị xóa (ON DELETE CASCADE)
+-- Ưu tiên Admin lâu năm nhất, sau đó đến Member lâu năm nhất
+CREATE OR REPLACE FUNCTION promote_room_owner_on_member_removed()
+RETURNS TRIGGER AS $$
+BEGIN
+    IF OLD.member_role <> 'Owner' THEN
+        RETURN OLD;
+    END IF;
+
+    -- Phòng đang bị xóa (cascade từ rooms) thì không cần chuyển quyền
+    IF NOT EXISTS (SELECT 1 FROM rooms WHERE room_id = OLD.room_id) THEN
+        RETURN OLD;
+    END IF;
+
+    -- Phòng vẫn còn Owner khác
+    IF EXISTS (
+        SELECT 1
+        FROM room_members
+        WHERE room_id = OLD.room_id AND member_role = 'Owner'
+    ) THEN
+        RETURN OLD;
+    END IF;
+
+    UPDATE room_members
+    SET member_role = 'Owner'
+    WHERE (user_uuid, room_id) = (
+        SELECT user_uuid, room_id
+        FROM room_members
+        WHERE room_id = OLD.room_id
+        ORDER BY
+            CASE member_role WHEN 'Admin' THEN 0 ELSE 1 END,
+            room_member_created_at ASC
+        LIMIT 1
+    );
+
+    RETURN OLD;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER trigger_promote_room_owner
+AFTER DELETE ON room_members
+FOR EACH ROW
+EXECUTE FUNCTION promote_room_owner_on_member_removed();
+
+-- Các phòng đã mất Owner trước đây (người tạo bị xóa) => chọn người kế nhiệm
+UPDATE room_members rm
+SET member_role = 'Owner'
+FROM (
+    SELECT DISTINCT ON (room_id) room_id, user_uuid
+    FROM room_members
+    WHERE room_id NOT IN (
+        SELECT room_id FROM room_members WHERE member_role = 'Owner'
+    )
+    ORDER BY
+        room_id,
+        CASE member_role WHEN 'Admin' THEN 0 ELSE 1 END,
+        room_member_created_at ASC
+) heir
+WHERE
+    rm.room_id = heir.room_id
+    AND rm.user_uuid = heir.user_uuid;
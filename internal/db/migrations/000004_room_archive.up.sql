@@ -0,0 +1,2 @@
+-- Phòng đã lưu trữ: chỉ đọc, ẩn khỏi danh sách mặc định nhưng vẫn giữ lịch sử tin nhắn
+ALTER TABLE rooms ADD COLUMN room_archived_at TIMESTAMPTZ; -- NULL = đang hoạt động
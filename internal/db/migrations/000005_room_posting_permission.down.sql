@@ -0,0 +1 @@
+ALTER TABLE rooms DROP COLUMN IF EXISTS room_posting_permission;
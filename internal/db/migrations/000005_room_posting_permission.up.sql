@@ -0,0 +1,5 @@
+-- Quyền gửi tin nhắn trong phòng: 'everyone' = mọi thành viên, 'admins' = chỉ Owner/Admin (kênh thông báo)
+ALTER TABLE rooms
+ADD COLUMN room_posting_permission VARCHAR(20) NOT NULL DEFAULT 'everyone' CHECK (
+    room_posting_permission IN ('everyone', 'admins')
+);
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_messages_room_user_created_at;
+
+ALTER TABLE rooms DROP COLUMN IF EXISTS room_slow_mode_seconds;
@@ -0,0 +1,12 @@
+-- Slow mode: số giây tối thiểu giữa hai tin nhắn của cùng một thành viên (0 = tắt)
+ALTER TABLE rooms
+ADD COLUMN room_slow_mode_seconds INTEGER NOT NULL DEFAULT 0 CHECK (
+    room_slow_mode_seconds BETWEEN 0 AND 21600
+);
+
+-- Tra cứu nhanh tin nhắn gần nhất của user trong phòng
+CREATE INDEX idx_messages_room_user_created_at ON messages (
+    room_id,
+    user_uuid,
+    message_created_at DESC
+);
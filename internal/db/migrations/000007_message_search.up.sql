@@ -0,0 +1,8 @@
+-- Full-text search cho nội dung tin nhắn.
+-- Dùng cấu hình 'simple' (không stemming) vì tin nhắn trộn lẫn tiếng Việt và tiếng Anh.
+ALTER TABLE messages
+ADD COLUMN content_tsv TSVECTOR GENERATED ALWAYS AS (
+    to_tsvector('simple', content)
+) STORED;
+
+CREATE INDEX idx_messages_content_tsv ON messages USING GIN (content_tsv);
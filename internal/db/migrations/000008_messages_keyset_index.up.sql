@@ -0,0 +1,2 @@
+-- Keyset pagination cho lịch sử tin nhắn: WHERE room_id = ? AND message_id < ? ORDER BY message_id DESC
+CREATE INDEX idx_messages_room_id_message_id ON messages (room_id, message_id);
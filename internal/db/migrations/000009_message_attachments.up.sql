@@ -0,0 +1,18 @@
+-- File đính kèm của tin nhắn, nội dung file nằm ở storage backend (local/S3), DB chỉ lưu metadata
+CREATE TABLE message_attachments (
+    attachment_id BIGSERIAL PRIMARY KEY,
+    message_id BIGINT NOT NULL, -- Tin nhắn chứa file
+    room_id BIGINT NOT NULL, -- Phòng chứa tin nhắn, dùng để kiểm tra quyền khi tải file
+    uploader_uuid UUID NOT NULL, -- Người upload
+    storage_key VARCHAR(500) UNIQUE NOT NULL, -- Khóa object trong storage backend
+    file_name VARCHAR(255) NOT NULL, -- Tên file gốc (đã làm sạch)
+    content_type VARCHAR(100) NOT NULL, -- MIME type nhận diện từ nội dung file
+    size_bytes BIGINT NOT NULL,
+    attachment_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_attachment_message FOREIGN KEY (message_id) REFERENCES messages (message_id) ON DELETE CASCADE,
+    CONSTRAINT fk_attachment_room FOREIGN KEY (room_id) REFERENCES rooms (room_id) ON DELETE CASCADE,
+    CONSTRAINT fk_attachment_uploader FOREIGN KEY (uploader_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE,
+    CONSTRAINT chk_attachment_size CHECK (size_bytes > 0)
+);
+
+CREATE INDEX idx_message_attachments_message_id ON message_attachments (message_id);
@@ -0,0 +1,8 @@
+ALTER TABLE message_attachments
+DROP COLUMN IF EXISTS image_width,
+DROP COLUMN IF EXISTS image_height,
+DROP COLUMN IF EXISTS thumbnail_key,
+DROP COLUMN IF EXISTS thumbnail_content_type,
+DROP COLUMN IF EXISTS thumbnail_width,
+DROP COLUMN IF EXISTS thumbnail_height,
+DROP COLUMN IF EXISTS attachment_processed_at;
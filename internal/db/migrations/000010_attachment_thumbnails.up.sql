@@ -0,0 +1,9 @@
+-- Metadata ảnh và thumbnail do worker xử lý nền điền vào sau khi upload
+ALTER TABLE message_attachments
+ADD COLUMN image_width INTEGER, -- Kích thước ảnh gốc (NULL nếu không phải ảnh)
+ADD COLUMN image_height INTEGER,
+ADD COLUMN thumbnail_key VARCHAR(500), -- Khóa object của thumbnail trong storage
+ADD COLUMN thumbnail_content_type VARCHAR(100),
+ADD COLUMN thumbnail_width INTEGER,
+ADD COLUMN thumbnail_height INTEGER,
+ADD COLUMN attachment_processed_at TIMESTAMPTZ; -- NULL = chưa xử lý xong
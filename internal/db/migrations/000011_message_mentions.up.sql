@@ -0,0 +1,22 @@
+-- Lượt nhắc (@user, @room, @here) trong tin nhắn, mỗi người được nhắc tối đa một lần mỗi tin nhắn
+CREATE TABLE message_mentions (
+    mention_id BIGSERIAL PRIMARY KEY,
+    message_id BIGINT NOT NULL, -- Tin nhắn chứa lượt nhắc
+    room_id BIGINT NOT NULL, -- Phòng chứa tin nhắn, dùng để đếm theo phòng
+    mentioned_user_uuid UUID NOT NULL, -- Người được nhắc
+    mention_type VARCHAR(10) NOT NULL DEFAULT 'user', -- user: @tên, room: @room, here: @here
+    mention_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    mention_read_at TIMESTAMPTZ, -- NULL = chưa đọc
+    CONSTRAINT fk_mention_message FOREIGN KEY (message_id) REFERENCES messages (message_id) ON DELETE CASCADE,
+    CONSTRAINT fk_mention_room FOREIGN KEY (room_id) REFERENCES rooms (room_id) ON DELETE CASCADE,
+    CONSTRAINT fk_mention_user FOREIGN KEY (mentioned_user_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE,
+    CONSTRAINT uq_mention_message_user UNIQUE (message_id, mentioned_user_uuid),
+    CONSTRAINT chk_mention_type CHECK (
+        mention_type IN ('user', 'room', 'here')
+    )
+);
+
+-- Đếm lượt nhắc chưa đọc của user theo phòng (ListRooms)
+CREATE INDEX idx_message_mentions_unread ON message_mentions (mentioned_user_uuid, room_id)
+WHERE
+    mention_read_at IS NULL;
@@ -0,0 +1,26 @@
+-- Hộp thông báo của từng user
+CREATE TABLE notifications (
+    notification_id BIGSERIAL PRIMARY KEY,
+    user_uuid UUID NOT NULL, -- Người nhận thông báo
+    notification_type VARCHAR(20) NOT NULL, -- Loại thông báo
+    room_id BIGINT, -- Phòng liên quan (nếu có)
+    message_id BIGINT, -- Tin nhắn liên quan (nếu có)
+    actor_uuid UUID, -- Người tạo ra sự kiện (người nhắc, người mời...)
+    notification_data JSONB NOT NULL DEFAULT '{}', -- Dữ liệu hiển thị (nội dung rút gọn, tên người gửi...)
+    notification_read_at TIMESTAMPTZ, -- NULL = chưa đọc
+    notification_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_notification_user FOREIGN KEY (user_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_notification_room FOREIGN KEY (room_id) REFERENCES rooms (room_id) ON DELETE CASCADE,
+    CONSTRAINT fk_notification_message FOREIGN KEY (message_id) REFERENCES messages (message_id) ON DELETE CASCADE,
+    CONSTRAINT fk_notification_actor FOREIGN KEY (actor_uuid) REFERENCES users (user_uuid) ON DELETE SET NULL,
+    -- Hiện chỉ có thông báo mention, thêm loại mới cùng với tính năng tương ứng
+    CONSTRAINT chk_notification_type CHECK (notification_type IN ('mention'))
+);
+
+-- Danh sách thông báo mới nhất trước theo user (keyset pagination)
+CREATE INDEX idx_notifications_user_id ON notifications (user_uuid, notification_id DESC);
+
+-- Đếm thông báo chưa đọc
+CREATE INDEX idx_notifications_unread ON notifications (user_uuid)
+WHERE
+    notification_read_at IS NULL;
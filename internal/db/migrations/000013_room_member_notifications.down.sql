@@ -0,0 +1,3 @@
+ALTER TABLE room_members
+DROP COLUMN IF EXISTS member_muted_until,
+DROP COLUMN IF EXISTS member_notification_level;
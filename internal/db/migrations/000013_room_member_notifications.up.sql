@@ -0,0 +1,7 @@
+-- Tùy chọn thông báo của từng thành viên theo phòng
+-- all: mọi tin nhắn, mentions: chỉ khi được nhắc, none: không thông báo
+ALTER TABLE room_members
+ADD COLUMN member_notification_level VARCHAR(10) NOT NULL DEFAULT 'all' CHECK (
+    member_notification_level IN ('all', 'mentions', 'none')
+),
+ADD COLUMN member_muted_until TIMESTAMPTZ; -- Tắt thông báo tới thời điểm này (NULL = không tắt)
@@ -0,0 +1,19 @@
+-- Thiết bị nhận push của user (Web Push subscription, FCM/APNs device token)
+CREATE TABLE push_devices (
+    device_id BIGSERIAL PRIMARY KEY,
+    user_uuid UUID NOT NULL,
+    device_platform VARCHAR(10) NOT NULL, -- webpush | fcm | apns
+    device_token TEXT NOT NULL, -- Web Push: endpoint của subscription, FCM/APNs: device token
+    device_p256dh TEXT, -- Web Push: public key của subscription
+    device_auth TEXT, -- Web Push: auth secret của subscription
+    device_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    device_last_used_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_push_device_user FOREIGN KEY (user_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE,
+    CONSTRAINT chk_push_platform CHECK (
+        device_platform IN ('webpush', 'fcm', 'apns')
+    ),
+    -- Một token chỉ thuộc về một user (đăng nhập tài khoản khác trên cùng thiết bị sẽ chuyển token)
+    CONSTRAINT uq_push_device_token UNIQUE (device_platform, device_token)
+);
+
+CREATE INDEX idx_push_devices_user ON push_devices (user_uuid);
@@ -0,0 +1,4 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS user_last_seen_at,
+DROP COLUMN IF EXISTS user_digest_last_sent_at,
+DROP COLUMN IF EXISTS user_digest_frequency;
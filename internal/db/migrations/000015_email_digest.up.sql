@@ -0,0 +1,8 @@
+-- Email tóm tắt (digest) các mention và tin nhắn riêng chưa đọc khi user offline
+-- never: tắt, hourly/daily/weekly: tối đa một email mỗi giờ/ngày/tuần
+ALTER TABLE users
+ADD COLUMN user_digest_frequency VARCHAR(10) NOT NULL DEFAULT 'daily' CHECK (
+    user_digest_frequency IN ('never', 'hourly', 'daily', 'weekly')
+),
+ADD COLUMN user_digest_last_sent_at TIMESTAMPTZ, -- Lần gửi digest gần nhất
+ADD COLUMN user_last_seen_at TIMESTAMPTZ; -- Lần cuối user có kết nối WebSocket (NULL = chưa từng kết nối)
@@ -0,0 +1,19 @@
+-- Xác minh email khi đăng ký
+ALTER TABLE users ADD COLUMN user_email_verified_at TIMESTAMPTZ; -- NULL = chưa xác minh
+
+-- User đã có trước khi bật xác minh được coi là đã xác minh
+UPDATE users SET user_email_verified_at = user_created_at;
+
+-- Token xác minh email: link gửi qua mail chứa token_id kèm chữ ký HMAC, mỗi token chỉ dùng được một lần
+CREATE TABLE email_verification_tokens (
+    token_id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_uuid UUID NOT NULL,
+    token_email VARCHAR(100) NOT NULL, -- Email tại thời điểm gửi, token hết hiệu lực nếu user đổi email
+    token_expires_at TIMESTAMPTZ NOT NULL,
+    token_used_at TIMESTAMPTZ,
+    token_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_email_verification_user FOREIGN KEY (user_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE
+);
+
+-- Giới hạn gửi lại theo user
+CREATE INDEX idx_email_verification_tokens_user ON email_verification_tokens (user_uuid, token_created_at DESC);
@@ -0,0 +1,16 @@
+-- Thời điểm đổi mật khẩu gần nhất
+ALTER TABLE users ADD COLUMN user_password_changed_at TIMESTAMPTZ;
+
+-- Token đặt lại mật khẩu: chỉ lưu SHA-256 của token gửi qua mail, mỗi token chỉ dùng được một lần
+CREATE TABLE password_reset_tokens (
+    token_id UUID PRIMARY KEY DEFAULT gen_random_uuid (),
+    user_uuid UUID NOT NULL,
+    token_hash VARCHAR(64) NOT NULL UNIQUE,
+    token_expires_at TIMESTAMPTZ NOT NULL,
+    token_used_at TIMESTAMPTZ,
+    token_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT fk_password_reset_user FOREIGN KEY (user_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE
+);
+
+-- Giới hạn số lần yêu cầu theo user
+CREATE INDEX idx_password_reset_tokens_user ON password_reset_tokens (user_uuid, token_created_at DESC);
@@ -0,0 +1,5 @@
+ALTER TABLE users
+DROP COLUMN IF EXISTS user_avatar_url,
+DROP COLUMN IF EXISTS user_bio,
+DROP COLUMN IF EXISTS user_timezone,
+DROP COLUMN IF EXISTS user_locale;
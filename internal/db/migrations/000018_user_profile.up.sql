@@ -0,0 +1,6 @@
+-- Thông tin hồ sơ người dùng có thể chỉnh sửa
+ALTER TABLE users
+ADD COLUMN user_avatar_url VARCHAR(500),
+ADD COLUMN user_bio VARCHAR(500),
+ADD COLUMN user_timezone VARCHAR(64) NOT NULL DEFAULT 'UTC', -- Tên múi giờ IANA, vd: Asia/Ho_Chi_Minh
+ADD COLUMN user_locale VARCHAR(35) NOT NULL DEFAULT 'en'; -- BCP 47, vd: vi-VN
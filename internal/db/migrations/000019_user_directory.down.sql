@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_users_email_trgm;
+
+DROP INDEX IF EXISTS idx_users_fullname_trgm;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS user_discoverable,
+DROP COLUMN IF EXISTS user_deactivated_at;
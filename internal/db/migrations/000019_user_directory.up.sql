@@ -0,0 +1,11 @@
+-- Danh bạ người dùng: tìm theo tiền tố / trigram trên tên và email
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE users
+ADD COLUMN user_discoverable BOOLEAN NOT NULL DEFAULT TRUE, -- FALSE = không hiện trong danh bạ
+ADD COLUMN user_deactivated_at TIMESTAMPTZ; -- Tài khoản đã ngừng hoạt động không hiện trong danh bạ
+
+-- GIN trigram hỗ trợ cả LIKE 'abc%', LIKE '% abc%' và toán tử %
+CREATE INDEX idx_users_fullname_trgm ON users USING GIN (lower(user_fullname) gin_trgm_ops);
+
+CREATE INDEX idx_users_email_trgm ON users USING GIN (lower(user_email) gin_trgm_ops);
@@ -0,0 +1,13 @@
+-- Chặn người dùng: ẩn tin nhắn, mention, thông báo của người bị chặn và không cho nhắn tin riêng giữa hai người
+CREATE TABLE user_blocks (
+    blocker_uuid UUID NOT NULL,
+    blocked_uuid UUID NOT NULL,
+    block_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_user_blocks PRIMARY KEY (blocker_uuid, blocked_uuid),
+    CONSTRAINT fk_user_blocks_blocker FOREIGN KEY (blocker_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_user_blocks_blocked FOREIGN KEY (blocked_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE,
+    CONSTRAINT chk_user_blocks_self CHECK (blocker_uuid <> blocked_uuid)
+);
+
+-- Tìm những người đã chặn một user (lọc người nhận khi user đó gửi tin)
+CREATE INDEX idx_user_blocks_blocked ON user_blocks (blocked_uuid);
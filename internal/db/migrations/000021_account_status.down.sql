@@ -0,0 +1,10 @@
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS fk_user_msg;
+
+ALTER TABLE messages
+ADD CONSTRAINT fk_user_msg FOREIGN KEY (user_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE;
+
+ALTER TABLE users
+DROP COLUMN IF EXISTS user_deleted_at,
+DROP COLUMN IF EXISTS user_suspension_reason,
+DROP COLUMN IF EXISTS user_suspended_until,
+DROP COLUMN IF EXISTS user_suspended_at;
@@ -0,0 +1,13 @@
+-- Trạng thái tài khoản (user_deactivated_at đã có từ 000019):
+-- tạm khóa có thời hạn hoặc đến khi admin mở, và thời điểm tài khoản bị xóa (ẩn danh hóa)
+ALTER TABLE users
+ADD COLUMN user_suspended_at TIMESTAMPTZ,
+ADD COLUMN user_suspended_until TIMESTAMPTZ, -- NULL = khóa đến khi admin mở
+ADD COLUMN user_suspension_reason VARCHAR(500),
+ADD COLUMN user_deleted_at TIMESTAMPTZ;
+
+-- Xóa tài khoản là ẩn danh hóa, không xóa dòng users. Chặn xóa cứng để tin nhắn không bị xóa theo
+ALTER TABLE messages DROP CONSTRAINT IF EXISTS fk_user_msg;
+
+ALTER TABLE messages
+ADD CONSTRAINT fk_user_msg FOREIGN KEY (user_uuid) REFERENCES users (user_uuid) ON DELETE RESTRICT;
@@ -0,0 +1,3 @@
+DROP INDEX IF EXISTS idx_messages_user_id;
+
+DROP TABLE IF EXISTS data_exports;
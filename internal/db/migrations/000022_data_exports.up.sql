@@ -0,0 +1,40 @@
+-- Xuất dữ liệu cá nhân: job chạy nền tạo file ZIP (hồ sơ, phòng, tin nhắn, file đính kèm), tải qua link có thời hạn
+CREATE TABLE data_exports (
+    export_id BIGSERIAL PRIMARY KEY,
+    user_uuid UUID NOT NULL,
+    requested_by_uuid UUID, -- admin yêu cầu thay cho user, NULL = user tự yêu cầu
+    export_status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    storage_key VARCHAR(500),
+    size_bytes BIGINT,
+    export_error VARCHAR(500),
+    export_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    export_started_at TIMESTAMPTZ,
+    export_completed_at TIMESTAMPTZ,
+    export_expires_at TIMESTAMPTZ,
+    CONSTRAINT fk_data_exports_user FOREIGN KEY (user_uuid) REFERENCES users (user_uuid) ON DELETE CASCADE,
+    CONSTRAINT fk_data_exports_requested_by FOREIGN KEY (requested_by_uuid) REFERENCES users (user_uuid) ON DELETE SET NULL,
+    CONSTRAINT chk_data_exports_status CHECK (
+        export_status IN (
+            'pending',
+            'processing',
+            'completed',
+            'failed',
+            'expired'
+        )
+    )
+);
+
+CREATE INDEX idx_data_exports_user ON data_exports (user_uuid, export_id DESC);
+
+-- Mỗi user chỉ có một job đang chờ hoặc đang chạy
+CREATE UNIQUE INDEX uq_data_exports_active ON data_exports (user_uuid)
+WHERE
+    export_status IN ('pending', 'processing');
+
+-- Tìm các file đã hết hạn để xóa khỏi storage
+CREATE INDEX idx_data_exports_expires ON data_exports (export_expires_at)
+WHERE
+    export_status = 'completed';
+
+-- Xuất toàn bộ tin nhắn của một user theo thứ tự
+CREATE INDEX idx_messages_user_id ON messages (user_uuid, message_id);
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_messages_room_created_at_id;
+
+DROP INDEX IF EXISTS uq_messages_external_id;
+
+ALTER TABLE messages DROP COLUMN IF EXISTS message_external_id;
+
+DROP TABLE IF EXISTS imported_rooms;
@@ -0,0 +1,28 @@
+-- Nhập lịch sử từ Slack/Discord: ghi nhớ kênh gốc của mỗi phòng và id gốc của mỗi tin nhắn
+-- để chạy lại cùng một file export không tạo phòng hay tin nhắn trùng
+CREATE TABLE imported_rooms (
+    import_source VARCHAR(20) NOT NULL,
+    external_id VARCHAR(100) NOT NULL,
+    room_id BIGINT NOT NULL,
+    imported_room_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    CONSTRAINT pk_imported_rooms PRIMARY KEY (import_source, external_id),
+    CONSTRAINT fk_imported_rooms_room FOREIGN KEY (room_id) REFERENCES rooms (room_id) ON DELETE CASCADE,
+    CONSTRAINT chk_imported_rooms_source CHECK (
+        import_source IN ('slack', 'discord')
+    )
+);
+
+ALTER TABLE messages ADD COLUMN message_external_id VARCHAR(100);
+
+-- Tin nhắn gửi trong app có message_external_id = NULL nên không bị ràng buộc
+CREATE UNIQUE INDEX uq_messages_external_id ON messages (room_id, message_external_id)
+WHERE
+    message_external_id IS NOT NULL;
+
+-- Tin nhắn import giữ thời điểm gửi gốc nên message_id không còn theo thứ tự thời gian,
+-- xuất lịch sử phòng đọc theo (message_created_at, message_id)
+CREATE INDEX idx_messages_room_created_at_id ON messages (
+    room_id,
+    message_created_at,
+    message_id
+);
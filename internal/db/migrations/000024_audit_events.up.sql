@@ -0,0 +1,39 @@
+-- Audit log cho các hành động bảo mật và kiểm duyệt (đăng nhập, khóa/xóa tài khoản, xóa phòng, chuyển quyền...)
+-- Chỉ được thêm, không sửa/xóa. actor_uuid không có khóa ngoại để bản ghi còn nguyên khi user bị xóa
+CREATE TABLE audit_events (
+    audit_id BIGSERIAL PRIMARY KEY,
+    actor_uuid UUID, -- người thực hiện, NULL khi không xác định (vd: đăng nhập sai với email không tồn tại)
+    audit_action VARCHAR(50) NOT NULL,
+    target_type VARCHAR(20),
+    target_id VARCHAR(100),
+    audit_ip VARCHAR(45),
+    audit_user_agent VARCHAR(500),
+    audit_metadata JSONB NOT NULL DEFAULT '{}',
+    audit_created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_audit_events_actor ON audit_events (actor_uuid, audit_id DESC);
+
+CREATE INDEX idx_audit_events_action ON audit_events (audit_action, audit_id DESC);
+
+CREATE INDEX idx_audit_events_target ON audit_events (target_type, target_id, audit_id DESC);
+
+CREATE INDEX idx_audit_events_created_at ON audit_events (audit_created_at);
+
+-- Chặn UPDATE/DELETE/TRUNCATE để log không bị sửa từ ứng dụng
+CREATE OR REPLACE FUNCTION prevent_audit_event_changes()
+RETURNS TRIGGER AS $$
+BEGIN
+    RAISE EXCEPTION 'audit_events is append-only';
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER audit_events_append_only
+BEFORE UPDATE OR DELETE ON audit_events
+FOR EACH ROW
+EXECUTE FUNCTION prevent_audit_event_changes();
+
+CREATE TRIGGER audit_events_no_truncate
+BEFORE TRUNCATE ON audit_events
+FOR EACH STATEMENT
+EXECUTE FUNCTION prevent_audit_event_changes();
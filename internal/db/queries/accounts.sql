@@ -0,0 +1,148 @@
+-- name: SuspendUser :one
+UPDATE users
+SET
+    user_suspended_at = NOW(),
+    user_suspended_until = sqlc.narg('suspended_until'),
+    user_suspension_reason = sqlc.narg('suspension_reason'),
+    user_updated_at = NOW()
+WHERE
+    user_uuid = sqlc.arg('user_uuid')
+    AND user_deleted_at IS NULL RETURNING *;
+
+-- name: UnsuspendUser :one
+UPDATE users
+SET
+    user_suspended_at = NULL,
+    user_suspended_until = NULL,
+    user_suspension_reason = NULL,
+    user_updated_at = NOW()
+WHERE
+    user_uuid = $1
+    AND user_deleted_at IS NULL RETURNING *;
+
+-- name: SetUserDeactivated :one
+-- Vô hiệu hóa giữ nguyên thời điểm ban đầu nếu đã vô hiệu hóa trước đó
+UPDATE users
+SET
+    user_deactivated_at = CASE
+        WHEN sqlc.arg('deactivated')::bool THEN COALESCE(user_deactivated_at, NOW())
+        ELSE NULL
+    END,
+    user_updated_at = NOW()
+WHERE
+    user_uuid = sqlc.arg('user_uuid')
+    AND user_deleted_at IS NULL RETURNING *;
+
+-- name: AnonymizeUser :one
+-- Xóa dữ liệu cá nhân nhưng giữ lại dòng users để tin nhắn của user vẫn còn (hiển thị "Deleted user").
+-- Phòng do user làm chủ được chuyển cho Admin (nếu không có thì thành viên) tham gia sớm nhất.
+-- Chạy trong một câu lệnh để không bị dừng giữa chừng.
+-- Trả về thêm các phòng đã chuyển chủ và chủ mới (cùng thứ tự) để ghi audit log.
+WITH
+    successors AS (
+        SELECT DISTINCT
+            ON (rm.room_id) rm.room_id,
+            rm.user_uuid
+        FROM
+            room_members rm
+            JOIN room_members owner ON owner.room_id = rm.room_id
+            AND owner.user_uuid = sqlc.arg('user_uuid')
+            AND owner.member_role = 'Owner'
+        WHERE
+            rm.user_uuid <> sqlc.arg('user_uuid')
+        ORDER BY rm.room_id, rm.member_role = 'Admin' DESC, rm.room_member_created_at
+    ),
+    promoted AS (
+        UPDATE room_members rm
+        SET
+            member_role = 'Owner',
+            room_member_updated_at = NOW()
+        FROM successors s
+        WHERE
+            rm.room_id = s.room_id
+            AND rm.user_uuid = s.user_uuid RETURNING rm.room_id,
+            rm.user_uuid
+    ),
+    left_rooms AS (
+        DELETE FROM room_members
+        WHERE
+            user_uuid = sqlc.arg('user_uuid')
+    ),
+    deleted_mentions AS (
+        DELETE FROM message_mentions
+        WHERE
+            mentioned_user_uuid = sqlc.arg('user_uuid')
+    ),
+    deleted_notifications AS (
+        DELETE FROM notifications
+        WHERE
+            user_uuid = sqlc.arg('user_uuid')
+    ),
+    anonymized_notifications AS (
+        UPDATE notifications
+        SET
+            notification_data = notification_data || jsonb_build_object('actor_fullname', 'Deleted user')
+        WHERE
+            actor_uuid = sqlc.arg('user_uuid')
+            AND notification_data ? 'actor_fullname'
+    ),
+    deleted_devices AS (
+        DELETE FROM push_devices
+        WHERE
+            user_uuid = sqlc.arg('user_uuid')
+    ),
+    deleted_blocks AS (
+        DELETE FROM user_blocks
+        WHERE
+            blocker_uuid = sqlc.arg('user_uuid')
+            OR blocked_uuid = sqlc.arg('user_uuid')
+    ),
+    deleted_verification_tokens AS (
+        DELETE FROM email_verification_tokens
+        WHERE
+            user_uuid = sqlc.arg('user_uuid')
+    ),
+    deleted_reset_tokens AS (
+        DELETE FROM password_reset_tokens
+        WHERE
+            user_uuid = sqlc.arg('user_uuid')
+    ),
+    -- File xuất dữ liệu hết hạn ngay, job dọn dẹp sẽ xóa khỏi storage
+    expired_exports AS (
+        UPDATE data_exports
+        SET
+            export_expires_at = NOW()
+        WHERE
+            user_uuid = sqlc.arg('user_uuid')
+            AND export_status = 'completed'
+    )
+UPDATE users
+SET
+    user_email = 'deleted-' || user_uuid || '@deleted.invalid',
+    user_password = '',
+    user_fullname = 'Deleted user',
+    user_avatar_url = NULL,
+    user_bio = NULL,
+    user_timezone = DEFAULT,
+    user_locale = DEFAULT,
+    user_discoverable = FALSE,
+    user_digest_frequency = 'never',
+    user_email_verified_at = NULL,
+    user_last_seen_at = NULL,
+    user_suspended_at = NULL,
+    user_suspended_until = NULL,
+    user_suspension_reason = NULL,
+    user_deactivated_at = COALESCE(user_deactivated_at, NOW()),
+    user_deleted_at = NOW(),
+    user_updated_at = NOW()
+WHERE
+    user_uuid = sqlc.arg('user_uuid')
+    AND user_deleted_at IS NULL RETURNING *,
+    (
+        SELECT COALESCE(array_agg(p.room_id ORDER BY p.room_id), '{}')
+        FROM promoted p
+    )::bigint[] AS transferred_room_ids,
+    (
+        SELECT COALESCE(array_agg(p.user_uuid ORDER BY p.room_id), '{}')
+        FROM promoted p
+    )::uuid[] AS new_owner_uuids;
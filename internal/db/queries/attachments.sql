@@ -0,0 +1,35 @@
+-- name: CreateAttachment :one
+INSERT INTO
+    message_attachments (
+        message_id,
+        room_id,
+        uploader_uuid,
+        storage_key,
+        file_name,
+        content_type,
+        size_bytes
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING *;
+
+-- name: GetAttachmentByID :one
+SELECT * FROM message_attachments WHERE attachment_id = $1;
+
+-- name: ListAttachmentsByMessageIDs :many
+SELECT *
+FROM message_attachments
+WHERE
+    message_id = ANY (sqlc.arg('message_ids')::bigint[])
+ORDER BY attachment_id;
+
+-- name: MarkAttachmentProcessed :one
+UPDATE message_attachments
+SET
+    image_width = sqlc.narg('image_width'),
+    image_height = sqlc.narg('image_height'),
+    thumbnail_key = sqlc.narg('thumbnail_key'),
+    thumbnail_content_type = sqlc.narg('thumbnail_content_type'),
+    thumbnail_width = sqlc.narg('thumbnail_width'),
+    thumbnail_height = sqlc.narg('thumbnail_height'),
+    attachment_processed_at = NOW()
+WHERE
+    attachment_id = sqlc.arg('attachment_id') RETURNING *;
@@ -0,0 +1,48 @@
+-- name: CreateAuditEvent :exec
+INSERT INTO
+    audit_events (
+        actor_uuid,
+        audit_action,
+        target_type,
+        target_id,
+        audit_ip,
+        audit_user_agent,
+        audit_metadata
+    )
+VALUES ($1, $2, $3, $4, $5, $6, $7);
+
+-- name: ListAuditEvents :many
+-- Lọc audit log (mọi điều kiện đều tùy chọn), mới nhất trước, phân trang theo audit_id
+SELECT a.*, u.user_email AS actor_email, u.user_fullname AS actor_fullname
+FROM audit_events a
+    LEFT JOIN users u ON u.user_uuid = a.actor_uuid
+WHERE (
+        sqlc.narg('actor_uuid')::text IS NULL
+        OR a.actor_uuid = sqlc.narg('actor_uuid')::uuid
+    )
+    AND (
+        sqlc.narg('audit_action')::text IS NULL
+        OR a.audit_action = sqlc.narg('audit_action')
+    )
+    AND (
+        sqlc.narg('target_type')::text IS NULL
+        OR a.target_type = sqlc.narg('target_type')
+    )
+    AND (
+        sqlc.narg('target_id')::text IS NULL
+        OR a.target_id = sqlc.narg('target_id')
+    )
+    AND (
+        sqlc.narg('from_time')::timestamptz IS NULL
+        OR a.audit_created_at >= sqlc.narg('from_time')
+    )
+    AND (
+        sqlc.narg('to_time')::timestamptz IS NULL
+        OR a.audit_created_at < sqlc.narg('to_time')
+    )
+    AND (
+        sqlc.narg('before_id')::bigint IS NULL
+        OR a.audit_id < sqlc.narg('before_id')
+    )
+ORDER BY a.audit_id DESC
+LIMIT sqlc.arg('limit');
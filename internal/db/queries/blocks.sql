@@ -0,0 +1,45 @@
+-- name: CreateUserBlock :one
+-- Chặn lại người đã chặn không báo lỗi, giữ nguyên thời điểm chặn ban đầu
+INSERT INTO
+    user_blocks (blocker_uuid, blocked_uuid)
+VALUES ($1, $2)
+ON CONFLICT (blocker_uuid, blocked_uuid) DO
+UPDATE
+SET
+    block_created_at = user_blocks.block_created_at RETURNING *;
+
+-- name: DeleteUserBlock :execrows
+DELETE FROM user_blocks WHERE blocker_uuid = $1 AND blocked_uuid = $2;
+
+-- name: ListUserBlocks :many
+-- Danh sách người user đã chặn kèm thông tin người bị chặn, mới chặn trước
+SELECT users.*, b.block_created_at
+FROM user_blocks b
+    JOIN users ON users.user_uuid = b.blocked_uuid
+WHERE
+    b.blocker_uuid = $1
+ORDER BY b.block_created_at DESC;
+
+-- name: ListBlockedUserIDs :many
+SELECT blocked_uuid FROM user_blocks WHERE blocker_uuid = $1;
+
+-- name: ListBlockerIDs :many
+-- Những người đã chặn user (không nhận tin nhắn, mention, thông báo từ user)
+SELECT blocker_uuid FROM user_blocks WHERE blocked_uuid = $1;
+
+-- name: HasBlockWithRoomMembers :one
+-- Có lượt chặn theo bất kỳ chiều nào giữa user và một thành viên khác của phòng
+SELECT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+            JOIN room_members rm ON rm.room_id = sqlc.arg('room_id')
+            AND rm.user_uuid <> sqlc.arg('user_uuid')
+        WHERE (
+                b.blocker_uuid = sqlc.arg('user_uuid')
+                AND b.blocked_uuid = rm.user_uuid
+            )
+            OR (
+                b.blocked_uuid = sqlc.arg('user_uuid')
+                AND b.blocker_uuid = rm.user_uuid
+            )
+    );
@@ -0,0 +1,104 @@
+-- name: ListDigestRecipients :many
+-- User đang hoạt động bật digest, đã offline từ trước offline_before và đến hạn gửi theo tần suất
+SELECT *
+FROM users
+WHERE
+    user_digest_frequency <> 'never'
+    AND user_deactivated_at IS NULL
+    AND COALESCE(user_last_seen_at, user_created_at) < sqlc.arg('offline_before')
+    AND (
+        user_digest_last_sent_at IS NULL
+        OR user_digest_last_sent_at < NOW() - CASE user_digest_frequency
+            WHEN 'hourly' THEN INTERVAL '1 hour'
+            WHEN 'daily' THEN INTERVAL '1 day'
+            ELSE INTERVAL '7 days'
+        END
+    )
+    AND user_uuid > sqlc.arg('after_uuid')
+ORDER BY user_uuid
+LIMIT sqlc.arg('limit');
+
+-- name: ListDigestMentions :many
+-- Mention chưa đọc từ sau thời điểm since, bỏ qua phòng đã tắt thông báo và người gửi đã bị chặn
+SELECT
+    mm.message_id,
+    mm.room_id,
+    mm.mention_type,
+    m.content,
+    m.message_created_at,
+    u.user_fullname AS sender_fullname,
+    r.room_name,
+    r.room_is_direct_chat
+FROM
+    message_mentions mm
+    JOIN messages m ON m.message_id = mm.message_id
+    JOIN users u ON u.user_uuid = m.user_uuid
+    JOIN rooms r ON r.room_id = mm.room_id
+    JOIN room_members rm ON rm.room_id = mm.room_id
+    AND rm.user_uuid = mm.mentioned_user_uuid
+WHERE
+    mm.mentioned_user_uuid = sqlc.arg('user_uuid')
+    AND mm.mention_read_at IS NULL
+    AND mm.mention_created_at > sqlc.arg('since')
+    AND rm.member_notification_level <> 'none'
+    AND (
+        rm.member_muted_until IS NULL
+        OR rm.member_muted_until <= NOW()
+    )
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = mm.mentioned_user_uuid
+            AND b.blocked_uuid = m.user_uuid
+    )
+ORDER BY mm.mention_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListDigestDirectMessages :many
+-- Tin nhắn riêng người khác (không bị user chặn) gửi cho user từ sau thời điểm since
+SELECT
+    m.message_id,
+    m.room_id,
+    m.content,
+    m.message_created_at,
+    u.user_fullname AS sender_fullname
+FROM
+    messages m
+    JOIN rooms r ON r.room_id = m.room_id
+    AND r.room_is_direct_chat
+    JOIN room_members rm ON rm.room_id = m.room_id
+    AND rm.user_uuid = sqlc.arg('user_uuid')
+    JOIN users u ON u.user_uuid = m.user_uuid
+WHERE
+    m.user_uuid <> sqlc.arg('user_uuid')
+    AND m.message_type = 'text'
+    AND m.message_created_at > sqlc.arg('since')
+    AND rm.member_notification_level = 'all'
+    AND (
+        rm.member_muted_until IS NULL
+        OR rm.member_muted_until <= NOW()
+    )
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = sqlc.arg('user_uuid')
+            AND b.blocked_uuid = m.user_uuid
+    )
+ORDER BY m.message_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: MarkDigestSent :exec
+UPDATE users SET user_digest_last_sent_at = NOW() WHERE user_uuid = $1;
+
+-- name: UpdateUserDigestFrequency :one
+UPDATE users
+SET
+    user_digest_frequency = $2,
+    user_updated_at = NOW()
+WHERE
+    user_uuid = $1 RETURNING *;
+
+-- name: UpdateUserLastSeen :exec
+UPDATE users SET user_last_seen_at = NOW() WHERE user_uuid = $1;
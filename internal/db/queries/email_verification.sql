@@ -0,0 +1,35 @@
+-- name: CreateEmailVerificationToken :one
+INSERT INTO
+    email_verification_tokens (
+        user_uuid,
+        token_email,
+        token_expires_at
+    )
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: GetEmailVerificationToken :one
+SELECT * FROM email_verification_tokens WHERE token_id = $1;
+
+-- name: UseEmailVerificationToken :execrows
+UPDATE email_verification_tokens
+SET
+    token_used_at = NOW()
+WHERE
+    token_id = $1
+    AND token_used_at IS NULL;
+
+-- name: CountEmailVerificationTokensSince :one
+SELECT COUNT(*)
+FROM email_verification_tokens
+WHERE
+    user_uuid = $1
+    AND token_created_at > $2;
+
+-- name: MarkUserEmailVerified :one
+UPDATE users
+SET
+    user_email_verified_at = COALESCE(user_email_verified_at, NOW()),
+    user_updated_at = NOW()
+WHERE
+    user_uuid = $1
+    AND user_email = $2 RETURNING *;
@@ -0,0 +1,81 @@
+-- name: CreateDataExport :one
+INSERT INTO
+    data_exports (user_uuid, requested_by_uuid)
+VALUES ($1, $2) RETURNING *;
+
+-- name: GetDataExport :one
+SELECT * FROM data_exports WHERE export_id = $1;
+
+-- name: GetActiveDataExport :one
+-- Mỗi user chỉ có một job đang chờ hoặc đang chạy
+SELECT *
+FROM data_exports
+WHERE
+    user_uuid = $1
+    AND export_status IN ('pending', 'processing')
+ORDER BY export_id DESC
+LIMIT 1;
+
+-- name: ListUserDataExports :many
+SELECT *
+FROM data_exports
+WHERE
+    user_uuid = $1
+ORDER BY export_id DESC
+LIMIT $2;
+
+-- name: StartDataExport :one
+-- Nhận job, không có dòng trả về nghĩa là job đã được worker khác nhận
+UPDATE data_exports
+SET
+    export_status = 'processing',
+    export_started_at = NOW()
+WHERE
+    export_id = $1
+    AND export_status = 'pending' RETURNING *;
+
+-- name: CompleteDataExport :one
+UPDATE data_exports
+SET
+    export_status = 'completed',
+    storage_key = sqlc.arg('storage_key'),
+    size_bytes = sqlc.arg('size_bytes'),
+    export_completed_at = NOW(),
+    export_expires_at = sqlc.arg('expires_at')
+WHERE
+    export_id = sqlc.arg('export_id') RETURNING *;
+
+-- name: FailDataExport :one
+UPDATE data_exports
+SET
+    export_status = 'failed',
+    export_error = sqlc.arg('export_error'),
+    export_completed_at = NOW()
+WHERE
+    export_id = sqlc.arg('export_id') RETURNING *;
+
+-- name: ResumeDataExports :many
+-- Khi khởi động lại, các job đang chạy dở được đưa về hàng đợi
+UPDATE data_exports
+SET
+    export_status = 'pending',
+    export_started_at = NULL
+WHERE
+    export_status IN ('pending', 'processing') RETURNING *;
+
+-- name: ListExpiredDataExports :many
+SELECT *
+FROM data_exports
+WHERE
+    export_status = 'completed'
+    AND export_expires_at <= NOW()
+ORDER BY export_expires_at
+LIMIT $1;
+
+-- name: ExpireDataExport :exec
+UPDATE data_exports
+SET
+    export_status = 'expired',
+    storage_key = NULL
+WHERE
+    export_id = $1;
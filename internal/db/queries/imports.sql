@@ -0,0 +1,40 @@
+-- name: GetImportedRoom :one
+SELECT *
+FROM imported_rooms
+WHERE
+    import_source = $1
+    AND external_id = $2;
+
+-- name: CreateImportedRoom :one
+INSERT INTO
+    imported_rooms (
+        import_source,
+        external_id,
+        room_id
+    )
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: AddImportedRoomMember :exec
+INSERT INTO
+    room_members (user_uuid, room_id, member_role)
+VALUES ($1, $2, $3)
+ON CONFLICT (user_uuid, room_id) DO NOTHING;
+
+-- name: ListExistingExternalMessageIDs :many
+SELECT message_external_id::text
+FROM messages
+WHERE
+    room_id = sqlc.arg('room_id')
+    AND message_external_id = ANY (sqlc.arg('external_ids')::text[]);
+
+-- name: CopyImportedMessages :copyfrom
+INSERT INTO
+    messages (
+        room_id,
+        user_uuid,
+        content,
+        message_type,
+        message_created_at,
+        message_external_id
+    )
+VALUES ($1, $2, $3, $4, $5, $6);
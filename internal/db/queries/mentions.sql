@@ -0,0 +1,22 @@
+-- name: CreateMessageMentions :many
+INSERT INTO
+    message_mentions (
+        message_id,
+        room_id,
+        mentioned_user_uuid,
+        mention_type
+    )
+SELECT sqlc.arg('message_id')::bigint, sqlc.arg('room_id')::bigint, m.user_uuid, m.mention_type
+FROM unnest(
+        sqlc.arg('user_uuids')::uuid[], sqlc.arg('mention_types')::text[]
+    ) AS m (user_uuid, mention_type)
+ON CONFLICT (message_id, mentioned_user_uuid) DO NOTHING RETURNING *;
+
+-- name: MarkRoomMentionsRead :execrows
+UPDATE message_mentions
+SET
+    mention_read_at = NOW()
+WHERE
+    mentioned_user_uuid = $1
+    AND room_id = $2
+    AND mention_read_at IS NULL;
@@ -1,17 +1,236 @@
 -- name: CreateMessage :one
 INSERT INTO
     messages (room_id, user_uuid, content)
-VALUES ($1, $2, $3) RETURNING *;
+VALUES ($1, $2, $3)
+RETURNING
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id;
 
--- name: GetRoomMessages :many
-SELECT *
+-- name: CreateSystemMessage :one
+INSERT INTO
+    messages (room_id, user_uuid, content, message_type)
+VALUES ($1, $2, $3, 'system')
+RETURNING
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id;
+
+-- name: LockUserRoomPosting :exec
+-- Khóa theo (phòng, user) đến hết transaction: các lần gửi đồng thời (REST và websocket) của cùng user phải chờ nhau
+SELECT pg_advisory_xact_lock(
+        hashtextextended(format('%s:%s', sqlc.arg('room_id')::bigint, sqlc.arg('user_uuid')::uuid), 0)
+    );
+
+-- name: GetLastUserMessageTime :one
+SELECT message_created_at
 FROM messages
 WHERE
     room_id = $1
+    AND user_uuid = $2
+    AND message_type = 'text'
 ORDER BY message_created_at DESC
-LIMIT $2
+LIMIT 1;
+
+-- name: GetRoomMessages :many
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    room_id = sqlc.arg('room_id')
+    -- Bỏ tin nhắn của người mà viewer đã chặn (viewer_uuid = NULL: không lọc)
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = sqlc.narg('viewer_uuid')
+            AND b.blocked_uuid = messages.user_uuid
+    )
+ORDER BY message_created_at DESC
+LIMIT sqlc.arg('limit')
 OFFSET
-    $3;
+    sqlc.arg('offset');
+
+-- name: GetRoomMessage :one
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    room_id = $1
+    AND message_id = $2;
+
+-- name: GetRoomMessagesBefore :many
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    room_id = sqlc.arg('room_id')
+    AND (
+        sqlc.narg('before_id')::bigint IS NULL
+        OR message_id < sqlc.narg('before_id')
+    )
+    -- Bỏ tin nhắn của người mà viewer đã chặn (viewer_uuid = NULL: không lọc)
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = sqlc.narg('viewer_uuid')
+            AND b.blocked_uuid = messages.user_uuid
+    )
+ORDER BY message_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: GetRoomMessagesAfter :many
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    room_id = sqlc.arg('room_id')
+    AND message_id > sqlc.arg('message_id')
+    -- Bỏ tin nhắn của người mà viewer đã chặn (viewer_uuid = NULL: không lọc)
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = sqlc.narg('viewer_uuid')
+            AND b.blocked_uuid = messages.user_uuid
+    )
+ORDER BY message_id ASC
+LIMIT sqlc.arg('limit');
 
 -- name: CountRoomMessages :one
-SELECT COUNT(*) FROM messages WHERE room_id = $1;
\ No newline at end of file
+SELECT COUNT(*) FROM messages WHERE room_id = $1;
+
+-- name: SearchMessages :many
+SELECT
+    m.message_id,
+    m.room_id,
+    m.user_uuid,
+    m.content,
+    m.message_type,
+    m.message_created_at,
+    r.room_name,
+    u.user_fullname,
+    -- Escape HTML trước khi highlight để client có thể render snippet an toàn
+    ts_headline(
+        'simple',
+        replace(replace(replace(m.content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+        q.query,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5'
+    )::text AS snippet
+FROM
+    messages m
+    INNER JOIN room_members rm ON rm.room_id = m.room_id
+    AND rm.user_uuid = sqlc.arg('user_uuid')
+    INNER JOIN rooms r ON r.room_id = m.room_id
+    INNER JOIN users u ON u.user_uuid = m.user_uuid
+    CROSS JOIN websearch_to_tsquery('simple', sqlc.arg('query')) AS q (query)
+WHERE
+    m.content_tsv @@ q.query
+    AND m.message_type = 'text'
+    -- Không trả về tin nhắn của người mà user đã chặn
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = sqlc.arg('user_uuid')
+            AND b.blocked_uuid = m.user_uuid
+    )
+    AND (
+        sqlc.narg('room_id')::bigint IS NULL
+        OR m.room_id = sqlc.narg('room_id')
+    )
+    AND (
+        sqlc.narg('sender_uuid')::text IS NULL
+        OR m.user_uuid = sqlc.narg('sender_uuid')::uuid
+    )
+    AND (
+        sqlc.narg('from_time')::timestamptz IS NULL
+        OR m.message_created_at >= sqlc.narg('from_time')
+    )
+    AND (
+        sqlc.narg('to_time')::timestamptz IS NULL
+        OR m.message_created_at < sqlc.narg('to_time')
+    )
+    AND (
+        sqlc.narg('before_id')::bigint IS NULL
+        OR m.message_id < sqlc.narg('before_id')
+    )
+ORDER BY m.message_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: ListUserMessages :many
+-- Tin nhắn do user gửi (xuất dữ liệu cá nhân), phân trang theo message_id
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    user_uuid = sqlc.arg('user_uuid')
+    AND message_type = 'text'
+    AND message_id > sqlc.arg('after_id')
+ORDER BY message_id ASC
+LIMIT sqlc.arg('limit');
+
+-- name: ListRoomMessagesForExport :many
+-- Xuất lịch sử phòng theo thứ tự gửi, kèm tên người gửi.
+-- Phân trang theo (message_created_at, message_id): tin nhắn import giữ thời điểm gửi gốc nhưng có message_id mới
+SELECT
+    m.message_id,
+    m.room_id,
+    m.user_uuid,
+    m.content,
+    m.message_created_at,
+    m.message_type,
+    m.message_external_id,
+    u.user_fullname
+FROM messages m
+    JOIN users u ON u.user_uuid = m.user_uuid
+WHERE
+    m.room_id = sqlc.arg('room_id')
+    AND (
+        sqlc.narg('after_created_at')::timestamptz IS NULL
+        OR (m.message_created_at, m.message_id) > (
+            sqlc.narg('after_created_at')::timestamptz,
+            sqlc.arg('after_id')::bigint
+        )
+    )
+ORDER BY m.message_created_at ASC, m.message_id ASC
+LIMIT sqlc.arg('limit');
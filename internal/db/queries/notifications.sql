@@ -0,0 +1,57 @@
+-- name: CreateNotifications :many
+INSERT INTO
+    notifications (
+        user_uuid,
+        notification_type,
+        room_id,
+        message_id,
+        actor_uuid,
+        notification_data
+    )
+SELECT
+    u.user_uuid,
+    sqlc.arg('notification_type')::varchar,
+    sqlc.narg('room_id')::bigint,
+    sqlc.narg('message_id')::bigint,
+    sqlc.narg('actor_uuid')::text::uuid,
+    sqlc.arg('notification_data')::jsonb
+FROM unnest(sqlc.arg('user_uuids')::uuid[]) AS u (user_uuid) RETURNING *;
+
+-- name: ListNotifications :many
+SELECT *
+FROM notifications
+WHERE
+    user_uuid = sqlc.arg('user_uuid')
+    AND (
+        NOT sqlc.arg('unread_only')::boolean
+        OR notification_read_at IS NULL
+    )
+    AND (
+        sqlc.narg('before_id')::bigint IS NULL
+        OR notification_id < sqlc.narg('before_id')::bigint
+    )
+ORDER BY notification_id DESC
+LIMIT sqlc.arg('limit');
+
+-- name: CountUnreadNotifications :one
+SELECT COUNT(*)
+FROM notifications
+WHERE
+    user_uuid = $1
+    AND notification_read_at IS NULL;
+
+-- name: MarkNotificationRead :one
+UPDATE notifications
+SET
+    notification_read_at = COALESCE(notification_read_at, NOW())
+WHERE
+    notification_id = $1
+    AND user_uuid = $2 RETURNING *;
+
+-- name: MarkAllNotificationsRead :execrows
+UPDATE notifications
+SET
+    notification_read_at = NOW()
+WHERE
+    user_uuid = $1
+    AND notification_read_at IS NULL;
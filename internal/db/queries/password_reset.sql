@@ -0,0 +1,34 @@
+-- name: CreatePasswordResetToken :one
+INSERT INTO
+    password_reset_tokens (
+        user_uuid,
+        token_hash,
+        token_expires_at
+    )
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: GetPasswordResetTokenByHash :one
+SELECT * FROM password_reset_tokens WHERE token_hash = $1;
+
+-- name: UsePasswordResetToken :execrows
+UPDATE password_reset_tokens
+SET
+    token_used_at = NOW()
+WHERE
+    token_id = $1
+    AND token_used_at IS NULL;
+
+-- name: InvalidateUserPasswordResetTokens :exec
+UPDATE password_reset_tokens
+SET
+    token_used_at = NOW()
+WHERE
+    user_uuid = $1
+    AND token_used_at IS NULL;
+
+-- name: CountPasswordResetTokensSince :one
+SELECT COUNT(*)
+FROM password_reset_tokens
+WHERE
+    user_uuid = $1
+    AND token_created_at > $2;
@@ -0,0 +1,36 @@
+-- name: UpsertPushDevice :one
+INSERT INTO
+    push_devices (
+        user_uuid,
+        device_platform,
+        device_token,
+        device_p256dh,
+        device_auth
+    )
+VALUES ($1, $2, $3, $4, $5)
+ON CONFLICT (device_platform, device_token) DO
+UPDATE
+SET
+    user_uuid = EXCLUDED.user_uuid,
+    device_p256dh = EXCLUDED.device_p256dh,
+    device_auth = EXCLUDED.device_auth,
+    device_last_used_at = NOW() RETURNING *;
+
+-- name: ListUserPushDevices :many
+SELECT *
+FROM push_devices
+WHERE
+    user_uuid = $1
+ORDER BY device_id;
+
+-- name: ListPushDevicesByUsers :many
+SELECT *
+FROM push_devices
+WHERE
+    user_uuid = ANY (sqlc.arg('user_uuids')::uuid[]);
+
+-- name: DeletePushDevice :execrows
+DELETE FROM push_devices WHERE device_id = $1 AND user_uuid = $2;
+
+-- name: DeletePushDeviceByID :exec
+DELETE FROM push_devices WHERE device_id = $1;
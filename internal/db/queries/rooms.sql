@@ -13,8 +13,52 @@ INSERT INTO
     room_members (user_uuid, room_id)
 VALUES ($1, $2) RETURNING *;
 
--- name: LeaveRoom :exec
-DELETE FROM room_members WHERE user_uuid = $1 AND room_id = $2;
+-- name: AddRoomMember :one
+INSERT INTO
+    room_members (user_uuid, room_id, member_role)
+VALUES ($1, $2, $3) RETURNING *;
+
+-- name: GetRoomMember :one
+SELECT *
+FROM room_members
+WHERE
+    user_uuid = $1
+    AND room_id = $2;
+
+-- name: TransferRoomOwnership :exec
+UPDATE room_members
+SET
+    member_role = CASE
+        WHEN user_uuid = sqlc.arg('new_owner_uuid') THEN 'Owner'
+        ELSE 'Admin'
+    END
+WHERE
+    room_id = sqlc.arg('room_id')
+    AND user_uuid IN (sqlc.arg('current_owner_uuid'), sqlc.arg('new_owner_uuid'));
+
+-- name: UpdateRoomMemberNotificationSettings :one
+UPDATE room_members
+SET
+    member_notification_level = sqlc.arg('member_notification_level'),
+    member_muted_until = sqlc.narg('member_muted_until')
+WHERE
+    user_uuid = sqlc.arg('user_uuid')
+    AND room_id = sqlc.arg('room_id') RETURNING *;
+
+-- name: ListRoomMemberNotificationSettings :many
+SELECT
+    user_uuid,
+    member_notification_level,
+    member_muted_until
+FROM room_members
+WHERE
+    room_id = $1;
+
+-- name: LeaveRoom :one
+DELETE FROM room_members WHERE user_uuid = $1 AND room_id = $2 RETURNING *;
+
+-- name: GetRoomOwner :one
+SELECT * FROM room_members WHERE room_id = $1 AND member_role = 'Owner' LIMIT 1;
 
 -- name: GetAllRoomsWithMemberCount :many
 SELECT r.*, COUNT(rm.user_uuid) as member_count
@@ -77,21 +121,76 @@ SELECT
     r.room_created_by,
     r.room_created_at,
     r.room_updated_at,
+    r.room_topic,
+    r.room_description,
+    r.room_avatar_url,
+    r.room_archived_at,
+    r.room_posting_permission,
+    r.room_slow_mode_seconds,
+    rm.member_notification_level,
+    rm.member_muted_until,
     -- Last message info with COALESCE to handle NULL
     COALESCE(lm.message_id, 0) as last_message_id,
     COALESCE(lm.content, '') as last_message_content,
     COALESCE(lm.message_created_at, r.room_created_at) as last_message_time,
     COALESCE(lm.user_uuid, '00000000-0000-0000-0000-000000000000'::uuid) as last_sender_uuid,
-    u.user_fullname as last_sender_name
+    u.user_fullname as last_sender_name,
+    (
+        SELECT COUNT(*)
+        FROM message_mentions mm
+        WHERE
+            mm.room_id = r.room_id
+            AND mm.mentioned_user_uuid = rm.user_uuid
+            AND mm.mention_read_at IS NULL
+    ) as mention_count
 FROM rooms r
 INNER JOIN room_members rm ON r.room_id = rm.room_id
 LEFT JOIN LATERAL (
     SELECT m.message_id, m.content, m.message_created_at, m.user_uuid
     FROM messages m 
     WHERE m.room_id = r.room_id 
+    -- Tin nhắn cuối không tính tin của người mà user đã chặn
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = rm.user_uuid
+            AND b.blocked_uuid = m.user_uuid
+    )
     ORDER BY m.message_created_at DESC 
     LIMIT 1
 ) lm ON true
 LEFT JOIN users u ON lm.user_uuid = u.user_uuid
-WHERE rm.user_uuid = $1
-ORDER BY COALESCE(lm.message_created_at, r.room_created_at) DESC;
\ No newline at end of file
+WHERE
+    rm.user_uuid = sqlc.arg('user_uuid')
+    AND (
+        sqlc.arg('include_archived')::boolean
+        OR r.room_archived_at IS NULL
+    )
+ORDER BY COALESCE(lm.message_created_at, r.room_created_at) DESC;
+
+-- name: UpdateRoomSettings :one
+UPDATE rooms
+SET
+    room_name = COALESCE(sqlc.narg('room_name'), room_name),
+    room_topic = COALESCE(sqlc.narg('room_topic'), room_topic),
+    room_description = COALESCE(sqlc.narg('room_description'), room_description),
+    room_avatar_url = COALESCE(sqlc.narg('room_avatar_url'), room_avatar_url),
+    room_posting_permission = COALESCE(sqlc.narg('room_posting_permission'), room_posting_permission),
+    room_slow_mode_seconds = COALESCE(sqlc.narg('room_slow_mode_seconds'), room_slow_mode_seconds)
+WHERE
+    room_id = sqlc.arg('room_id') RETURNING *;
+
+-- name: ArchiveRoom :one
+UPDATE rooms SET room_archived_at = NOW() WHERE room_id = $1 RETURNING *;
+
+-- name: UnarchiveRoom :one
+UPDATE rooms SET room_archived_at = NULL WHERE room_id = $1 RETURNING *;
+
+-- name: ListUserMemberships :many
+SELECT r.*, rm.member_role, rm.room_member_created_at, rm.member_notification_level, rm.member_muted_until
+FROM rooms r
+    JOIN room_members rm ON r.room_id = rm.room_id
+WHERE
+    rm.user_uuid = $1
+ORDER BY rm.room_member_created_at;
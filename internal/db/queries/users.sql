@@ -21,5 +21,86 @@ LIMIT $1
 OFFSET
     $2;
 
--- name: DeleteUser :exec
-DELETE FROM users WHERE user_uuid = $1;
\ No newline at end of file
+-- name: UpdateUserPassword :one
+UPDATE users
+SET
+    user_password = $2,
+    user_password_changed_at = NOW(),
+    user_updated_at = NOW()
+WHERE
+    user_uuid = $1 RETURNING *;
+
+-- name: UpdateUserProfile :one
+-- NULL = giữ nguyên, chuỗi rỗng = xóa avatar/bio
+UPDATE users
+SET
+    user_fullname = COALESCE(sqlc.narg('user_fullname'), user_fullname),
+    user_avatar_url = CASE
+        WHEN sqlc.narg('user_avatar_url')::text IS NULL THEN user_avatar_url
+        ELSE NULLIF(sqlc.narg('user_avatar_url'), '')
+    END,
+    user_bio = CASE
+        WHEN sqlc.narg('user_bio')::text IS NULL THEN user_bio
+        ELSE NULLIF(sqlc.narg('user_bio'), '')
+    END,
+    user_timezone = COALESCE(sqlc.narg('user_timezone'), user_timezone),
+    user_locale = COALESCE(sqlc.narg('user_locale'), user_locale),
+    user_discoverable = COALESCE(sqlc.narg('user_discoverable'), user_discoverable),
+    user_updated_at = NOW()
+WHERE
+    user_uuid = sqlc.arg('user_uuid') RETURNING *;
+
+-- name: ListSharedRoomIDs :many
+-- Các phòng của user có ít nhất một thành viên khác
+SELECT rm.room_id
+FROM room_members rm
+WHERE
+    rm.user_uuid = $1
+    AND EXISTS (
+        SELECT 1
+        FROM room_members other
+        WHERE
+            other.room_id = rm.room_id
+            AND other.user_uuid <> rm.user_uuid
+    )
+ORDER BY rm.room_id;
+
+-- name: SearchUserDirectory :many
+-- Khớp tiền tố (tên, một từ trong tên, email) xếp trước, sau đó là tên gần đúng theo trigram.
+-- Phân trang keyset theo (match_rank, lower(user_fullname), user_uuid); sort_name được trả về để tạo cursor
+-- vì lower() của PostgreSQL và strings.ToLower của Go có thể khác nhau với một số ký tự Unicode.
+SELECT u.*, r.match_rank, lower(u.user_fullname)::text AS sort_name
+FROM users u
+    CROSS JOIN LATERAL (
+        SELECT
+            CASE
+                WHEN lower(u.user_fullname) LIKE sqlc.arg('prefix')::text
+                OR lower(u.user_fullname) LIKE '% ' || sqlc.arg('prefix')::text
+                OR lower(u.user_email) LIKE sqlc.arg('prefix')::text THEN 0
+                ELSE 1
+            END::int AS match_rank
+    ) r
+WHERE
+    u.user_deactivated_at IS NULL
+    AND u.user_discoverable
+    AND u.user_uuid <> sqlc.arg('requester_uuid')
+    AND (
+        lower(u.user_fullname) LIKE sqlc.arg('prefix')::text
+        OR lower(u.user_fullname) LIKE '% ' || sqlc.arg('prefix')::text
+        OR lower(u.user_email) LIKE sqlc.arg('prefix')::text
+        OR lower(u.user_fullname) % sqlc.arg('query')::text
+    )
+    AND (
+        sqlc.narg('after_rank')::int IS NULL
+        OR (
+            r.match_rank,
+            lower(u.user_fullname),
+            u.user_uuid
+        ) > (
+            sqlc.narg('after_rank')::int,
+            sqlc.narg('after_name')::text,
+            sqlc.narg('after_uuid')::uuid
+        )
+    )
+ORDER BY r.match_rank, lower(u.user_fullname), u.user_uuid
+LIMIT sqlc.arg('limit');
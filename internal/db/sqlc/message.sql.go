@@ -7,6 +7,7 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -25,7 +26,15 @@ func (q *Queries) CountRoomMessages(ctx context.Context, roomID int64) (int64, e
 const createMessage = `-- name: CreateMessage :one
 INSERT INTO
     messages (room_id, user_uuid, content)
-VALUES ($1, $2, $3) RETURNING message_id, room_id, user_uuid, content, message_created_at
+VALUES ($1, $2, $3)
+RETURNING
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
 `
 
 type CreateMessageParams struct {
@@ -34,51 +43,642 @@ type CreateMessageParams struct {
 	Content  string    `json:"content"`
 }
 
-func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error) {
+type CreateMessageRow struct {
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	MessageExternalID *string   `json:"-"`
+}
+
+func (q *Queries) CreateMessage(ctx context.Context, arg CreateMessageParams) (CreateMessageRow, error) {
 	row := q.db.QueryRow(ctx, createMessage, arg.RoomID, arg.UserUuid, arg.Content)
-	var i Message
+	var i CreateMessageRow
+	err := row.Scan(
+		&i.MessageID,
+		&i.RoomID,
+		&i.UserUuid,
+		&i.Content,
+		&i.MessageCreatedAt,
+		&i.MessageType,
+		&i.MessageExternalID,
+	)
+	return i, err
+}
+
+const createSystemMessage = `-- name: CreateSystemMessage :one
+INSERT INTO
+    messages (room_id, user_uuid, content, message_type)
+VALUES ($1, $2, $3, 'system')
+RETURNING
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+`
+
+type CreateSystemMessageParams struct {
+	RoomID   int64     `json:"room_id"`
+	UserUuid uuid.UUID `json:"user_uuid"`
+	Content  string    `json:"content"`
+}
+
+type CreateSystemMessageRow struct {
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	MessageExternalID *string   `json:"-"`
+}
+
+func (q *Queries) CreateSystemMessage(ctx context.Context, arg CreateSystemMessageParams) (CreateSystemMessageRow, error) {
+	row := q.db.QueryRow(ctx, createSystemMessage, arg.RoomID, arg.UserUuid, arg.Content)
+	var i CreateSystemMessageRow
 	err := row.Scan(
 		&i.MessageID,
 		&i.RoomID,
 		&i.UserUuid,
 		&i.Content,
 		&i.MessageCreatedAt,
+		&i.MessageType,
+		&i.MessageExternalID,
+	)
+	return i, err
+}
+
+const getLastUserMessageTime = `-- name: GetLastUserMessageTime :one
+SELECT message_created_at
+FROM messages
+WHERE
+    room_id = $1
+    AND user_uuid = $2
+    AND message_type = 'text'
+ORDER BY message_created_at DESC
+LIMIT 1
+`
+
+type GetLastUserMessageTimeParams struct {
+	RoomID   int64     `json:"room_id"`
+	UserUuid uuid.UUID `json:"user_uuid"`
+}
+
+func (q *Queries) GetLastUserMessageTime(ctx context.Context, arg GetLastUserMessageTimeParams) (time.Time, error) {
+	row := q.db.QueryRow(ctx, getLastUserMessageTime, arg.RoomID, arg.UserUuid)
+	var message_created_at time.Time
+	err := row.Scan(&message_created_at)
+	return message_created_at, err
+}
+
+const getRoomMessage = `-- name: GetRoomMessage :one
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    room_id = $1
+    AND message_id = $2
+`
+
+type GetRoomMessageParams struct {
+	RoomID    int64 `json:"room_id"`
+	MessageID int64 `json:"message_id"`
+}
+
+type GetRoomMessageRow struct {
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	MessageExternalID *string   `json:"-"`
+}
+
+func (q *Queries) GetRoomMessage(ctx context.Context, arg GetRoomMessageParams) (GetRoomMessageRow, error) {
+	row := q.db.QueryRow(ctx, getRoomMessage, arg.RoomID, arg.MessageID)
+	var i GetRoomMessageRow
+	err := row.Scan(
+		&i.MessageID,
+		&i.RoomID,
+		&i.UserUuid,
+		&i.Content,
+		&i.MessageCreatedAt,
+		&i.MessageType,
+		&i.MessageExternalID,
 	)
 	return i, err
 }
 
 const getRoomMessages = `-- name: GetRoomMessages :many
-SELECT message_id, room_id, user_uuid, content, message_created_at
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
 FROM messages
 WHERE
     room_id = $1
+    -- Bỏ tin nhắn của người mà viewer đã chặn (viewer_uuid = NULL: không lọc)
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = $2
+            AND b.blocked_uuid = messages.user_uuid
+    )
 ORDER BY message_created_at DESC
-LIMIT $2
+LIMIT $3
 OFFSET
-    $3
+    $4
 `
 
 type GetRoomMessagesParams struct {
-	RoomID int64 `json:"room_id"`
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+	RoomID     int64      `json:"room_id"`
+	ViewerUuid *uuid.UUID `json:"viewer_uuid"`
+	Limit      int32      `json:"limit"`
+	Offset     int32      `json:"offset"`
+}
+
+type GetRoomMessagesRow struct {
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	MessageExternalID *string   `json:"-"`
+}
+
+func (q *Queries) GetRoomMessages(ctx context.Context, arg GetRoomMessagesParams) ([]GetRoomMessagesRow, error) {
+	rows, err := q.db.Query(ctx, getRoomMessages,
+		arg.RoomID,
+		arg.ViewerUuid,
+		arg.Limit,
+		arg.Offset,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRoomMessagesRow{}
+	for rows.Next() {
+		var i GetRoomMessagesRow
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.RoomID,
+			&i.UserUuid,
+			&i.Content,
+			&i.MessageCreatedAt,
+			&i.MessageType,
+			&i.MessageExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getRoomMessagesAfter = `-- name: GetRoomMessagesAfter :many
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    room_id = $1
+    AND message_id > $2
+    -- Bỏ tin nhắn của người mà viewer đã chặn (viewer_uuid = NULL: không lọc)
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = $3
+            AND b.blocked_uuid = messages.user_uuid
+    )
+ORDER BY message_id ASC
+LIMIT $4
+`
+
+type GetRoomMessagesAfterParams struct {
+	RoomID     int64      `json:"room_id"`
+	MessageID  int64      `json:"message_id"`
+	ViewerUuid *uuid.UUID `json:"viewer_uuid"`
+	Limit      int32      `json:"limit"`
+}
+
+type GetRoomMessagesAfterRow struct {
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	MessageExternalID *string   `json:"-"`
+}
+
+func (q *Queries) GetRoomMessagesAfter(ctx context.Context, arg GetRoomMessagesAfterParams) ([]GetRoomMessagesAfterRow, error) {
+	rows, err := q.db.Query(ctx, getRoomMessagesAfter,
+		arg.RoomID,
+		arg.MessageID,
+		arg.ViewerUuid,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRoomMessagesAfterRow{}
+	for rows.Next() {
+		var i GetRoomMessagesAfterRow
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.RoomID,
+			&i.UserUuid,
+			&i.Content,
+			&i.MessageCreatedAt,
+			&i.MessageType,
+			&i.MessageExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
-func (q *Queries) GetRoomMessages(ctx context.Context, arg GetRoomMessagesParams) ([]Message, error) {
-	rows, err := q.db.Query(ctx, getRoomMessages, arg.RoomID, arg.Limit, arg.Offset)
+const getRoomMessagesBefore = `-- name: GetRoomMessagesBefore :many
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    room_id = $1
+    AND (
+        $2::bigint IS NULL
+        OR message_id < $2
+    )
+    -- Bỏ tin nhắn của người mà viewer đã chặn (viewer_uuid = NULL: không lọc)
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = $3
+            AND b.blocked_uuid = messages.user_uuid
+    )
+ORDER BY message_id DESC
+LIMIT $4
+`
+
+type GetRoomMessagesBeforeParams struct {
+	RoomID     int64      `json:"room_id"`
+	BeforeID   *int64     `json:"before_id"`
+	ViewerUuid *uuid.UUID `json:"viewer_uuid"`
+	Limit      int32      `json:"limit"`
+}
+
+type GetRoomMessagesBeforeRow struct {
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	MessageExternalID *string   `json:"-"`
+}
+
+func (q *Queries) GetRoomMessagesBefore(ctx context.Context, arg GetRoomMessagesBeforeParams) ([]GetRoomMessagesBeforeRow, error) {
+	rows, err := q.db.Query(ctx, getRoomMessagesBefore,
+		arg.RoomID,
+		arg.BeforeID,
+		arg.ViewerUuid,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []GetRoomMessagesBeforeRow{}
+	for rows.Next() {
+		var i GetRoomMessagesBeforeRow
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.RoomID,
+			&i.UserUuid,
+			&i.Content,
+			&i.MessageCreatedAt,
+			&i.MessageType,
+			&i.MessageExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listRoomMessagesForExport = `-- name: ListRoomMessagesForExport :many
+-- Xuất lịch sử phòng theo thứ tự gửi, kèm tên người gửi.
+-- Phân trang theo (message_created_at, message_id): tin nhắn import giữ thời điểm gửi gốc nhưng có message_id mới
+SELECT
+    m.message_id,
+    m.room_id,
+    m.user_uuid,
+    m.content,
+    m.message_created_at,
+    m.message_type,
+    m.message_external_id,
+    u.user_fullname
+FROM messages m
+    JOIN users u ON u.user_uuid = m.user_uuid
+WHERE
+    m.room_id = $1
+    AND (
+        $2::timestamptz IS NULL
+        OR (m.message_created_at, m.message_id) > (
+            $2::timestamptz,
+            $3::bigint
+        )
+    )
+ORDER BY m.message_created_at ASC, m.message_id ASC
+LIMIT $4
+`
+
+type ListRoomMessagesForExportParams struct {
+	RoomID         int64      `json:"room_id"`
+	AfterCreatedAt *time.Time `json:"after_created_at"`
+	AfterID        int64      `json:"after_id"`
+	Limit          int32      `json:"limit"`
+}
+
+type ListRoomMessagesForExportRow struct {
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	MessageExternalID *string   `json:"-"`
+	UserFullname      string    `json:"user_fullname"`
+}
+
+func (q *Queries) ListRoomMessagesForExport(ctx context.Context, arg ListRoomMessagesForExportParams) ([]ListRoomMessagesForExportRow, error) {
+	rows, err := q.db.Query(ctx, listRoomMessagesForExport,
+		arg.RoomID,
+		arg.AfterCreatedAt,
+		arg.AfterID,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRoomMessagesForExportRow{}
+	for rows.Next() {
+		var i ListRoomMessagesForExportRow
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.RoomID,
+			&i.UserUuid,
+			&i.Content,
+			&i.MessageCreatedAt,
+			&i.MessageType,
+			&i.MessageExternalID,
+			&i.UserFullname,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserMessages = `-- name: ListUserMessages :many
+-- Tin nhắn do user gửi (xuất dữ liệu cá nhân), phân trang theo message_id
+SELECT
+    message_id,
+    room_id,
+    user_uuid,
+    content,
+    message_created_at,
+    message_type,
+    message_external_id
+FROM messages
+WHERE
+    user_uuid = $1
+    AND message_type = 'text'
+    AND message_id > $2
+ORDER BY message_id ASC
+LIMIT $3
+`
+
+type ListUserMessagesParams struct {
+	UserUuid uuid.UUID `json:"user_uuid"`
+	AfterID  int64     `json:"after_id"`
+	Limit    int32     `json:"limit"`
+}
+
+type ListUserMessagesRow struct {
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	MessageExternalID *string   `json:"-"`
+}
+
+func (q *Queries) ListUserMessages(ctx context.Context, arg ListUserMessagesParams) ([]ListUserMessagesRow, error) {
+	rows, err := q.db.Query(ctx, listUserMessages, arg.UserUuid, arg.AfterID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserMessagesRow{}
+	for rows.Next() {
+		var i ListUserMessagesRow
+		if err := rows.Scan(
+			&i.MessageID,
+			&i.RoomID,
+			&i.UserUuid,
+			&i.Content,
+			&i.MessageCreatedAt,
+			&i.MessageType,
+			&i.MessageExternalID,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const lockUserRoomPosting = `-- name: LockUserRoomPosting :exec
+-- Khóa theo (phòng, user) đến hết transaction: các lần gửi đồng thời (REST và websocket) của cùng user phải chờ nhau
+SELECT pg_advisory_xact_lock(
+        hashtextextended(format('%s:%s', $1::bigint, $2::uuid), 0)
+    )
+`
+
+type LockUserRoomPostingParams struct {
+	RoomID   int64     `json:"room_id"`
+	UserUuid uuid.UUID `json:"user_uuid"`
+}
+
+func (q *Queries) LockUserRoomPosting(ctx context.Context, arg LockUserRoomPostingParams) error {
+	_, err := q.db.Exec(ctx, lockUserRoomPosting, arg.RoomID, arg.UserUuid)
+	return err
+}
+
+const searchMessages = `-- name: SearchMessages :many
+SELECT
+    m.message_id,
+    m.room_id,
+    m.user_uuid,
+    m.content,
+    m.message_type,
+    m.message_created_at,
+    r.room_name,
+    u.user_fullname,
+    -- Escape HTML trước khi highlight để client có thể render snippet an toàn
+    ts_headline(
+        'simple',
+        replace(replace(replace(m.content, '&', '&amp;'), '<', '&lt;'), '>', '&gt;'),
+        q.query,
+        'StartSel=<mark>, StopSel=</mark>, MaxFragments=2, MaxWords=20, MinWords=5'
+    )::text AS snippet
+FROM
+    messages m
+    INNER JOIN room_members rm ON rm.room_id = m.room_id
+    AND rm.user_uuid = $1
+    INNER JOIN rooms r ON r.room_id = m.room_id
+    INNER JOIN users u ON u.user_uuid = m.user_uuid
+    CROSS JOIN websearch_to_tsquery('simple', $2) AS q (query)
+WHERE
+    m.content_tsv @@ q.query
+    AND m.message_type = 'text'
+    -- Không trả về tin nhắn của người mà user đã chặn
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = $1
+            AND b.blocked_uuid = m.user_uuid
+    )
+    AND (
+        $3::bigint IS NULL
+        OR m.room_id = $3
+    )
+    AND (
+        $4::text IS NULL
+        OR m.user_uuid = $4::uuid
+    )
+    AND (
+        $5::timestamptz IS NULL
+        OR m.message_created_at >= $5
+    )
+    AND (
+        $6::timestamptz IS NULL
+        OR m.message_created_at < $6
+    )
+    AND (
+        $7::bigint IS NULL
+        OR m.message_id < $7
+    )
+ORDER BY m.message_id DESC
+LIMIT $8
+`
+
+type SearchMessagesParams struct {
+	UserUuid   uuid.UUID  `json:"user_uuid"`
+	Query      string     `json:"query"`
+	RoomID     *int64     `json:"room_id"`
+	SenderUuid *string    `json:"sender_uuid"`
+	FromTime   *time.Time `json:"from_time"`
+	ToTime     *time.Time `json:"to_time"`
+	BeforeID   *int64     `json:"before_id"`
+	Limit      int32      `json:"limit"`
+}
+
+type SearchMessagesRow struct {
+	MessageID        int64     `json:"message_id"`
+	RoomID           int64     `json:"room_id"`
+	UserUuid         uuid.UUID `json:"user_uuid"`
+	Content          string    `json:"content"`
+	MessageType      string    `json:"message_type"`
+	MessageCreatedAt time.Time `json:"message_created_at"`
+	RoomName         *string   `json:"room_name"`
+	UserFullname     string    `json:"user_fullname"`
+	Snippet          string    `json:"snippet"`
+}
+
+func (q *Queries) SearchMessages(ctx context.Context, arg SearchMessagesParams) ([]SearchMessagesRow, error) {
+	rows, err := q.db.Query(ctx, searchMessages,
+		arg.UserUuid,
+		arg.Query,
+		arg.RoomID,
+		arg.SenderUuid,
+		arg.FromTime,
+		arg.ToTime,
+		arg.BeforeID,
+		arg.Limit,
+	)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
-	items := []Message{}
+	items := []SearchMessagesRow{}
 	for rows.Next() {
-		var i Message
+		var i SearchMessagesRow
 		if err := rows.Scan(
 			&i.MessageID,
 			&i.RoomID,
 			&i.UserUuid,
 			&i.Content,
+			&i.MessageType,
 			&i.MessageCreatedAt,
+			&i.RoomName,
+			&i.UserFullname,
+			&i.Snippet,
 		); err != nil {
 			return nil, err
 		}
@@ -5,43 +5,179 @@
 package sqlc
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
 )
 
+type AuditEvent struct {
+	AuditID        int64           `json:"audit_id"`
+	ActorUuid      *uuid.UUID      `json:"actor_uuid"`
+	AuditAction    string          `json:"audit_action"`
+	TargetType     *string         `json:"target_type"`
+	TargetID       *string         `json:"target_id"`
+	AuditIp        *string         `json:"audit_ip"`
+	AuditUserAgent *string         `json:"audit_user_agent"`
+	AuditMetadata  json.RawMessage `json:"audit_metadata"`
+	AuditCreatedAt time.Time       `json:"audit_created_at"`
+}
+
+type DataExport struct {
+	ExportID          int64      `json:"export_id"`
+	UserUuid          uuid.UUID  `json:"user_uuid"`
+	RequestedByUuid   *uuid.UUID `json:"requested_by_uuid"`
+	ExportStatus      string     `json:"export_status"`
+	StorageKey        *string    `json:"storage_key"`
+	SizeBytes         *int64     `json:"size_bytes"`
+	ExportError       *string    `json:"export_error"`
+	ExportCreatedAt   time.Time  `json:"export_created_at"`
+	ExportStartedAt   *time.Time `json:"export_started_at"`
+	ExportCompletedAt *time.Time `json:"export_completed_at"`
+	ExportExpiresAt   *time.Time `json:"export_expires_at"`
+}
+
+type EmailVerificationToken struct {
+	TokenID        uuid.UUID  `json:"token_id"`
+	UserUuid       uuid.UUID  `json:"user_uuid"`
+	TokenEmail     string     `json:"token_email"`
+	TokenExpiresAt time.Time  `json:"token_expires_at"`
+	TokenUsedAt    *time.Time `json:"token_used_at"`
+	TokenCreatedAt time.Time  `json:"token_created_at"`
+}
+
+type ImportedRoom struct {
+	ImportSource          string    `json:"import_source"`
+	ExternalID            string    `json:"external_id"`
+	RoomID                int64     `json:"room_id"`
+	ImportedRoomCreatedAt time.Time `json:"imported_room_created_at"`
+}
+
 type Message struct {
-	MessageID        int64     `json:"message_id"`
-	RoomID           int64     `json:"room_id"`
+	MessageID         int64     `json:"message_id"`
+	RoomID            int64     `json:"room_id"`
+	UserUuid          uuid.UUID `json:"user_uuid"`
+	Content           string    `json:"content"`
+	MessageCreatedAt  time.Time `json:"message_created_at"`
+	MessageType       string    `json:"message_type"`
+	ContentTsv        string    `json:"-"`
+	MessageExternalID *string   `json:"-"`
+}
+
+type MessageAttachment struct {
+	AttachmentID          int64      `json:"attachment_id"`
+	MessageID             int64      `json:"message_id"`
+	RoomID                int64      `json:"room_id"`
+	UploaderUuid          uuid.UUID  `json:"uploader_uuid"`
+	StorageKey            string     `json:"storage_key"`
+	FileName              string     `json:"file_name"`
+	ContentType           string     `json:"content_type"`
+	SizeBytes             int64      `json:"size_bytes"`
+	AttachmentCreatedAt   time.Time  `json:"attachment_created_at"`
+	ImageWidth            *int32     `json:"image_width"`
+	ImageHeight           *int32     `json:"image_height"`
+	ThumbnailKey          *string    `json:"thumbnail_key"`
+	ThumbnailContentType  *string    `json:"thumbnail_content_type"`
+	ThumbnailWidth        *int32     `json:"thumbnail_width"`
+	ThumbnailHeight       *int32     `json:"thumbnail_height"`
+	AttachmentProcessedAt *time.Time `json:"attachment_processed_at"`
+}
+
+type MessageMention struct {
+	MentionID         int64      `json:"mention_id"`
+	MessageID         int64      `json:"message_id"`
+	RoomID            int64      `json:"room_id"`
+	MentionedUserUuid uuid.UUID  `json:"mentioned_user_uuid"`
+	MentionType       string     `json:"mention_type"`
+	MentionCreatedAt  time.Time  `json:"mention_created_at"`
+	MentionReadAt     *time.Time `json:"mention_read_at"`
+}
+
+type Notification struct {
+	NotificationID        int64           `json:"notification_id"`
+	UserUuid              uuid.UUID       `json:"user_uuid"`
+	NotificationType      string          `json:"notification_type"`
+	RoomID                *int64          `json:"room_id"`
+	MessageID             *int64          `json:"message_id"`
+	ActorUuid             uuid.UUID       `json:"actor_uuid"`
+	NotificationData      json.RawMessage `json:"notification_data"`
+	NotificationReadAt    *time.Time      `json:"notification_read_at"`
+	NotificationCreatedAt time.Time       `json:"notification_created_at"`
+}
+
+type PasswordResetToken struct {
+	TokenID        uuid.UUID  `json:"token_id"`
+	UserUuid       uuid.UUID  `json:"user_uuid"`
+	TokenHash      string     `json:"token_hash"`
+	TokenExpiresAt time.Time  `json:"token_expires_at"`
+	TokenUsedAt    *time.Time `json:"token_used_at"`
+	TokenCreatedAt time.Time  `json:"token_created_at"`
+}
+
+type PushDevice struct {
+	DeviceID         int64     `json:"device_id"`
 	UserUuid         uuid.UUID `json:"user_uuid"`
-	Content          string    `json:"content"`
-	MessageCreatedAt time.Time `json:"message_created_at"`
+	DevicePlatform   string    `json:"device_platform"`
+	DeviceToken      string    `json:"device_token"`
+	DeviceP256dh     *string   `json:"device_p256dh"`
+	DeviceAuth       *string   `json:"device_auth"`
+	DeviceCreatedAt  time.Time `json:"device_created_at"`
+	DeviceLastUsedAt time.Time `json:"device_last_used_at"`
 }
 
 type Room struct {
-	RoomID           int64     `json:"room_id"`
-	RoomCode         string    `json:"room_code"`
-	RoomName         *string   `json:"room_name"`
-	RoomIsDirectChat bool      `json:"room_is_direct_chat"`
-	RoomCreatedBy    uuid.UUID `json:"room_created_by"`
-	RoomCreatedAt    time.Time `json:"room_created_at"`
-	RoomUpdatedAt    time.Time `json:"room_updated_at"`
+	RoomID                int64      `json:"room_id"`
+	RoomCode              string     `json:"room_code"`
+	RoomName              *string    `json:"room_name"`
+	RoomIsDirectChat      bool       `json:"room_is_direct_chat"`
+	RoomCreatedBy         uuid.UUID  `json:"room_created_by"`
+	RoomCreatedAt         time.Time  `json:"room_created_at"`
+	RoomUpdatedAt         time.Time  `json:"room_updated_at"`
+	RoomTopic             *string    `json:"room_topic"`
+	RoomDescription       *string    `json:"room_description"`
+	RoomAvatarUrl         *string    `json:"room_avatar_url"`
+	RoomArchivedAt        *time.Time `json:"room_archived_at"`
+	RoomPostingPermission string     `json:"room_posting_permission"`
+	RoomSlowModeSeconds   int32      `json:"room_slow_mode_seconds"`
 }
 
 type RoomMember struct {
-	UserUuid            uuid.UUID `json:"user_uuid"`
-	RoomID              int64     `json:"room_id"`
-	MemberRole          string    `json:"member_role"`
-	RoomMemberCreatedAt time.Time `json:"room_member_created_at"`
-	RoomMemberUpdatedAt time.Time `json:"room_member_updated_at"`
+	UserUuid                uuid.UUID  `json:"user_uuid"`
+	RoomID                  int64      `json:"room_id"`
+	MemberRole              string     `json:"member_role"`
+	RoomMemberCreatedAt     time.Time  `json:"room_member_created_at"`
+	RoomMemberUpdatedAt     time.Time  `json:"room_member_updated_at"`
+	MemberNotificationLevel string     `json:"member_notification_level"`
+	MemberMutedUntil        *time.Time `json:"member_muted_until"`
 }
 
 type User struct {
-	UserUuid      uuid.UUID `json:"user_uuid"`
-	UserEmail     string    `json:"user_email"`
-	UserPassword  string    `json:"user_password"`
-	UserFullname  string    `json:"user_fullname"`
-	UserRole      string    `json:"user_role"`
-	UserCreatedAt time.Time `json:"user_created_at"`
-	UserUpdatedAt time.Time `json:"user_updated_at"`
+	UserUuid              uuid.UUID  `json:"user_uuid"`
+	UserEmail             string     `json:"user_email"`
+	UserPassword          string     `json:"user_password"`
+	UserFullname          string     `json:"user_fullname"`
+	UserRole              string     `json:"user_role"`
+	UserCreatedAt         time.Time  `json:"user_created_at"`
+	UserUpdatedAt         time.Time  `json:"user_updated_at"`
+	UserDigestFrequency   string     `json:"user_digest_frequency"`
+	UserDigestLastSentAt  *time.Time `json:"user_digest_last_sent_at"`
+	UserLastSeenAt        *time.Time `json:"user_last_seen_at"`
+	UserEmailVerifiedAt   *time.Time `json:"user_email_verified_at"`
+	UserPasswordChangedAt *time.Time `json:"user_password_changed_at"`
+	UserAvatarUrl         *string    `json:"user_avatar_url"`
+	UserBio               *string    `json:"user_bio"`
+	UserTimezone          string     `json:"user_timezone"`
+	UserLocale            string     `json:"user_locale"`
+	UserDiscoverable      bool       `json:"user_discoverable"`
+	UserDeactivatedAt     *time.Time `json:"user_deactivated_at"`
+	UserSuspendedAt       *time.Time `json:"user_suspended_at"`
+	UserSuspendedUntil    *time.Time `json:"user_suspended_until"`
+	UserSuspensionReason  *string    `json:"user_suspension_reason"`
+	UserDeletedAt         *time.Time `json:"user_deleted_at"`
+}
+
+type UserBlock struct {
+	BlockerUuid    uuid.UUID `json:"blocker_uuid"`
+	BlockedUuid    uuid.UUID `json:"blocked_uuid"`
+	BlockCreatedAt time.Time `json:"block_created_at"`
 }
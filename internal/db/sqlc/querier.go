@@ -6,31 +6,113 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
 
 type Querier interface {
+	AddImportedRoomMember(ctx context.Context, arg AddImportedRoomMemberParams) error
+	AddRoomMember(ctx context.Context, arg AddRoomMemberParams) (RoomMember, error)
+	AnonymizeUser(ctx context.Context, userUuid uuid.UUID) (AnonymizeUserRow, error)
+	ArchiveRoom(ctx context.Context, roomID int64) (Room, error)
+	CompleteDataExport(ctx context.Context, arg CompleteDataExportParams) (DataExport, error)
+	CopyImportedMessages(ctx context.Context, arg []CopyImportedMessagesParams) (int64, error)
+	CountEmailVerificationTokensSince(ctx context.Context, arg CountEmailVerificationTokensSinceParams) (int64, error)
+	CountPasswordResetTokensSince(ctx context.Context, arg CountPasswordResetTokensSinceParams) (int64, error)
 	CountRoomMessages(ctx context.Context, roomID int64) (int64, error)
-	CreateMessage(ctx context.Context, arg CreateMessageParams) (Message, error)
+	CountUnreadNotifications(ctx context.Context, userUuid uuid.UUID) (int64, error)
+	CreateAttachment(ctx context.Context, arg CreateAttachmentParams) (MessageAttachment, error)
+	CreateAuditEvent(ctx context.Context, arg CreateAuditEventParams) error
+	CreateDataExport(ctx context.Context, arg CreateDataExportParams) (DataExport, error)
+	CreateEmailVerificationToken(ctx context.Context, arg CreateEmailVerificationTokenParams) (EmailVerificationToken, error)
+	CreateImportedRoom(ctx context.Context, arg CreateImportedRoomParams) (ImportedRoom, error)
+	CreateMessage(ctx context.Context, arg CreateMessageParams) (CreateMessageRow, error)
+	CreateMessageMentions(ctx context.Context, arg CreateMessageMentionsParams) ([]MessageMention, error)
+	CreateNotifications(ctx context.Context, arg CreateNotificationsParams) ([]Notification, error)
+	CreatePasswordResetToken(ctx context.Context, arg CreatePasswordResetTokenParams) (PasswordResetToken, error)
 	CreateRoom(ctx context.Context, arg CreateRoomParams) (Room, error)
+	CreateSystemMessage(ctx context.Context, arg CreateSystemMessageParams) (CreateSystemMessageRow, error)
 	CreateUser(ctx context.Context, arg CreateUserParams) (User, error)
+	CreateUserBlock(ctx context.Context, arg CreateUserBlockParams) (UserBlock, error)
+	DeletePushDevice(ctx context.Context, arg DeletePushDeviceParams) (int64, error)
+	DeletePushDeviceByID(ctx context.Context, deviceID int64) error
 	DeleteRoom(ctx context.Context, roomID int64) error
-	DeleteUser(ctx context.Context, userUuid uuid.UUID) error
+	DeleteUserBlock(ctx context.Context, arg DeleteUserBlockParams) (int64, error)
+	ExpireDataExport(ctx context.Context, exportID int64) error
+	FailDataExport(ctx context.Context, arg FailDataExportParams) (DataExport, error)
 	GenerateUniqueRoomCode(ctx context.Context) (string, error)
+	GetActiveDataExport(ctx context.Context, userUuid uuid.UUID) (DataExport, error)
 	GetAllRoomsWithMemberCount(ctx context.Context, arg GetAllRoomsWithMemberCountParams) ([]GetAllRoomsWithMemberCountRow, error)
 	GetAllUsers(ctx context.Context, arg GetAllUsersParams) ([]User, error)
+	GetAttachmentByID(ctx context.Context, attachmentID int64) (MessageAttachment, error)
+	GetDataExport(ctx context.Context, exportID int64) (DataExport, error)
+	GetEmailVerificationToken(ctx context.Context, tokenID uuid.UUID) (EmailVerificationToken, error)
+	GetImportedRoom(ctx context.Context, arg GetImportedRoomParams) (ImportedRoom, error)
+	GetLastUserMessageTime(ctx context.Context, arg GetLastUserMessageTimeParams) (time.Time, error)
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (PasswordResetToken, error)
 	GetRoomByCode(ctx context.Context, roomCode string) (Room, error)
 	GetRoomByID(ctx context.Context, roomID int64) (Room, error)
+	GetRoomMember(ctx context.Context, arg GetRoomMemberParams) (RoomMember, error)
 	GetRoomMembers(ctx context.Context, roomID int64) ([]User, error)
-	GetRoomMessages(ctx context.Context, arg GetRoomMessagesParams) ([]Message, error)
+	GetRoomMessage(ctx context.Context, arg GetRoomMessageParams) (GetRoomMessageRow, error)
+	GetRoomMessages(ctx context.Context, arg GetRoomMessagesParams) ([]GetRoomMessagesRow, error)
+	GetRoomMessagesAfter(ctx context.Context, arg GetRoomMessagesAfterParams) ([]GetRoomMessagesAfterRow, error)
+	GetRoomMessagesBefore(ctx context.Context, arg GetRoomMessagesBeforeParams) ([]GetRoomMessagesBeforeRow, error)
+	GetRoomOwner(ctx context.Context, roomID int64) (RoomMember, error)
 	GetUserByEmail(ctx context.Context, userEmail string) (User, error)
 	GetUserByUUID(ctx context.Context, userUuid uuid.UUID) (User, error)
+	HasBlockWithRoomMembers(ctx context.Context, arg HasBlockWithRoomMembersParams) (bool, error)
+	InvalidateUserPasswordResetTokens(ctx context.Context, userUuid uuid.UUID) error
 	IsUserMemberOfRoom(ctx context.Context, arg IsUserMemberOfRoomParams) (bool, error)
 	JoinRoom(ctx context.Context, arg JoinRoomParams) (RoomMember, error)
-	LeaveRoom(ctx context.Context, arg LeaveRoomParams) error
+	LeaveRoom(ctx context.Context, arg LeaveRoomParams) (RoomMember, error)
+	ListAttachmentsByMessageIDs(ctx context.Context, messageIds []int64) ([]MessageAttachment, error)
+	ListAuditEvents(ctx context.Context, arg ListAuditEventsParams) ([]ListAuditEventsRow, error)
+	ListBlockedUserIDs(ctx context.Context, blockerUuid uuid.UUID) ([]uuid.UUID, error)
+	ListBlockerIDs(ctx context.Context, blockedUuid uuid.UUID) ([]uuid.UUID, error)
+	ListDigestDirectMessages(ctx context.Context, arg ListDigestDirectMessagesParams) ([]ListDigestDirectMessagesRow, error)
+	ListDigestMentions(ctx context.Context, arg ListDigestMentionsParams) ([]ListDigestMentionsRow, error)
+	ListDigestRecipients(ctx context.Context, arg ListDigestRecipientsParams) ([]User, error)
+	ListExistingExternalMessageIDs(ctx context.Context, arg ListExistingExternalMessageIDsParams) ([]string, error)
+	ListExpiredDataExports(ctx context.Context, limit int32) ([]DataExport, error)
+	ListNotifications(ctx context.Context, arg ListNotificationsParams) ([]Notification, error)
+	ListPushDevicesByUsers(ctx context.Context, userUuids []uuid.UUID) ([]PushDevice, error)
+	ListRoomMemberNotificationSettings(ctx context.Context, roomID int64) ([]ListRoomMemberNotificationSettingsRow, error)
+	ListRoomMessagesForExport(ctx context.Context, arg ListRoomMessagesForExportParams) ([]ListRoomMessagesForExportRow, error)
+	ListSharedRoomIDs(ctx context.Context, userUuid uuid.UUID) ([]int64, error)
+	ListUserBlocks(ctx context.Context, blockerUuid uuid.UUID) ([]ListUserBlocksRow, error)
+	ListUserDataExports(ctx context.Context, arg ListUserDataExportsParams) ([]DataExport, error)
+	ListUserMemberships(ctx context.Context, userUuid uuid.UUID) ([]ListUserMembershipsRow, error)
+	ListUserMessages(ctx context.Context, arg ListUserMessagesParams) ([]ListUserMessagesRow, error)
+	ListUserPushDevices(ctx context.Context, userUuid uuid.UUID) ([]PushDevice, error)
 	ListUserRooms(ctx context.Context, userUuid uuid.UUID) ([]Room, error)
-	ListUserRoomsWithLastMessage(ctx context.Context, userUuid uuid.UUID) ([]ListUserRoomsWithLastMessageRow, error)
+	ListUserRoomsWithLastMessage(ctx context.Context, arg ListUserRoomsWithLastMessageParams) ([]ListUserRoomsWithLastMessageRow, error)
+	LockUserRoomPosting(ctx context.Context, arg LockUserRoomPostingParams) error
+	MarkAllNotificationsRead(ctx context.Context, userUuid uuid.UUID) (int64, error)
+	MarkAttachmentProcessed(ctx context.Context, arg MarkAttachmentProcessedParams) (MessageAttachment, error)
+	MarkDigestSent(ctx context.Context, userUuid uuid.UUID) error
+	MarkNotificationRead(ctx context.Context, arg MarkNotificationReadParams) (Notification, error)
+	MarkRoomMentionsRead(ctx context.Context, arg MarkRoomMentionsReadParams) (int64, error)
+	MarkUserEmailVerified(ctx context.Context, arg MarkUserEmailVerifiedParams) (User, error)
+	ResumeDataExports(ctx context.Context) ([]DataExport, error)
+	SearchMessages(ctx context.Context, arg SearchMessagesParams) ([]SearchMessagesRow, error)
+	SearchUserDirectory(ctx context.Context, arg SearchUserDirectoryParams) ([]SearchUserDirectoryRow, error)
+	SetUserDeactivated(ctx context.Context, arg SetUserDeactivatedParams) (User, error)
+	StartDataExport(ctx context.Context, exportID int64) (DataExport, error)
+	SuspendUser(ctx context.Context, arg SuspendUserParams) (User, error)
+	TransferRoomOwnership(ctx context.Context, arg TransferRoomOwnershipParams) error
+	UnarchiveRoom(ctx context.Context, roomID int64) (Room, error)
+	UnsuspendUser(ctx context.Context, userUuid uuid.UUID) (User, error)
+	UpdateRoomMemberNotificationSettings(ctx context.Context, arg UpdateRoomMemberNotificationSettingsParams) (RoomMember, error)
+	UpdateRoomSettings(ctx context.Context, arg UpdateRoomSettingsParams) (Room, error)
+	UpdateUserDigestFrequency(ctx context.Context, arg UpdateUserDigestFrequencyParams) (User, error)
+	UpdateUserLastSeen(ctx context.Context, userUuid uuid.UUID) error
+	UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error)
+	UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error)
+	UpsertPushDevice(ctx context.Context, arg UpsertPushDeviceParams) (PushDevice, error)
+	UseEmailVerificationToken(ctx context.Context, tokenID uuid.UUID) (int64, error)
+	UsePasswordResetToken(ctx context.Context, tokenID uuid.UUID) (int64, error)
 }
 
 var _ Querier = (*Queries)(nil)
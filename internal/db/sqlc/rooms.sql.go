@@ -12,6 +12,58 @@ import (
 	"github.com/google/uuid"
 )
 
+const addRoomMember = `-- name: AddRoomMember :one
+INSERT INTO
+    room_members (user_uuid, room_id, member_role)
+VALUES ($1, $2, $3) RETURNING user_uuid, room_id, member_role, room_member_created_at, room_member_updated_at, member_notification_level, member_muted_until
+`
+
+type AddRoomMemberParams struct {
+	UserUuid   uuid.UUID `json:"user_uuid"`
+	RoomID     int64     `json:"room_id"`
+	MemberRole string    `json:"member_role"`
+}
+
+func (q *Queries) AddRoomMember(ctx context.Context, arg AddRoomMemberParams) (RoomMember, error) {
+	row := q.db.QueryRow(ctx, addRoomMember, arg.UserUuid, arg.RoomID, arg.MemberRole)
+	var i RoomMember
+	err := row.Scan(
+		&i.UserUuid,
+		&i.RoomID,
+		&i.MemberRole,
+		&i.RoomMemberCreatedAt,
+		&i.RoomMemberUpdatedAt,
+		&i.MemberNotificationLevel,
+		&i.MemberMutedUntil,
+	)
+	return i, err
+}
+
+const archiveRoom = `-- name: ArchiveRoom :one
+UPDATE rooms SET room_archived_at = NOW() WHERE room_id = $1 RETURNING room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at, room_topic, room_description, room_avatar_url, room_archived_at, room_posting_permission, room_slow_mode_seconds
+`
+
+func (q *Queries) ArchiveRoom(ctx context.Context, roomID int64) (Room, error) {
+	row := q.db.QueryRow(ctx, archiveRoom, roomID)
+	var i Room
+	err := row.Scan(
+		&i.RoomID,
+		&i.RoomCode,
+		&i.RoomName,
+		&i.RoomIsDirectChat,
+		&i.RoomCreatedBy,
+		&i.RoomCreatedAt,
+		&i.RoomUpdatedAt,
+		&i.RoomTopic,
+		&i.RoomDescription,
+		&i.RoomAvatarUrl,
+		&i.RoomArchivedAt,
+		&i.RoomPostingPermission,
+		&i.RoomSlowModeSeconds,
+	)
+	return i, err
+}
+
 const createRoom = `-- name: CreateRoom :one
 INSERT INTO
     rooms (
@@ -20,7 +72,7 @@ INSERT INTO
         room_is_direct_chat,
         room_created_by
     )
-VALUES ($1, $2, $3, $4) RETURNING room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at
+VALUES ($1, $2, $3, $4) RETURNING room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at, room_topic, room_description, room_avatar_url, room_archived_at, room_posting_permission, room_slow_mode_seconds
 `
 
 type CreateRoomParams struct {
@@ -46,6 +98,12 @@ func (q *Queries) CreateRoom(ctx context.Context, arg CreateRoomParams) (Room, e
 		&i.RoomCreatedBy,
 		&i.RoomCreatedAt,
 		&i.RoomUpdatedAt,
+		&i.RoomTopic,
+		&i.RoomDescription,
+		&i.RoomAvatarUrl,
+		&i.RoomArchivedAt,
+		&i.RoomPostingPermission,
+		&i.RoomSlowModeSeconds,
 	)
 	return i, err
 }
@@ -76,7 +134,7 @@ func (q *Queries) GenerateUniqueRoomCode(ctx context.Context) (string, error) {
 }
 
 const getAllRoomsWithMemberCount = `-- name: GetAllRoomsWithMemberCount :many
-SELECT r.room_id, r.room_code, r.room_name, r.room_is_direct_chat, r.room_created_by, r.room_created_at, r.room_updated_at, COUNT(rm.user_uuid) as member_count
+SELECT r.room_id, r.room_code, r.room_name, r.room_is_direct_chat, r.room_created_by, r.room_created_at, r.room_updated_at, r.room_topic, r.room_description, r.room_avatar_url, r.room_archived_at, r.room_posting_permission, r.room_slow_mode_seconds, COUNT(rm.user_uuid) as member_count
 FROM rooms r
     LEFT JOIN room_members rm ON r.room_id = rm.room_id
 GROUP BY
@@ -93,14 +151,20 @@ type GetAllRoomsWithMemberCountParams struct {
 }
 
 type GetAllRoomsWithMemberCountRow struct {
-	RoomID           int64     `json:"room_id"`
-	RoomCode         string    `json:"room_code"`
-	RoomName         *string   `json:"room_name"`
-	RoomIsDirectChat bool      `json:"room_is_direct_chat"`
-	RoomCreatedBy    uuid.UUID `json:"room_created_by"`
-	RoomCreatedAt    time.Time `json:"room_created_at"`
-	RoomUpdatedAt    time.Time `json:"room_updated_at"`
-	MemberCount      int64     `json:"member_count"`
+	RoomID                int64      `json:"room_id"`
+	RoomCode              string     `json:"room_code"`
+	RoomName              *string    `json:"room_name"`
+	RoomIsDirectChat      bool       `json:"room_is_direct_chat"`
+	RoomCreatedBy         uuid.UUID  `json:"room_created_by"`
+	RoomCreatedAt         time.Time  `json:"room_created_at"`
+	RoomUpdatedAt         time.Time  `json:"room_updated_at"`
+	RoomTopic             *string    `json:"room_topic"`
+	RoomDescription       *string    `json:"room_description"`
+	RoomAvatarUrl         *string    `json:"room_avatar_url"`
+	RoomArchivedAt        *time.Time `json:"room_archived_at"`
+	RoomPostingPermission string     `json:"room_posting_permission"`
+	RoomSlowModeSeconds   int32      `json:"room_slow_mode_seconds"`
+	MemberCount           int64      `json:"member_count"`
 }
 
 func (q *Queries) GetAllRoomsWithMemberCount(ctx context.Context, arg GetAllRoomsWithMemberCountParams) ([]GetAllRoomsWithMemberCountRow, error) {
@@ -120,6 +184,12 @@ func (q *Queries) GetAllRoomsWithMemberCount(ctx context.Context, arg GetAllRoom
 			&i.RoomCreatedBy,
 			&i.RoomCreatedAt,
 			&i.RoomUpdatedAt,
+			&i.RoomTopic,
+			&i.RoomDescription,
+			&i.RoomAvatarUrl,
+			&i.RoomArchivedAt,
+			&i.RoomPostingPermission,
+			&i.RoomSlowModeSeconds,
 			&i.MemberCount,
 		); err != nil {
 			return nil, err
@@ -133,7 +203,7 @@ func (q *Queries) GetAllRoomsWithMemberCount(ctx context.Context, arg GetAllRoom
 }
 
 const getRoomByCode = `-- name: GetRoomByCode :one
-SELECT room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at FROM rooms WHERE room_code = $1
+SELECT room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at, room_topic, room_description, room_avatar_url, room_archived_at, room_posting_permission, room_slow_mode_seconds FROM rooms WHERE room_code = $1
 `
 
 func (q *Queries) GetRoomByCode(ctx context.Context, roomCode string) (Room, error) {
@@ -147,12 +217,18 @@ func (q *Queries) GetRoomByCode(ctx context.Context, roomCode string) (Room, err
 		&i.RoomCreatedBy,
 		&i.RoomCreatedAt,
 		&i.RoomUpdatedAt,
+		&i.RoomTopic,
+		&i.RoomDescription,
+		&i.RoomAvatarUrl,
+		&i.RoomArchivedAt,
+		&i.RoomPostingPermission,
+		&i.RoomSlowModeSeconds,
 	)
 	return i, err
 }
 
 const getRoomByID = `-- name: GetRoomByID :one
-SELECT room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at FROM rooms WHERE room_id = $1
+SELECT room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at, room_topic, room_description, room_avatar_url, room_archived_at, room_posting_permission, room_slow_mode_seconds FROM rooms WHERE room_id = $1
 `
 
 func (q *Queries) GetRoomByID(ctx context.Context, roomID int64) (Room, error) {
@@ -166,12 +242,46 @@ func (q *Queries) GetRoomByID(ctx context.Context, roomID int64) (Room, error) {
 		&i.RoomCreatedBy,
 		&i.RoomCreatedAt,
 		&i.RoomUpdatedAt,
+		&i.RoomTopic,
+		&i.RoomDescription,
+		&i.RoomAvatarUrl,
+		&i.RoomArchivedAt,
+		&i.RoomPostingPermission,
+		&i.RoomSlowModeSeconds,
+	)
+	return i, err
+}
+
+const getRoomMember = `-- name: GetRoomMember :one
+SELECT user_uuid, room_id, member_role, room_member_created_at, room_member_updated_at, member_notification_level, member_muted_until
+FROM room_members
+WHERE
+    user_uuid = $1
+    AND room_id = $2
+`
+
+type GetRoomMemberParams struct {
+	UserUuid uuid.UUID `json:"user_uuid"`
+	RoomID   int64     `json:"room_id"`
+}
+
+func (q *Queries) GetRoomMember(ctx context.Context, arg GetRoomMemberParams) (RoomMember, error) {
+	row := q.db.QueryRow(ctx, getRoomMember, arg.UserUuid, arg.RoomID)
+	var i RoomMember
+	err := row.Scan(
+		&i.UserUuid,
+		&i.RoomID,
+		&i.MemberRole,
+		&i.RoomMemberCreatedAt,
+		&i.RoomMemberUpdatedAt,
+		&i.MemberNotificationLevel,
+		&i.MemberMutedUntil,
 	)
 	return i, err
 }
 
 const getRoomMembers = `-- name: GetRoomMembers :many
-SELECT u.user_uuid, u.user_email, u.user_password, u.user_fullname, u.user_role, u.user_created_at, u.user_updated_at
+SELECT u.user_uuid, u.user_email, u.user_password, u.user_fullname, u.user_role, u.user_created_at, u.user_updated_at, u.user_digest_frequency, u.user_digest_last_sent_at, u.user_last_seen_at, u.user_email_verified_at, u.user_password_changed_at, u.user_avatar_url, u.user_bio, u.user_timezone, u.user_locale, u.user_discoverable, u.user_deactivated_at, u.user_suspended_at, u.user_suspended_until, u.user_suspension_reason, u.user_deleted_at
 FROM users u
     JOIN room_members rm ON u.user_uuid = rm.user_uuid
 WHERE
@@ -195,6 +305,21 @@ func (q *Queries) GetRoomMembers(ctx context.Context, roomID int64) ([]User, err
 			&i.UserRole,
 			&i.UserCreatedAt,
 			&i.UserUpdatedAt,
+			&i.UserDigestFrequency,
+			&i.UserDigestLastSentAt,
+			&i.UserLastSeenAt,
+			&i.UserEmailVerifiedAt,
+			&i.UserPasswordChangedAt,
+			&i.UserAvatarUrl,
+			&i.UserBio,
+			&i.UserTimezone,
+			&i.UserLocale,
+			&i.UserDiscoverable,
+			&i.UserDeactivatedAt,
+			&i.UserSuspendedAt,
+			&i.UserSuspendedUntil,
+			&i.UserSuspensionReason,
+			&i.UserDeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -206,6 +331,25 @@ func (q *Queries) GetRoomMembers(ctx context.Context, roomID int64) ([]User, err
 	return items, nil
 }
 
+const getRoomOwner = `-- name: GetRoomOwner :one
+SELECT user_uuid, room_id, member_role, room_member_created_at, room_member_updated_at, member_notification_level, member_muted_until FROM room_members WHERE room_id = $1 AND member_role = 'Owner' LIMIT 1
+`
+
+func (q *Queries) GetRoomOwner(ctx context.Context, roomID int64) (RoomMember, error) {
+	row := q.db.QueryRow(ctx, getRoomOwner, roomID)
+	var i RoomMember
+	err := row.Scan(
+		&i.UserUuid,
+		&i.RoomID,
+		&i.MemberRole,
+		&i.RoomMemberCreatedAt,
+		&i.RoomMemberUpdatedAt,
+		&i.MemberNotificationLevel,
+		&i.MemberMutedUntil,
+	)
+	return i, err
+}
+
 const isUserMemberOfRoom = `-- name: IsUserMemberOfRoom :one
 SELECT EXISTS (
         SELECT 1
@@ -231,7 +375,7 @@ func (q *Queries) IsUserMemberOfRoom(ctx context.Context, arg IsUserMemberOfRoom
 const joinRoom = `-- name: JoinRoom :one
 INSERT INTO
     room_members (user_uuid, room_id)
-VALUES ($1, $2) RETURNING user_uuid, room_id, member_role, room_member_created_at, room_member_updated_at
+VALUES ($1, $2) RETURNING user_uuid, room_id, member_role, room_member_created_at, room_member_updated_at, member_notification_level, member_muted_until
 `
 
 type JoinRoomParams struct {
@@ -248,13 +392,14 @@ func (q *Queries) JoinRoom(ctx context.Context, arg JoinRoomParams) (RoomMember,
 		&i.MemberRole,
 		&i.RoomMemberCreatedAt,
 		&i.RoomMemberUpdatedAt,
+		&i.MemberNotificationLevel,
+		&i.MemberMutedUntil,
 	)
 	return i, err
 }
 
-const leaveRoom = `-- name: LeaveRoom :exec
-DELETE FROM room_members 
-WHERE user_uuid = $1 AND room_id = $2
+const leaveRoom = `-- name: LeaveRoom :one
+DELETE FROM room_members WHERE user_uuid = $1 AND room_id = $2 RETURNING user_uuid, room_id, member_role, room_member_created_at, room_member_updated_at, member_notification_level, member_muted_until
 `
 
 type LeaveRoomParams struct {
@@ -262,13 +407,130 @@ type LeaveRoomParams struct {
 	RoomID   int64     `json:"room_id"`
 }
 
-func (q *Queries) LeaveRoom(ctx context.Context, arg LeaveRoomParams) error {
-	_, err := q.db.Exec(ctx, leaveRoom, arg.UserUuid, arg.RoomID)
-	return err
+func (q *Queries) LeaveRoom(ctx context.Context, arg LeaveRoomParams) (RoomMember, error) {
+	row := q.db.QueryRow(ctx, leaveRoom, arg.UserUuid, arg.RoomID)
+	var i RoomMember
+	err := row.Scan(
+		&i.UserUuid,
+		&i.RoomID,
+		&i.MemberRole,
+		&i.RoomMemberCreatedAt,
+		&i.RoomMemberUpdatedAt,
+		&i.MemberNotificationLevel,
+		&i.MemberMutedUntil,
+	)
+	return i, err
+}
+
+const listRoomMemberNotificationSettings = `-- name: ListRoomMemberNotificationSettings :many
+SELECT
+    user_uuid,
+    member_notification_level,
+    member_muted_until
+FROM room_members
+WHERE
+    room_id = $1
+`
+
+type ListRoomMemberNotificationSettingsRow struct {
+	UserUuid                uuid.UUID  `json:"user_uuid"`
+	MemberNotificationLevel string     `json:"member_notification_level"`
+	MemberMutedUntil        *time.Time `json:"member_muted_until"`
+}
+
+func (q *Queries) ListRoomMemberNotificationSettings(ctx context.Context, roomID int64) ([]ListRoomMemberNotificationSettingsRow, error) {
+	rows, err := q.db.Query(ctx, listRoomMemberNotificationSettings, roomID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListRoomMemberNotificationSettingsRow{}
+	for rows.Next() {
+		var i ListRoomMemberNotificationSettingsRow
+		if err := rows.Scan(
+			&i.UserUuid,
+			&i.MemberNotificationLevel,
+			&i.MemberMutedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listUserMemberships = `-- name: ListUserMemberships :many
+SELECT r.room_id, r.room_code, r.room_name, r.room_is_direct_chat, r.room_created_by, r.room_created_at, r.room_updated_at, r.room_topic, r.room_description, r.room_avatar_url, r.room_archived_at, r.room_posting_permission, r.room_slow_mode_seconds, rm.member_role, rm.room_member_created_at, rm.member_notification_level, rm.member_muted_until
+FROM rooms r
+    JOIN room_members rm ON r.room_id = rm.room_id
+WHERE
+    rm.user_uuid = $1
+ORDER BY rm.room_member_created_at
+`
+
+type ListUserMembershipsRow struct {
+	RoomID                  int64      `json:"room_id"`
+	RoomCode                string     `json:"room_code"`
+	RoomName                *string    `json:"room_name"`
+	RoomIsDirectChat        bool       `json:"room_is_direct_chat"`
+	RoomCreatedBy           uuid.UUID  `json:"room_created_by"`
+	RoomCreatedAt           time.Time  `json:"room_created_at"`
+	RoomUpdatedAt           time.Time  `json:"room_updated_at"`
+	RoomTopic               *string    `json:"room_topic"`
+	RoomDescription         *string    `json:"room_description"`
+	RoomAvatarUrl           *string    `json:"room_avatar_url"`
+	RoomArchivedAt          *time.Time `json:"room_archived_at"`
+	RoomPostingPermission   string     `json:"room_posting_permission"`
+	RoomSlowModeSeconds     int32      `json:"room_slow_mode_seconds"`
+	MemberRole              string     `json:"member_role"`
+	RoomMemberCreatedAt     time.Time  `json:"room_member_created_at"`
+	MemberNotificationLevel string     `json:"member_notification_level"`
+	MemberMutedUntil        *time.Time `json:"member_muted_until"`
+}
+
+func (q *Queries) ListUserMemberships(ctx context.Context, userUuid uuid.UUID) ([]ListUserMembershipsRow, error) {
+	rows, err := q.db.Query(ctx, listUserMemberships, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []ListUserMembershipsRow{}
+	for rows.Next() {
+		var i ListUserMembershipsRow
+		if err := rows.Scan(
+			&i.RoomID,
+			&i.RoomCode,
+			&i.RoomName,
+			&i.RoomIsDirectChat,
+			&i.RoomCreatedBy,
+			&i.RoomCreatedAt,
+			&i.RoomUpdatedAt,
+			&i.RoomTopic,
+			&i.RoomDescription,
+			&i.RoomAvatarUrl,
+			&i.RoomArchivedAt,
+			&i.RoomPostingPermission,
+			&i.RoomSlowModeSeconds,
+			&i.MemberRole,
+			&i.RoomMemberCreatedAt,
+			&i.MemberNotificationLevel,
+			&i.MemberMutedUntil,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
 }
 
 const listUserRooms = `-- name: ListUserRooms :many
-SELECT r.room_id, r.room_code, r.room_name, r.room_is_direct_chat, r.room_created_by, r.room_created_at, r.room_updated_at
+SELECT r.room_id, r.room_code, r.room_name, r.room_is_direct_chat, r.room_created_by, r.room_created_at, r.room_updated_at, r.room_topic, r.room_description, r.room_avatar_url, r.room_archived_at, r.room_posting_permission, r.room_slow_mode_seconds
 FROM rooms r
     JOIN room_members rm ON r.room_id = rm.room_id
 WHERE
@@ -293,6 +555,12 @@ func (q *Queries) ListUserRooms(ctx context.Context, userUuid uuid.UUID) ([]Room
 			&i.RoomCreatedBy,
 			&i.RoomCreatedAt,
 			&i.RoomUpdatedAt,
+			&i.RoomTopic,
+			&i.RoomDescription,
+			&i.RoomAvatarUrl,
+			&i.RoomArchivedAt,
+			&i.RoomPostingPermission,
+			&i.RoomSlowModeSeconds,
 		); err != nil {
 			return nil, err
 		}
@@ -313,43 +581,86 @@ SELECT
     r.room_created_by,
     r.room_created_at,
     r.room_updated_at,
+    r.room_topic,
+    r.room_description,
+    r.room_avatar_url,
+    r.room_archived_at,
+    r.room_posting_permission,
+    r.room_slow_mode_seconds,
+    rm.member_notification_level,
+    rm.member_muted_until,
     -- Last message info with COALESCE to handle NULL
     COALESCE(lm.message_id, 0) as last_message_id,
     COALESCE(lm.content, '') as last_message_content,
     COALESCE(lm.message_created_at, r.room_created_at) as last_message_time,
     COALESCE(lm.user_uuid, '00000000-0000-0000-0000-000000000000'::uuid) as last_sender_uuid,
-    u.user_fullname as last_sender_name
+    u.user_fullname as last_sender_name,
+    (
+        SELECT COUNT(*)
+        FROM message_mentions mm
+        WHERE
+            mm.room_id = r.room_id
+            AND mm.mentioned_user_uuid = rm.user_uuid
+            AND mm.mention_read_at IS NULL
+    ) as mention_count
 FROM rooms r
 INNER JOIN room_members rm ON r.room_id = rm.room_id
 LEFT JOIN LATERAL (
     SELECT m.message_id, m.content, m.message_created_at, m.user_uuid
     FROM messages m 
     WHERE m.room_id = r.room_id 
+    -- Tin nhắn cuối không tính tin của người mà user đã chặn
+    AND NOT EXISTS (
+        SELECT 1
+        FROM user_blocks b
+        WHERE
+            b.blocker_uuid = rm.user_uuid
+            AND b.blocked_uuid = m.user_uuid
+    )
     ORDER BY m.message_created_at DESC 
     LIMIT 1
 ) lm ON true
 LEFT JOIN users u ON lm.user_uuid = u.user_uuid
-WHERE rm.user_uuid = $1
+WHERE
+    rm.user_uuid = $1
+    AND (
+        $2::boolean
+        OR r.room_archived_at IS NULL
+    )
 ORDER BY COALESCE(lm.message_created_at, r.room_created_at) DESC
 `
 
+type ListUserRoomsWithLastMessageParams struct {
+	UserUuid        uuid.UUID `json:"user_uuid"`
+	IncludeArchived bool      `json:"include_archived"`
+}
+
 type ListUserRoomsWithLastMessageRow struct {
-	RoomID             int64     `json:"room_id"`
-	RoomCode           string    `json:"room_code"`
-	RoomName           *string   `json:"room_name"`
-	RoomIsDirectChat   bool      `json:"room_is_direct_chat"`
-	RoomCreatedBy      uuid.UUID `json:"room_created_by"`
-	RoomCreatedAt      time.Time `json:"room_created_at"`
-	RoomUpdatedAt      time.Time `json:"room_updated_at"`
-	LastMessageID      int64     `json:"last_message_id"`
-	LastMessageContent string    `json:"last_message_content"`
-	LastMessageTime    time.Time `json:"last_message_time"`
-	LastSenderUuid     uuid.UUID `json:"last_sender_uuid"`
-	LastSenderName     *string   `json:"last_sender_name"`
-}
-
-func (q *Queries) ListUserRoomsWithLastMessage(ctx context.Context, userUuid uuid.UUID) ([]ListUserRoomsWithLastMessageRow, error) {
-	rows, err := q.db.Query(ctx, listUserRoomsWithLastMessage, userUuid)
+	RoomID                  int64      `json:"room_id"`
+	RoomCode                string     `json:"room_code"`
+	RoomName                *string    `json:"room_name"`
+	RoomIsDirectChat        bool       `json:"room_is_direct_chat"`
+	RoomCreatedBy           uuid.UUID  `json:"room_created_by"`
+	RoomCreatedAt           time.Time  `json:"room_created_at"`
+	RoomUpdatedAt           time.Time  `json:"room_updated_at"`
+	RoomTopic               *string    `json:"room_topic"`
+	RoomDescription         *string    `json:"room_description"`
+	RoomAvatarUrl           *string    `json:"room_avatar_url"`
+	RoomArchivedAt          *time.Time `json:"room_archived_at"`
+	RoomPostingPermission   string     `json:"room_posting_permission"`
+	RoomSlowModeSeconds     int32      `json:"room_slow_mode_seconds"`
+	MemberNotificationLevel string     `json:"member_notification_level"`
+	MemberMutedUntil        *time.Time `json:"member_muted_until"`
+	LastMessageID           int64      `json:"last_message_id"`
+	LastMessageContent      string     `json:"last_message_content"`
+	LastMessageTime         time.Time  `json:"last_message_time"`
+	LastSenderUuid          uuid.UUID  `json:"last_sender_uuid"`
+	LastSenderName          *string    `json:"last_sender_name"`
+	MentionCount            int64      `json:"mention_count"`
+}
+
+func (q *Queries) ListUserRoomsWithLastMessage(ctx context.Context, arg ListUserRoomsWithLastMessageParams) ([]ListUserRoomsWithLastMessageRow, error) {
+	rows, err := q.db.Query(ctx, listUserRoomsWithLastMessage, arg.UserUuid, arg.IncludeArchived)
 	if err != nil {
 		return nil, err
 	}
@@ -365,11 +676,20 @@ func (q *Queries) ListUserRoomsWithLastMessage(ctx context.Context, userUuid uui
 			&i.RoomCreatedBy,
 			&i.RoomCreatedAt,
 			&i.RoomUpdatedAt,
+			&i.RoomTopic,
+			&i.RoomDescription,
+			&i.RoomAvatarUrl,
+			&i.RoomArchivedAt,
+			&i.RoomPostingPermission,
+			&i.RoomSlowModeSeconds,
+			&i.MemberNotificationLevel,
+			&i.MemberMutedUntil,
 			&i.LastMessageID,
 			&i.LastMessageContent,
 			&i.LastMessageTime,
 			&i.LastSenderUuid,
 			&i.LastSenderName,
+			&i.MentionCount,
 		); err != nil {
 			return nil, err
 		}
@@ -380,3 +700,140 @@ func (q *Queries) ListUserRoomsWithLastMessage(ctx context.Context, userUuid uui
 	}
 	return items, nil
 }
+
+const transferRoomOwnership = `-- name: TransferRoomOwnership :exec
+UPDATE room_members
+SET
+    member_role = CASE
+        WHEN user_uuid = $1 THEN 'Owner'
+        ELSE 'Admin'
+    END
+WHERE
+    room_id = $2
+    AND user_uuid IN ($3, $1)
+`
+
+type TransferRoomOwnershipParams struct {
+	NewOwnerUuid     uuid.UUID `json:"new_owner_uuid"`
+	RoomID           int64     `json:"room_id"`
+	CurrentOwnerUuid uuid.UUID `json:"current_owner_uuid"`
+}
+
+func (q *Queries) TransferRoomOwnership(ctx context.Context, arg TransferRoomOwnershipParams) error {
+	_, err := q.db.Exec(ctx, transferRoomOwnership, arg.NewOwnerUuid, arg.RoomID, arg.CurrentOwnerUuid)
+	return err
+}
+
+const unarchiveRoom = `-- name: UnarchiveRoom :one
+UPDATE rooms SET room_archived_at = NULL WHERE room_id = $1 RETURNING room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at, room_topic, room_description, room_avatar_url, room_archived_at, room_posting_permission, room_slow_mode_seconds
+`
+
+func (q *Queries) UnarchiveRoom(ctx context.Context, roomID int64) (Room, error) {
+	row := q.db.QueryRow(ctx, unarchiveRoom, roomID)
+	var i Room
+	err := row.Scan(
+		&i.RoomID,
+		&i.RoomCode,
+		&i.RoomName,
+		&i.RoomIsDirectChat,
+		&i.RoomCreatedBy,
+		&i.RoomCreatedAt,
+		&i.RoomUpdatedAt,
+		&i.RoomTopic,
+		&i.RoomDescription,
+		&i.RoomAvatarUrl,
+		&i.RoomArchivedAt,
+		&i.RoomPostingPermission,
+		&i.RoomSlowModeSeconds,
+	)
+	return i, err
+}
+
+const updateRoomMemberNotificationSettings = `-- name: UpdateRoomMemberNotificationSettings :one
+UPDATE room_members
+SET
+    member_notification_level = $1,
+    member_muted_until = $2
+WHERE
+    user_uuid = $3
+    AND room_id = $4 RETURNING user_uuid, room_id, member_role, room_member_created_at, room_member_updated_at, member_notification_level, member_muted_until
+`
+
+type UpdateRoomMemberNotificationSettingsParams struct {
+	MemberNotificationLevel string     `json:"member_notification_level"`
+	MemberMutedUntil        *time.Time `json:"member_muted_until"`
+	UserUuid                uuid.UUID  `json:"user_uuid"`
+	RoomID                  int64      `json:"room_id"`
+}
+
+func (q *Queries) UpdateRoomMemberNotificationSettings(ctx context.Context, arg UpdateRoomMemberNotificationSettingsParams) (RoomMember, error) {
+	row := q.db.QueryRow(ctx, updateRoomMemberNotificationSettings,
+		arg.MemberNotificationLevel,
+		arg.MemberMutedUntil,
+		arg.UserUuid,
+		arg.RoomID,
+	)
+	var i RoomMember
+	err := row.Scan(
+		&i.UserUuid,
+		&i.RoomID,
+		&i.MemberRole,
+		&i.RoomMemberCreatedAt,
+		&i.RoomMemberUpdatedAt,
+		&i.MemberNotificationLevel,
+		&i.MemberMutedUntil,
+	)
+	return i, err
+}
+
+const updateRoomSettings = `-- name: UpdateRoomSettings :one
+UPDATE rooms
+SET
+    room_name = COALESCE($1, room_name),
+    room_topic = COALESCE($2, room_topic),
+    room_description = COALESCE($3, room_description),
+    room_avatar_url = COALESCE($4, room_avatar_url),
+    room_posting_permission = COALESCE($5, room_posting_permission),
+    room_slow_mode_seconds = COALESCE($6, room_slow_mode_seconds)
+WHERE
+    room_id = $7 RETURNING room_id, room_code, room_name, room_is_direct_chat, room_created_by, room_created_at, room_updated_at, room_topic, room_description, room_avatar_url, room_archived_at, room_posting_permission, room_slow_mode_seconds
+`
+
+type UpdateRoomSettingsParams struct {
+	RoomName              *string `json:"room_name"`
+	RoomTopic             *string `json:"room_topic"`
+	RoomDescription       *string `json:"room_description"`
+	RoomAvatarUrl         *string `json:"room_avatar_url"`
+	RoomPostingPermission *string `json:"room_posting_permission"`
+	RoomSlowModeSeconds   *int32  `json:"room_slow_mode_seconds"`
+	RoomID                int64   `json:"room_id"`
+}
+
+func (q *Queries) UpdateRoomSettings(ctx context.Context, arg UpdateRoomSettingsParams) (Room, error) {
+	row := q.db.QueryRow(ctx, updateRoomSettings,
+		arg.RoomName,
+		arg.RoomTopic,
+		arg.RoomDescription,
+		arg.RoomAvatarUrl,
+		arg.RoomPostingPermission,
+		arg.RoomSlowModeSeconds,
+		arg.RoomID,
+	)
+	var i Room
+	err := row.Scan(
+		&i.RoomID,
+		&i.RoomCode,
+		&i.RoomName,
+		&i.RoomIsDirectChat,
+		&i.RoomCreatedBy,
+		&i.RoomCreatedAt,
+		&i.RoomUpdatedAt,
+		&i.RoomTopic,
+		&i.RoomDescription,
+		&i.RoomAvatarUrl,
+		&i.RoomArchivedAt,
+		&i.RoomPostingPermission,
+		&i.RoomSlowModeSeconds,
+	)
+	return i, err
+}
@@ -7,6 +7,7 @@ package sqlc
 
 import (
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -18,7 +19,7 @@ INSERT INTO
         user_password,
         user_fullname
     )
-VALUES ($1, $2, $3) RETURNING user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at
+VALUES ($1, $2, $3) RETURNING user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at, user_digest_frequency, user_digest_last_sent_at, user_last_seen_at, user_email_verified_at, user_password_changed_at, user_avatar_url, user_bio, user_timezone, user_locale, user_discoverable, user_deactivated_at, user_suspended_at, user_suspended_until, user_suspension_reason, user_deleted_at
 `
 
 type CreateUserParams struct {
@@ -38,21 +39,27 @@ func (q *Queries) CreateUser(ctx context.Context, arg CreateUserParams) (User, e
 		&i.UserRole,
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
+		&i.UserDigestFrequency,
+		&i.UserDigestLastSentAt,
+		&i.UserLastSeenAt,
+		&i.UserEmailVerifiedAt,
+		&i.UserPasswordChangedAt,
+		&i.UserAvatarUrl,
+		&i.UserBio,
+		&i.UserTimezone,
+		&i.UserLocale,
+		&i.UserDiscoverable,
+		&i.UserDeactivatedAt,
+		&i.UserSuspendedAt,
+		&i.UserSuspendedUntil,
+		&i.UserSuspensionReason,
+		&i.UserDeletedAt,
 	)
 	return i, err
 }
 
-const deleteUser = `-- name: DeleteUser :exec
-DELETE FROM users WHERE user_uuid = $1
-`
-
-func (q *Queries) DeleteUser(ctx context.Context, userUuid uuid.UUID) error {
-	_, err := q.db.Exec(ctx, deleteUser, userUuid)
-	return err
-}
-
 const getAllUsers = `-- name: GetAllUsers :many
-SELECT user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at
+SELECT user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at, user_digest_frequency, user_digest_last_sent_at, user_last_seen_at, user_email_verified_at, user_password_changed_at, user_avatar_url, user_bio, user_timezone, user_locale, user_discoverable, user_deactivated_at, user_suspended_at, user_suspended_until, user_suspension_reason, user_deleted_at
 FROM users
 ORDER BY user_created_at DESC
 LIMIT $1
@@ -82,6 +89,21 @@ func (q *Queries) GetAllUsers(ctx context.Context, arg GetAllUsersParams) ([]Use
 			&i.UserRole,
 			&i.UserCreatedAt,
 			&i.UserUpdatedAt,
+			&i.UserDigestFrequency,
+			&i.UserDigestLastSentAt,
+			&i.UserLastSeenAt,
+			&i.UserEmailVerifiedAt,
+			&i.UserPasswordChangedAt,
+			&i.UserAvatarUrl,
+			&i.UserBio,
+			&i.UserTimezone,
+			&i.UserLocale,
+			&i.UserDiscoverable,
+			&i.UserDeactivatedAt,
+			&i.UserSuspendedAt,
+			&i.UserSuspendedUntil,
+			&i.UserSuspensionReason,
+			&i.UserDeletedAt,
 		); err != nil {
 			return nil, err
 		}
@@ -94,7 +116,7 @@ func (q *Queries) GetAllUsers(ctx context.Context, arg GetAllUsersParams) ([]Use
 }
 
 const getUserByEmail = `-- name: GetUserByEmail :one
-SELECT user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at FROM users WHERE user_email = $1
+SELECT user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at, user_digest_frequency, user_digest_last_sent_at, user_last_seen_at, user_email_verified_at, user_password_changed_at, user_avatar_url, user_bio, user_timezone, user_locale, user_discoverable, user_deactivated_at, user_suspended_at, user_suspended_until, user_suspension_reason, user_deleted_at FROM users WHERE user_email = $1
 `
 
 func (q *Queries) GetUserByEmail(ctx context.Context, userEmail string) (User, error) {
@@ -108,12 +130,27 @@ func (q *Queries) GetUserByEmail(ctx context.Context, userEmail string) (User, e
 		&i.UserRole,
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
+		&i.UserDigestFrequency,
+		&i.UserDigestLastSentAt,
+		&i.UserLastSeenAt,
+		&i.UserEmailVerifiedAt,
+		&i.UserPasswordChangedAt,
+		&i.UserAvatarUrl,
+		&i.UserBio,
+		&i.UserTimezone,
+		&i.UserLocale,
+		&i.UserDiscoverable,
+		&i.UserDeactivatedAt,
+		&i.UserSuspendedAt,
+		&i.UserSuspendedUntil,
+		&i.UserSuspensionReason,
+		&i.UserDeletedAt,
 	)
 	return i, err
 }
 
 const getUserByUUID = `-- name: GetUserByUUID :one
-SELECT user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at FROM users WHERE user_uuid = $1
+SELECT user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at, user_digest_frequency, user_digest_last_sent_at, user_last_seen_at, user_email_verified_at, user_password_changed_at, user_avatar_url, user_bio, user_timezone, user_locale, user_discoverable, user_deactivated_at, user_suspended_at, user_suspended_until, user_suspension_reason, user_deleted_at FROM users WHERE user_uuid = $1
 `
 
 func (q *Queries) GetUserByUUID(ctx context.Context, userUuid uuid.UUID) (User, error) {
@@ -127,6 +164,302 @@ func (q *Queries) GetUserByUUID(ctx context.Context, userUuid uuid.UUID) (User,
 		&i.UserRole,
 		&i.UserCreatedAt,
 		&i.UserUpdatedAt,
+		&i.UserDigestFrequency,
+		&i.UserDigestLastSentAt,
+		&i.UserLastSeenAt,
+		&i.UserEmailVerifiedAt,
+		&i.UserPasswordChangedAt,
+		&i.UserAvatarUrl,
+		&i.UserBio,
+		&i.UserTimezone,
+		&i.UserLocale,
+		&i.UserDiscoverable,
+		&i.UserDeactivatedAt,
+		&i.UserSuspendedAt,
+		&i.UserSuspendedUntil,
+		&i.UserSuspensionReason,
+		&i.UserDeletedAt,
+	)
+	return i, err
+}
+
+const listSharedRoomIDs = `-- name: ListSharedRoomIDs :many
+-- Các phòng của user có ít nhất một thành viên khác
+SELECT rm.room_id
+FROM room_members rm
+WHERE
+    rm.user_uuid = $1
+    AND EXISTS (
+        SELECT 1
+        FROM room_members other
+        WHERE
+            other.room_id = rm.room_id
+            AND other.user_uuid <> rm.user_uuid
+    )
+ORDER BY rm.room_id
+`
+
+func (q *Queries) ListSharedRoomIDs(ctx context.Context, userUuid uuid.UUID) ([]int64, error) {
+	rows, err := q.db.Query(ctx, listSharedRoomIDs, userUuid)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []int64{}
+	for rows.Next() {
+		var room_id int64
+		if err := rows.Scan(&room_id); err != nil {
+			return nil, err
+		}
+		items = append(items, room_id)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const searchUserDirectory = `-- name: SearchUserDirectory :many
+-- Khớp tiền tố (tên, một từ trong tên, email) xếp trước, sau đó là tên gần đúng theo trigram.
+-- Phân trang keyset theo (match_rank, lower(user_fullname), user_uuid); sort_name được trả về để tạo cursor
+-- vì lower() của PostgreSQL và strings.ToLower của Go có thể khác nhau với một số ký tự Unicode.
+SELECT u.user_uuid, u.user_email, u.user_password, u.user_fullname, u.user_role, u.user_created_at, u.user_updated_at, u.user_digest_frequency, u.user_digest_last_sent_at, u.user_last_seen_at, u.user_email_verified_at, u.user_password_changed_at, u.user_avatar_url, u.user_bio, u.user_timezone, u.user_locale, u.user_discoverable, u.user_deactivated_at, u.user_suspended_at, u.user_suspended_until, u.user_suspension_reason, u.user_deleted_at, r.match_rank, lower(u.user_fullname)::text AS sort_name
+FROM users u
+    CROSS JOIN LATERAL (
+        SELECT
+            CASE
+                WHEN lower(u.user_fullname) LIKE $1::text
+                OR lower(u.user_fullname) LIKE '% ' || $1::text
+                OR lower(u.user_email) LIKE $1::text THEN 0
+                ELSE 1
+            END::int AS match_rank
+    ) r
+WHERE
+    u.user_deactivated_at IS NULL
+    AND u.user_discoverable
+    AND u.user_uuid <> $2
+    AND (
+        lower(u.user_fullname) LIKE $1::text
+        OR lower(u.user_fullname) LIKE '% ' || $1::text
+        OR lower(u.user_email) LIKE $1::text
+        OR lower(u.user_fullname) % $3::text
+    )
+    AND (
+        $4::int IS NULL
+        OR (
+            r.match_rank,
+            lower(u.user_fullname),
+            u.user_uuid
+        ) > (
+            $4::int,
+            $5::text,
+            $6::uuid
+        )
+    )
+ORDER BY r.match_rank, lower(u.user_fullname), u.user_uuid
+LIMIT $7
+`
+
+type SearchUserDirectoryParams struct {
+	Prefix        string    `json:"prefix"`
+	RequesterUuid uuid.UUID `json:"requester_uuid"`
+	Query         string    `json:"query"`
+	AfterRank     *int32    `json:"after_rank"`
+	AfterName     *string   `json:"after_name"`
+	AfterUuid     *string   `json:"after_uuid"`
+	Limit         int32     `json:"limit"`
+}
+
+type SearchUserDirectoryRow struct {
+	UserUuid              uuid.UUID  `json:"user_uuid"`
+	UserEmail             string     `json:"user_email"`
+	UserPassword          string     `json:"user_password"`
+	UserFullname          string     `json:"user_fullname"`
+	UserRole              string     `json:"user_role"`
+	UserCreatedAt         time.Time  `json:"user_created_at"`
+	UserUpdatedAt         time.Time  `json:"user_updated_at"`
+	UserDigestFrequency   string     `json:"user_digest_frequency"`
+	UserDigestLastSentAt  *time.Time `json:"user_digest_last_sent_at"`
+	UserLastSeenAt        *time.Time `json:"user_last_seen_at"`
+	UserEmailVerifiedAt   *time.Time `json:"user_email_verified_at"`
+	UserPasswordChangedAt *time.Time `json:"user_password_changed_at"`
+	UserAvatarUrl         *string    `json:"user_avatar_url"`
+	UserBio               *string    `json:"user_bio"`
+	UserTimezone          string     `json:"user_timezone"`
+	UserLocale            string     `json:"user_locale"`
+	UserDiscoverable      bool       `json:"user_discoverable"`
+	UserDeactivatedAt     *time.Time `json:"user_deactivated_at"`
+	UserSuspendedAt       *time.Time `json:"user_suspended_at"`
+	UserSuspendedUntil    *time.Time `json:"user_suspended_until"`
+	UserSuspensionReason  *string    `json:"user_suspension_reason"`
+	UserDeletedAt         *time.Time `json:"user_deleted_at"`
+	MatchRank             int32      `json:"match_rank"`
+	SortName              string     `json:"sort_name"`
+}
+
+func (q *Queries) SearchUserDirectory(ctx context.Context, arg SearchUserDirectoryParams) ([]SearchUserDirectoryRow, error) {
+	rows, err := q.db.Query(ctx, searchUserDirectory,
+		arg.Prefix,
+		arg.RequesterUuid,
+		arg.Query,
+		arg.AfterRank,
+		arg.AfterName,
+		arg.AfterUuid,
+		arg.Limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []SearchUserDirectoryRow{}
+	for rows.Next() {
+		var i SearchUserDirectoryRow
+		if err := rows.Scan(
+			&i.UserUuid,
+			&i.UserEmail,
+			&i.UserPassword,
+			&i.UserFullname,
+			&i.UserRole,
+			&i.UserCreatedAt,
+			&i.UserUpdatedAt,
+			&i.UserDigestFrequency,
+			&i.UserDigestLastSentAt,
+			&i.UserLastSeenAt,
+			&i.UserEmailVerifiedAt,
+			&i.UserPasswordChangedAt,
+			&i.UserAvatarUrl,
+			&i.UserBio,
+			&i.UserTimezone,
+			&i.UserLocale,
+			&i.UserDiscoverable,
+			&i.UserDeactivatedAt,
+			&i.UserSuspendedAt,
+			&i.UserSuspendedUntil,
+			&i.UserSuspensionReason,
+			&i.UserDeletedAt,
+			&i.MatchRank,
+			&i.SortName,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const updateUserPassword = `-- name: UpdateUserPassword :one
+UPDATE users
+SET
+    user_password = $2,
+    user_password_changed_at = NOW(),
+    user_updated_at = NOW()
+WHERE
+    user_uuid = $1 RETURNING user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at, user_digest_frequency, user_digest_last_sent_at, user_last_seen_at, user_email_verified_at, user_password_changed_at, user_avatar_url, user_bio, user_timezone, user_locale, user_discoverable, user_deactivated_at, user_suspended_at, user_suspended_until, user_suspension_reason, user_deleted_at
+`
+
+type UpdateUserPasswordParams struct {
+	UserUuid     uuid.UUID `json:"user_uuid"`
+	UserPassword string    `json:"user_password"`
+}
+
+func (q *Queries) UpdateUserPassword(ctx context.Context, arg UpdateUserPasswordParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserPassword, arg.UserUuid, arg.UserPassword)
+	var i User
+	err := row.Scan(
+		&i.UserUuid,
+		&i.UserEmail,
+		&i.UserPassword,
+		&i.UserFullname,
+		&i.UserRole,
+		&i.UserCreatedAt,
+		&i.UserUpdatedAt,
+		&i.UserDigestFrequency,
+		&i.UserDigestLastSentAt,
+		&i.UserLastSeenAt,
+		&i.UserEmailVerifiedAt,
+		&i.UserPasswordChangedAt,
+		&i.UserAvatarUrl,
+		&i.UserBio,
+		&i.UserTimezone,
+		&i.UserLocale,
+		&i.UserDiscoverable,
+		&i.UserDeactivatedAt,
+		&i.UserSuspendedAt,
+		&i.UserSuspendedUntil,
+		&i.UserSuspensionReason,
+		&i.UserDeletedAt,
+	)
+	return i, err
+}
+
+const updateUserProfile = `-- name: UpdateUserProfile :one
+-- NULL = giữ nguyên, chuỗi rỗng = xóa avatar/bio
+UPDATE users
+SET
+    user_fullname = COALESCE($1, user_fullname),
+    user_avatar_url = CASE
+        WHEN $2::text IS NULL THEN user_avatar_url
+        ELSE NULLIF($2, '')
+    END,
+    user_bio = CASE
+        WHEN $3::text IS NULL THEN user_bio
+        ELSE NULLIF($3, '')
+    END,
+    user_timezone = COALESCE($4, user_timezone),
+    user_locale = COALESCE($5, user_locale),
+    user_discoverable = COALESCE($6, user_discoverable),
+    user_updated_at = NOW()
+WHERE
+    user_uuid = $7 RETURNING user_uuid, user_email, user_password, user_fullname, user_role, user_created_at, user_updated_at, user_digest_frequency, user_digest_last_sent_at, user_last_seen_at, user_email_verified_at, user_password_changed_at, user_avatar_url, user_bio, user_timezone, user_locale, user_discoverable, user_deactivated_at, user_suspended_at, user_suspended_until, user_suspension_reason, user_deleted_at
+`
+
+type UpdateUserProfileParams struct {
+	UserFullname     *string   `json:"user_fullname"`
+	UserAvatarUrl    *string   `json:"user_avatar_url"`
+	UserBio          *string   `json:"user_bio"`
+	UserTimezone     *string   `json:"user_timezone"`
+	UserLocale       *string   `json:"user_locale"`
+	UserDiscoverable *bool     `json:"user_discoverable"`
+	UserUuid         uuid.UUID `json:"user_uuid"`
+}
+
+func (q *Queries) UpdateUserProfile(ctx context.Context, arg UpdateUserProfileParams) (User, error) {
+	row := q.db.QueryRow(ctx, updateUserProfile,
+		arg.UserFullname,
+		arg.UserAvatarUrl,
+		arg.UserBio,
+		arg.UserTimezone,
+		arg.UserLocale,
+		arg.UserDiscoverable,
+		arg.UserUuid,
+	)
+	var i User
+	err := row.Scan(
+		&i.UserUuid,
+		&i.UserEmail,
+		&i.UserPassword,
+		&i.UserFullname,
+		&i.UserRole,
+		&i.UserCreatedAt,
+		&i.UserUpdatedAt,
+		&i.UserDigestFrequency,
+		&i.UserDigestLastSentAt,
+		&i.UserLastSeenAt,
+		&i.UserEmailVerifiedAt,
+		&i.UserPasswordChangedAt,
+		&i.UserAvatarUrl,
+		&i.UserBio,
+		&i.UserTimezone,
+		&i.UserLocale,
+		&i.UserDiscoverable,
+		&i.UserDeactivatedAt,
+		&i.UserSuspendedAt,
+		&i.UserSuspendedUntil,
+		&i.UserSuspensionReason,
+		&i.UserDeletedAt,
 	)
 	return i, err
 }
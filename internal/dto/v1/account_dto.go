@@ -0,0 +1,39 @@
+package v1Dto
+
+import (
+	"chat-app/internal/db/sqlc"
+	"time"
+)
+
+// Trạng thái tài khoản
+const (
+	AccountStatusActive      = "active"
+	AccountStatusSuspended   = "suspended"
+	AccountStatusDeactivated = "deactivated"
+	AccountStatusDeleted     = "deleted"
+)
+
+// SuspendUserInput tạm khóa tài khoản, không có until thì khóa đến khi admin mở
+type SuspendUserInput struct {
+	Reason *string    `json:"reason" binding:"omitempty,max=500"`
+	Until  *time.Time `json:"until"`
+}
+
+// DeleteAccountInput xác nhận mật khẩu trước khi xóa tài khoản của chính mình
+type DeleteAccountInput struct {
+	Password string `json:"password" binding:"required"`
+}
+
+// AccountStatus trả về trạng thái hiện tại của tài khoản, tạm khóa đã hết hạn được tính là active
+func AccountStatus(user sqlc.User) string {
+	switch {
+	case user.UserDeletedAt != nil:
+		return AccountStatusDeleted
+	case user.UserDeactivatedAt != nil:
+		return AccountStatusDeactivated
+	case user.UserSuspendedAt != nil && (user.UserSuspendedUntil == nil || user.UserSuspendedUntil.After(time.Now())):
+		return AccountStatusSuspended
+	default:
+		return AccountStatusActive
+	}
+}
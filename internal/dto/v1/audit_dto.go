@@ -0,0 +1,84 @@
+package v1Dto
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Hành động được ghi vào audit log
+const (
+	AuditActionLogin                = "auth.login"
+	AuditActionLoginFailed          = "auth.login_failed"
+	AuditActionLogout               = "auth.logout"
+	AuditActionPasswordChanged      = "auth.password_changed"
+	AuditActionPasswordReset        = "auth.password_reset"
+	AuditActionUserSuspended        = "user.suspended"
+	AuditActionUserUnsuspended      = "user.unsuspended"
+	AuditActionUserDeactivated      = "user.deactivated"
+	AuditActionUserReactivated      = "user.reactivated"
+	AuditActionUserDeleted          = "user.deleted"
+	AuditActionUserExportRequested  = "user.data_export_requested"
+	AuditActionRoomDeleted          = "room.deleted"
+	AuditActionRoomOwnershipChanged = "room.ownership_transferred"
+	AuditActionImportCompleted      = "import.completed"
+	AuditActionAuditExported        = "audit.exported"
+)
+
+// Loại đối tượng bị tác động
+const (
+	AuditTargetUser   = "user"
+	AuditTargetRoom   = "room"
+	AuditTargetImport = "import"
+)
+
+// Định dạng xuất audit log
+const (
+	AuditExportFormatNDJSON = "ndjson"
+	AuditExportFormatCSV    = "csv"
+)
+
+// AuditFilter là các điều kiện lọc dùng chung cho xem và xuất audit log
+type AuditFilter struct {
+	ActorUUID  *string    `form:"actor_uuid" json:"actor_uuid,omitempty" binding:"omitempty,uuid"`
+	Action     *string    `form:"action" json:"action,omitempty" binding:"omitempty,max=50"`
+	TargetType *string    `form:"target_type" json:"target_type,omitempty" binding:"omitempty,max=20"`
+	TargetID   *string    `form:"target_id" json:"target_id,omitempty" binding:"omitempty,max=100"`
+	From       *time.Time `form:"from" json:"from,omitempty" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" json:"to,omitempty" time_format:"2006-01-02T15:04:05Z07:00"`
+}
+
+// AuditQuery là query string của GET /admin/audit
+type AuditQuery struct {
+	AuditFilter
+	Cursor *int64 `form:"cursor" binding:"omitempty,min=1"` // audit_id cuối cùng của trang trước
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// AuditExportQuery là query string của GET /admin/audit/export, mặc định ndjson
+type AuditExportQuery struct {
+	AuditFilter
+	Format string `form:"format" binding:"omitempty,oneof=ndjson csv"`
+}
+
+// AuditExportFile là tên file và Content-Type của file xuất audit log
+type AuditExportFile struct {
+	FileName    string
+	ContentType string
+}
+
+// AuditEventDTO là một bản ghi audit log
+type AuditEventDTO struct {
+	AuditID    int64           `json:"audit_id"`
+	ActorUUID  *uuid.UUID      `json:"actor_uuid"`
+	ActorEmail *string         `json:"actor_email,omitempty"`
+	ActorName  *string         `json:"actor_name,omitempty"`
+	Action     string          `json:"action"`
+	TargetType *string         `json:"target_type"`
+	TargetID   *string         `json:"target_id"`
+	IP         *string         `json:"ip"`
+	UserAgent  *string         `json:"user_agent"`
+	Metadata   json.RawMessage `json:"metadata"`
+	CreatedAt  time.Time       `json:"created_at"`
+}
@@ -0,0 +1,13 @@
+package v1Dto
+
+import "time"
+
+type BlockUserInput struct {
+	UserUUID string `json:"user_uuid" binding:"required,uuid"`
+}
+
+// BlockedUserDTO là một người trong danh sách chặn của user
+type BlockedUserDTO struct {
+	User      UserProfileDTO `json:"user"`
+	BlockedAt time.Time      `json:"blocked_at"`
+}
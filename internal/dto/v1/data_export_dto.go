@@ -0,0 +1,34 @@
+package v1Dto
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Trạng thái job xuất dữ liệu (khớp với chk_data_exports_status)
+const (
+	DataExportStatusPending    = "pending"
+	DataExportStatusProcessing = "processing"
+	DataExportStatusCompleted  = "completed"
+	DataExportStatusFailed     = "failed"
+	DataExportStatusExpired    = "expired"
+)
+
+// DataExportDTO là trạng thái một job xuất dữ liệu cá nhân
+type DataExportDTO struct {
+	ExportID    int64      `json:"export_id"`
+	UserUUID    uuid.UUID  `json:"user_uuid"`
+	RequestedBy *uuid.UUID `json:"requested_by,omitempty"` // admin yêu cầu thay cho user
+	Status      string     `json:"status"`
+	SizeBytes   *int64     `json:"size_bytes,omitempty"`
+	Error       *string    `json:"error,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	StartedAt   *time.Time `json:"started_at,omitempty"`
+	CompletedAt *time.Time `json:"completed_at,omitempty"`
+	ExpiresAt   *time.Time `json:"expires_at,omitempty"` // sau thời điểm này file ZIP bị xóa
+
+	// Chỉ có khi status = completed, URL tải có chữ ký không cần header Authorization
+	DownloadURL          *string    `json:"download_url,omitempty"`
+	DownloadURLExpiresAt *time.Time `json:"download_url_expires_at,omitempty"`
+}
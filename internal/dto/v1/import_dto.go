@@ -0,0 +1,26 @@
+package v1Dto
+
+// ImportInput là các trường form đi kèm file ZIP (source khớp với chk_imported_rooms_source)
+type ImportInput struct {
+	Source string `form:"source" binding:"required,oneof=slack discord"`
+}
+
+// ImportResultDTO là kết quả một lần import. Chạy lại cùng file thì MessagesImported = 0
+type ImportResultDTO struct {
+	Source            string                  `json:"source"`
+	RoomsCreated      int                     `json:"rooms_created"`
+	RoomsExisting     int                     `json:"rooms_existing"` // đã được tạo ở lần import trước
+	MessagesImported  int64                   `json:"messages_imported"`
+	MessagesSkipped   int                     `json:"messages_skipped"`   // đã có từ lần import trước
+	MessagesUnmapped  int                     `json:"messages_unmapped"`  // người gửi không có tài khoản trùng email
+	MessagesTruncated int                     `json:"messages_truncated"` // dài hơn 2000 ký tự nên bị cắt
+	UnmappedUsers     []ImportUnmappedUserDTO `json:"unmapped_users"`
+}
+
+// ImportUnmappedUserDTO là người gửi trong file export không ghép được với user nào
+type ImportUnmappedUserDTO struct {
+	ExternalID string `json:"external_id"`
+	Name       string `json:"name,omitempty"`
+	Email      string `json:"email,omitempty"`
+	Messages   int    `json:"messages"` // số tin nhắn bị bỏ qua
+}
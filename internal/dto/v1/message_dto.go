@@ -0,0 +1,86 @@
+package v1Dto
+
+import "time"
+
+// SearchMessagesQuery là query string của GET /search/messages
+type SearchMessagesQuery struct {
+	Query      string     `form:"q" binding:"required,max=200"`
+	RoomID     *int64     `form:"room_id" binding:"omitempty,min=1"`
+	SenderUUID *string    `form:"sender_uuid" binding:"omitempty,uuid"`
+	From       *time.Time `form:"from" time_format:"2006-01-02T15:04:05Z07:00"`
+	To         *time.Time `form:"to" time_format:"2006-01-02T15:04:05Z07:00"`
+	Cursor     *int64     `form:"cursor" binding:"omitempty,min=1"` // message_id cuối cùng của trang trước
+	Limit      int32      `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type MessageSearchResult struct {
+	MessageID        int64     `json:"message_id"`
+	RoomID           int64     `json:"room_id"`
+	RoomName         *string   `json:"room_name"`
+	UserUUID         string    `json:"user_uuid"`
+	UserFullname     string    `json:"user_fullname"`
+	Content          string    `json:"content"`
+	Snippet          string    `json:"snippet"` // HTML đã escape, từ khớp được bọc trong <mark></mark>
+	MessageCreatedAt time.Time `json:"message_created_at"`
+}
+
+// Định dạng xuất lịch sử phòng
+const (
+	RoomExportFormatNDJSON = "ndjson"
+	RoomExportFormatCSV    = "csv"
+	RoomExportFormatHTML   = "html"
+)
+
+// RoomExportQuery chọn định dạng file xuất, mặc định ndjson
+type RoomExportQuery struct {
+	Format string `form:"format" binding:"omitempty,oneof=ndjson csv html"`
+}
+
+// RoomExportFile là tên file và Content-Type của file xuất lịch sử phòng
+type RoomExportFile struct {
+	FileName    string
+	ContentType string
+}
+
+// MessagePageQuery phân trang lịch sử tin nhắn theo message_id (keyset).
+// Before và After loại trừ nhau; không truyền cả hai = trang mới nhất.
+type MessagePageQuery struct {
+	Before *int64
+	After  *int64
+	Limit  int32
+}
+
+// MessageContextPagination cho phép cuộn tiếp cả hai chiều quanh tin nhắn được chọn.
+// BeforeCursor dùng với ?before=, AfterCursor dùng với ?after= của GET /rooms/:roomID/messages
+type MessageContextPagination struct {
+	TargetMessageID int64  `json:"target_message_id"`
+	BeforeCursor    *int64 `json:"before_cursor"`
+	HasMoreBefore   bool   `json:"has_more_before"`
+	AfterCursor     *int64 `json:"after_cursor"`
+	HasMoreAfter    bool   `json:"has_more_after"`
+}
+
+type AttachmentDTO struct {
+	AttachmentID int64     `json:"attachment_id"`
+	MessageID    int64     `json:"message_id"`
+	FileName     string    `json:"file_name"`
+	ContentType  string    `json:"content_type"`
+	SizeBytes    int64     `json:"size_bytes"`
+	URL          string    `json:"url"` // URL tải có chữ ký, không cần header Authorization
+	URLExpiresAt time.Time `json:"url_expires_at"`
+
+	// Chỉ có với ảnh, được điền sau khi worker xử lý xong (sự kiện attachment_processed)
+	Processed       bool    `json:"processed"`
+	ImageWidth      *int32  `json:"image_width,omitempty"`
+	ImageHeight     *int32  `json:"image_height,omitempty"`
+	ThumbnailURL    *string `json:"thumbnail_url,omitempty"`
+	ThumbnailWidth  *int32  `json:"thumbnail_width,omitempty"`
+	ThumbnailHeight *int32  `json:"thumbnail_height,omitempty"`
+}
+
+// CursorPagination được trả về trong trường "pagination" của APIResponse
+type CursorPagination struct {
+	NextCursor *int64 `json:"next_cursor"` // nil khi không còn dữ liệu
+	HasMore    bool   `json:"has_more"`
+	Limit      int32  `json:"limit"`
+}
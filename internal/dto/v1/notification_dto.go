@@ -0,0 +1,36 @@
+package v1Dto
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// NotificationQuery là query string của GET /notifications
+type NotificationQuery struct {
+	UnreadOnly bool   `form:"unread_only"`
+	Cursor     *int64 `form:"cursor" binding:"omitempty,min=1"` // notification_id cuối cùng của trang trước
+	Limit      int32  `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+type NotificationDTO struct {
+	NotificationID   int64           `json:"notification_id"`
+	NotificationType string          `json:"notification_type"` // mention
+	RoomID           *int64          `json:"room_id,omitempty"`
+	MessageID        *int64          `json:"message_id,omitempty"`
+	ActorUUID        *string         `json:"actor_uuid,omitempty"`
+	Data             json.RawMessage `json:"data"`
+	IsRead           bool            `json:"is_read"`
+	ReadAt           *time.Time      `json:"read_at"`
+	CreatedAt        time.Time       `json:"created_at"`
+}
+
+// DigestSettingsInput là body của PUT /users/me/email-digest, "never" để tắt email digest
+type DigestSettingsInput struct {
+	Frequency string `json:"frequency" binding:"required,oneof=never hourly daily weekly"`
+}
+
+type DigestSettingsDTO struct {
+	Frequency  string     `json:"frequency"`
+	Enabled    bool       `json:"enabled"`
+	LastSentAt *time.Time `json:"last_sent_at"`
+}
@@ -0,0 +1,20 @@
+package v1Dto
+
+import "time"
+
+// RegisterPushDeviceInput đăng ký thiết bị nhận push.
+// Web Push: token là endpoint của PushSubscription, p256dh/auth lấy từ subscription.keys
+type RegisterPushDeviceInput struct {
+	Platform string `json:"platform" binding:"required,oneof=webpush fcm apns"`
+	Token    string `json:"token" binding:"required,max=2048"`
+	P256dh   string `json:"p256dh" binding:"required_if=Platform webpush"`
+	Auth     string `json:"auth" binding:"required_if=Platform webpush"`
+}
+
+type PushDeviceDTO struct {
+	DeviceID   int64     `json:"device_id"`
+	Platform   string    `json:"platform"`
+	Token      string    `json:"token"`
+	CreatedAt  time.Time `json:"created_at"`
+	LastUsedAt time.Time `json:"last_used_at"`
+}
@@ -3,13 +3,25 @@ package v1Dto
 import "time"
 
 type RoomWithLastMessage struct {
-	RoomID           int64     `json:"room_id"`
-	RoomCode         string    `json:"room_code"`
-	RoomName         *string   `json:"room_name"`
-	RoomIsDirectChat bool      `json:"room_is_direct_chat"`
-	RoomCreatedBy    string    `json:"room_created_by"`
-	RoomCreatedAt    time.Time `json:"room_created_at"`
-	RoomUpdatedAt    time.Time `json:"room_updated_at"`
+	RoomID                int64      `json:"room_id"`
+	RoomCode              string     `json:"room_code"`
+	RoomName              *string    `json:"room_name"`
+	RoomIsDirectChat      bool       `json:"room_is_direct_chat"`
+	RoomCreatedBy         string     `json:"room_created_by"`
+	RoomCreatedAt         time.Time  `json:"room_created_at"`
+	RoomUpdatedAt         time.Time  `json:"room_updated_at"`
+	RoomTopic             *string    `json:"room_topic"`
+	RoomDescription       *string    `json:"room_description"`
+	RoomAvatarURL         *string    `json:"room_avatar_url"`
+	RoomArchivedAt        *time.Time `json:"room_archived_at"`        // NULL = phòng đang hoạt động
+	RoomPostingPermission string     `json:"room_posting_permission"` // everyone | admins
+	RoomSlowModeSeconds   int32      `json:"room_slow_mode_seconds"`  // 0 = tắt
+	MentionCount          int64      `json:"mention_count"`           // Số lượt nhắc (@) chưa đọc
+
+	// Tùy chọn thông báo của user hiện tại với phòng
+	NotificationLevel string     `json:"notification_level"` // all | mentions | none
+	MutedUntil        *time.Time `json:"muted_until"`        // NULL = không tắt
+	IsMuted           bool       `json:"is_muted"`
 
 	// Last message info
 	LastMessage *LastMessageInfo `json:"last_message,omitempty"`
@@ -31,6 +43,29 @@ type MessageWithUser struct {
 	UserFullname     string    `json:"user_fullname"`
 	UserEmail        string    `json:"user_email"`
 	Content          string    `json:"content"`
+	MessageType      string    `json:"message_type"` // 'text' hoặc 'system'
 	MessageCreatedAt time.Time `json:"created_at"`
 	IsOwn            bool      `json:"is_own"` // Tin nhắn của chính user này
+
+	Attachments []AttachmentDTO `json:"attachments,omitempty"`
+}
+
+// UpdateRoomInput chỉ cập nhật các trường được gửi lên (nil = giữ nguyên, "" = xóa nội dung)
+type UpdateRoomInput struct {
+	RoomName              *string `json:"room_name" binding:"omitempty,min=1,max=255"`
+	RoomTopic             *string `json:"room_topic" binding:"omitempty,max=255"`
+	RoomDescription       *string `json:"room_description" binding:"omitempty,max=1000"`
+	RoomAvatarURL         *string `json:"room_avatar_url" binding:"omitempty,url,max=500"`
+	RoomPostingPermission *string `json:"room_posting_permission" binding:"omitempty,oneof=everyone admins"`
+	RoomSlowModeSeconds   *int32  `json:"room_slow_mode_seconds" binding:"omitempty,min=0,max=21600"` // 0 = tắt
+}
+
+// UpdateNotificationSettingsInput thay thế toàn bộ tùy chọn thông báo của user với phòng
+type UpdateNotificationSettingsInput struct {
+	NotificationLevel string     `json:"notification_level" binding:"required,oneof=all mentions none"`
+	MutedUntil        *time.Time `json:"muted_until"` // null = bỏ tắt thông báo
+}
+
+type TransferOwnershipInput struct {
+	NewOwnerUUID string `json:"new_owner_uuid" binding:"required,uuid"`
 }
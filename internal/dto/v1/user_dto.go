@@ -1,6 +1,9 @@
 package v1Dto
 
-import "chat-app/internal/db/sqlc"
+import (
+	"chat-app/internal/db/sqlc"
+	"time"
+)
 
 // UserDTO để trả về thông tin người dùng mà không bao gồm mật khẩu, hoặc các thông tin nhạy cảm khác
 type UserDTO struct {
@@ -8,6 +11,15 @@ type UserDTO struct {
 	Name      string `json:"full_name"`
 	Email     string `json:"email_address"`
 	Role      string `json:"role"`
+	EmailVerified bool `json:"email_verified"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	Bio       *string `json:"bio,omitempty"`
+	Timezone  string `json:"timezone"`
+	Locale    string `json:"locale"`
+	Discoverable bool `json:"discoverable"`
+	Status    string `json:"status"` // active | suspended | deactivated | deleted
+	SuspendedUntil *time.Time `json:"suspended_until,omitempty"`
+	SuspensionReason *string `json:"suspension_reason,omitempty"`
 	CreatedAt string `json:"created_at"`
 	UpdatedAt string `json:"updated_at,omitempty"`
 }
@@ -18,6 +30,40 @@ type CreateUserInput struct {
 	Password string `json:"user_password" binding:"required,password_strong"`
 }
 
+// UpdateProfileInput chỉ cập nhật các trường được gửi lên (nil = giữ nguyên, "" = xóa avatar/bio)
+type UpdateProfileInput struct {
+	Name      *string `json:"user_fullname" binding:"omitempty,min=2,max=100"`
+	AvatarURL *string `json:"user_avatar_url" binding:"omitempty,max=500,url_or_empty"`
+	Bio       *string `json:"user_bio" binding:"omitempty,max=500"`
+	Timezone  *string `json:"user_timezone" binding:"omitempty,timezone,max=64"`
+	Locale    *string `json:"user_locale" binding:"omitempty,locale,max=35"`
+	Discoverable *bool `json:"user_discoverable"` // false = không hiện trong danh bạ người dùng
+}
+
+// UserDirectoryQuery là query string của GET /users/directory
+type UserDirectoryQuery struct {
+	Query  string `form:"q" binding:"required,min=2,max=100"`
+	Cursor string `form:"cursor" binding:"omitempty,max=500"` // next_cursor của trang trước
+	Limit  int32  `form:"limit" binding:"omitempty,min=1,max=50"`
+}
+
+// DirectoryPagination giống CursorPagination nhưng cursor là chuỗi mờ (opaque)
+type DirectoryPagination struct {
+	NextCursor *string `json:"next_cursor"` // nil khi không còn dữ liệu
+	HasMore    bool    `json:"has_more"`
+	Limit      int32   `json:"limit"`
+}
+
+// UserProfileDTO là hồ sơ công khai gửi cho người dùng khác (không có email, vai trò)
+type UserProfileDTO struct {
+	UUID      string  `json:"uuid"`
+	Name      string  `json:"full_name"`
+	AvatarURL *string `json:"avatar_url,omitempty"`
+	Bio       *string `json:"bio,omitempty"`
+	Timezone  string  `json:"timezone"`
+	Locale    string  `json:"locale"`
+}
+
 func (input *CreateUserInput) MapCreateInputToModel() sqlc.CreateUserParams {
 	return sqlc.CreateUserParams{
 		UserEmail:    input.Email,
@@ -27,14 +73,26 @@ func (input *CreateUserInput) MapCreateInputToModel() sqlc.CreateUserParams {
 
 }
 func MapUserToDTO(user sqlc.User) *UserDTO {
-	return &UserDTO{
+	dto := &UserDTO{
 		UUID:      user.UserUuid.String(),
 		Name:      user.UserFullname,
 		Email:     user.UserEmail,
 		Role:      user.UserRole,
+		EmailVerified: user.UserEmailVerifiedAt != nil,
+		AvatarURL: user.UserAvatarUrl,
+		Bio:       user.UserBio,
+		Timezone:  user.UserTimezone,
+		Locale:    user.UserLocale,
+		Discoverable: user.UserDiscoverable,
+		Status:    AccountStatus(user),
 		UpdatedAt: user.UserUpdatedAt.String(),
 		CreatedAt: user.UserCreatedAt.String(),
 	}
+	if dto.Status == AccountStatusSuspended {
+		dto.SuspendedUntil = user.UserSuspendedUntil
+		dto.SuspensionReason = user.UserSuspensionReason
+	}
+	return dto
 }
 
 func MapUsersToDTO(users []sqlc.User) []UserDTO {
@@ -46,3 +104,13 @@ func MapUsersToDTO(users []sqlc.User) []UserDTO {
 	return dtoUsers
 }
 
+func MapUserToProfileDTO(user sqlc.User) UserProfileDTO {
+	return UserProfileDTO{
+		UUID:      user.UserUuid.String(),
+		Name:      user.UserFullname,
+		AvatarURL: user.UserAvatarUrl,
+		Bio:       user.UserBio,
+		Timezone:  user.UserTimezone,
+		Locale:    user.UserLocale,
+	}
+}
\ No newline at end of file
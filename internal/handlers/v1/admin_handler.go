@@ -2,22 +2,30 @@ package v1Handler
 
 import (
 	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
 	"chat-app/internal/services/v1"
 	"chat-app/internal/utils"
+	"chat-app/internal/validation"
+	wsmanager "chat-app/pkg/websocket"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AdminHandler struct {
-	userService services.UserService
-	roomService services.RoomService
+	userService    services.UserService
+	roomService    services.RoomService
+	accountService services.AccountService
+	manager        *wsmanager.Manager
 }
 
-func NewAdminHandler(userService services.UserService, roomService services.RoomService) *AdminHandler {
+func NewAdminHandler(userService services.UserService, roomService services.RoomService, accountService services.AccountService, manager *wsmanager.Manager) *AdminHandler {
 	return &AdminHandler{
-		userService: userService,
-		roomService: roomService,
+		userService:    userService,
+		roomService:    roomService,
+		accountService: accountService,
+		manager:        manager,
 	}
 }
 
@@ -87,13 +95,19 @@ func (ah *AdminHandler) GetAllRooms(c *gin.Context) {
 	for _, roomData := range rooms {
 		adminRoom := AdminRoomResponse{
 			Room: sqlc.Room{
-				RoomID:           roomData.RoomID,
-				RoomCode:         roomData.RoomCode,
-				RoomName:         roomData.RoomName,
-				RoomIsDirectChat: roomData.RoomIsDirectChat,
-				RoomCreatedBy:    roomData.RoomCreatedBy,
-				RoomCreatedAt:    roomData.RoomCreatedAt,
-				RoomUpdatedAt:    roomData.RoomUpdatedAt,
+				RoomID:                roomData.RoomID,
+				RoomCode:              roomData.RoomCode,
+				RoomName:              roomData.RoomName,
+				RoomIsDirectChat:      roomData.RoomIsDirectChat,
+				RoomCreatedBy:         roomData.RoomCreatedBy,
+				RoomCreatedAt:         roomData.RoomCreatedAt,
+				RoomUpdatedAt:         roomData.RoomUpdatedAt,
+				RoomTopic:             roomData.RoomTopic,
+				RoomDescription:       roomData.RoomDescription,
+				RoomAvatarUrl:         roomData.RoomAvatarUrl,
+				RoomArchivedAt:        roomData.RoomArchivedAt,
+				RoomPostingPermission: roomData.RoomPostingPermission,
+				RoomSlowModeSeconds:   roomData.RoomSlowModeSeconds,
 			},
 			MemberCount: int(roomData.MemberCount),
 		}
@@ -148,7 +162,7 @@ func (ah *AdminHandler) GetRoomDetails(c *gin.Context) {
 
 // DeleteUser godoc
 // @Summary [Admin] Delete user
-// @Description Delete a user account (Admin only)
+// @Description Erase a user account (Admin only). Personal data is removed and the account is anonymised; messages stay in their rooms attributed to "Deleted user"
 // @Tags admin
 // @Param userID path string true "User UUID"
 // @Success 200 {object} utils.Response
@@ -157,15 +171,12 @@ func (ah *AdminHandler) GetRoomDetails(c *gin.Context) {
 // @Failure 404 {object} utils.ErrorResponse
 // @Router /api/v1/admin/users/{userID} [delete]
 func (ah *AdminHandler) DeleteUser(c *gin.Context) {
-	userID := c.Param("user_uuid")
-	if userID == "" {
-		utils.ResponseError(c, utils.NewError("user_uuid is required", utils.ErrorCodeBadRequest))
+	actorUUID, userUUID, ok := ah.parseUserAction(c)
+	if !ok {
 		return
 	}
 
-	// Delete user
-	err := ah.userService.DeleteUser(c, userID)
-	if err != nil {
+	if err := ah.accountService.DeleteUser(c, actorUUID, userUUID); err != nil {
 		utils.ResponseError(c, err)
 		return
 	}
@@ -173,6 +184,138 @@ func (ah *AdminHandler) DeleteUser(c *gin.Context) {
 	utils.ResponseSuccess(c, "User deleted successfully", nil)
 }
 
+// SuspendUser godoc
+// @Summary [Admin] Suspend user
+// @Description Block login, API and WebSocket access until the given time, or until unsuspended when until is omitted (Admin only). Open connections are closed
+// @Tags admin
+// @Accept json
+// @Produce json
+// @Param userID path string true "User UUID"
+// @Param request body v1Dto.SuspendUserInput true "Reason and end of suspension"
+// @Success 200 {object} utils.Response{data=v1Dto.UserDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/admin/users/{userID}/suspend [post]
+func (ah *AdminHandler) SuspendUser(c *gin.Context) {
+	actorUUID, userUUID, ok := ah.parseUserAction(c)
+	if !ok {
+		return
+	}
+
+	var req v1Dto.SuspendUserInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(c, validation.HandleValidationError(err))
+		return
+	}
+
+	user, err := ah.accountService.SuspendUser(c, actorUUID, userUUID, req)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "User suspended successfully", v1Dto.MapUserToDTO(user))
+}
+
+// UnsuspendUser godoc
+// @Summary [Admin] Lift a suspension
+// @Tags admin
+// @Produce json
+// @Param userID path string true "User UUID"
+// @Success 200 {object} utils.Response{data=v1Dto.UserDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/admin/users/{userID}/suspend [delete]
+func (ah *AdminHandler) UnsuspendUser(c *gin.Context) {
+	actorUUID, userUUID, ok := ah.parseUserAction(c)
+	if !ok {
+		return
+	}
+
+	user, err := ah.accountService.UnsuspendUser(c, actorUUID, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "User unsuspended successfully", v1Dto.MapUserToDTO(user))
+}
+
+// DeactivateUser godoc
+// @Summary [Admin] Deactivate user
+// @Description Disable the account indefinitely without removing any data (Admin only). Open connections are closed
+// @Tags admin
+// @Produce json
+// @Param userID path string true "User UUID"
+// @Success 200 {object} utils.Response{data=v1Dto.UserDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/admin/users/{userID}/deactivate [post]
+func (ah *AdminHandler) DeactivateUser(c *gin.Context) {
+	actorUUID, userUUID, ok := ah.parseUserAction(c)
+	if !ok {
+		return
+	}
+
+	user, err := ah.accountService.DeactivateUser(c, actorUUID, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "User deactivated successfully", v1Dto.MapUserToDTO(user))
+}
+
+// ReactivateUser godoc
+// @Summary [Admin] Reactivate user
+// @Tags admin
+// @Produce json
+// @Param userID path string true "User UUID"
+// @Success 200 {object} utils.Response{data=v1Dto.UserDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/admin/users/{userID}/reactivate [post]
+func (ah *AdminHandler) ReactivateUser(c *gin.Context) {
+	actorUUID, userUUID, ok := ah.parseUserAction(c)
+	if !ok {
+		return
+	}
+
+	user, err := ah.accountService.ReactivateUser(c, actorUUID, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "User reactivated successfully", v1Dto.MapUserToDTO(user))
+}
+
+// DisconnectAccount đóng mọi kết nối websocket của user khi tài khoản bị tạm khóa, vô hiệu hóa hoặc xóa
+func (ah *AdminHandler) DisconnectAccount(userUUID uuid.UUID, status string) {
+	disconnectUser(ah.manager, userUUID, "account_"+status)
+}
+
+// parseUserAction lấy admin đang thao tác và user bị tác động từ path, tự trả lỗi nếu không hợp lệ
+func (ah *AdminHandler) parseUserAction(c *gin.Context) (uuid.UUID, uuid.UUID, bool) {
+	userUUID, err := uuid.Parse(c.Param("user_uuid"))
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid user ID", utils.ErrorCodeBadRequest))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	actorUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return uuid.Nil, uuid.Nil, false
+	}
+
+	return actorUUID, userUUID, true
+}
+
 // DeleteRoom godoc
 // @Summary [Admin] Delete room
 // @Description Delete a room and all its messages (Admin only)
@@ -198,5 +341,9 @@ func (ah *AdminHandler) DeleteRoom(c *gin.Context) {
 		return
 	}
 
+	if adminUUID, err := utils.GetUserUUID(c); err == nil {
+		ah.manager.CloseRoom(roomDeletedEvent(roomID, adminUUID))
+	}
+
 	utils.ResponseSuccess(c, "Room deleted successfully", nil)
 }
@@ -0,0 +1,202 @@
+package v1Handler
+
+import (
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
+	wsmanager "chat-app/pkg/websocket"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AttachmentHandler struct {
+	attachmentService services.AttachmentService
+	userService       services.UserService
+	manager           *wsmanager.Manager
+	maxSizeBytes      int64
+}
+
+func NewAttachmentHandler(attachmentService services.AttachmentService, userService services.UserService, manager *wsmanager.Manager, maxSizeBytes int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		attachmentService: attachmentService,
+		userService:       userService,
+		manager:           manager,
+		maxSizeBytes:      maxSizeBytes,
+	}
+}
+
+// UploadAttachment godoc
+// @Summary Upload an attachment
+// @Description Upload a file to a room. Creates a message (optional caption in "content") with the file attached and broadcasts it as new_message
+// @Tags attachments
+// @Accept multipart/form-data
+// @Produce json
+// @Param roomID path int true "Room ID"
+// @Param file formData file true "File (images, pdf, text, mp4, mp3, zip/office)"
+// @Param content formData string false "Caption"
+// @Success 200 {object} utils.Response{data=v1Dto.MessageWithUser}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID}/attachments [post]
+func (ah *AttachmentHandler) UploadAttachment(c *gin.Context) {
+	roomIDStr := c.Param("roomID")
+	roomID, err := strconv.ParseInt(roomIDStr, 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	// Chặn body quá lớn trước khi gin parse multipart (cộng thêm 1MB cho các trường khác)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, ah.maxSizeBytes+1<<20)
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ResponseError(c, utils.NewError(fmt.Sprintf("file is required and must be at most %d MB", ah.maxSizeBytes>>20), utils.ErrorCodeBadRequest))
+		return
+	}
+
+	message, attachment, err := ah.attachmentService.UploadAttachment(c, roomID, userUUID, file, c.PostForm("content"))
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	user, err := ah.userService.GetUserByUUIDWithContext(c.Request.Context(), userUUID.String())
+	if err != nil {
+		log.Printf("❌ Error getting user info: %v", err)
+	} else {
+		ah.manager.SendToRoom(newMessageEvent(message, user, attachment))
+	}
+
+	utils.ResponseSuccess(c, "File uploaded successfully", gin.H{
+		"message":    message,
+		"attachment": attachment,
+	})
+}
+
+// GetAttachment godoc
+// @Summary Get attachment
+// @Description Get attachment metadata with a fresh signed download URL (room members only)
+// @Tags attachments
+// @Produce json
+// @Param attachmentID path int true "Attachment ID"
+// @Success 200 {object} utils.Response{data=v1Dto.AttachmentDTO}
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/attachments/{attachmentID} [get]
+func (ah *AttachmentHandler) GetAttachment(c *gin.Context) {
+	attachmentID, err := strconv.ParseInt(c.Param("attachmentID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid attachment ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	attachment, err := ah.attachmentService.GetAttachment(c, attachmentID, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Attachment retrieved successfully", attachment)
+}
+
+// DownloadAttachment godoc
+// @Summary Download attachment
+// @Description Stream the file. Requires the expires/signature pair from a signed URL instead of an Authorization header
+// @Tags attachments
+// @Produce octet-stream
+// @Param attachmentID path int true "Attachment ID"
+// @Param expires query int true "Expiry (unix seconds)"
+// @Param signature query string true "Signature"
+// @Success 200 {file} binary
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/attachments/{attachmentID}/download [get]
+func (ah *AttachmentHandler) DownloadAttachment(c *gin.Context) {
+	ah.serveAttachment(c, false)
+}
+
+// DownloadThumbnail godoc
+// @Summary Download attachment thumbnail
+// @Description Stream the image thumbnail generated after upload, using the signed thumbnail_url
+// @Tags attachments
+// @Produce image/jpeg,image/png
+// @Param attachmentID path int true "Attachment ID"
+// @Param expires query int true "Expiry (unix seconds)"
+// @Param signature query string true "Signature"
+// @Success 200 {file} binary
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/attachments/{attachmentID}/thumbnail [get]
+func (ah *AttachmentHandler) DownloadThumbnail(c *gin.Context) {
+	ah.serveAttachment(c, true)
+}
+
+// NotifyAttachmentProcessed gửi sự kiện "attachment_processed" tới phòng khi thumbnail đã sẵn sàng
+func (ah *AttachmentHandler) NotifyAttachmentProcessed(roomID int64, attachment v1Dto.AttachmentDTO) {
+	dataBytes, _ := json.Marshal(attachment)
+	ah.manager.SendToRoom(wsmanager.Message{
+		Type:      "attachment_processed",
+		RoomID:    roomID,
+		MessageID: &attachment.MessageID,
+		Data:      dataBytes,
+	})
+}
+
+func (ah *AttachmentHandler) serveAttachment(c *gin.Context, thumbnail bool) {
+	attachmentID, err := strconv.ParseInt(c.Param("attachmentID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid attachment ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("download link is invalid or expired", utils.ErrorCodeForbidden))
+		return
+	}
+
+	attachment, reader, err := ah.attachmentService.OpenSignedAttachment(c, attachmentID, thumbnail, expires, c.Query("signature"))
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+	defer reader.Close()
+
+	// Ảnh hiển thị inline, các loại khác luôn tải xuống
+	disposition := "attachment"
+	if strings.HasPrefix(attachment.ContentType, "image/") {
+		disposition = "inline"
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("%s; filename*=UTF-8''%s", disposition, url.PathEscape(attachment.FileName)))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, max-age=300")
+	c.Header("Content-Type", attachment.ContentType)
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		log.Printf("❌ Error streaming attachment %d: %v", attachmentID, err)
+	}
+}
@@ -0,0 +1,98 @@
+package v1Handler
+
+import (
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	auditService services.AuditService
+}
+
+func NewAuditHandler(auditService services.AuditService) *AuditHandler {
+	return &AuditHandler{auditService: auditService}
+}
+
+// ListAuditEvents godoc
+// @Summary [Admin] List audit events
+// @Description Query the audit log of admin actions and security events (logins, failed logins, password changes, account status changes, room deletions...), newest first (Admin only)
+// @Tags admin
+// @Produce json
+// @Param actor_uuid query string false "Only events performed by this user"
+// @Param action query string false "Action, e.g. auth.login_failed, user.deleted, room.deleted"
+// @Param target_type query string false "Target type (user, room, import)"
+// @Param target_id query string false "Target ID (user UUID, room ID...)"
+// @Param from query string false "Created at or after (RFC3339)"
+// @Param to query string false "Created before (RFC3339)"
+// @Param cursor query int false "next_cursor from the previous page"
+// @Param limit query int false "Limit (default 50, max 100)"
+// @Success 200 {object} utils.Response{data=[]v1Dto.AuditEventDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/v1/admin/audit [get]
+func (ah *AuditHandler) ListAuditEvents(c *gin.Context) {
+	var query v1Dto.AuditQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ResponseError(c, utils.WrapError(err, "invalid audit query parameters", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	events, pagination, err := ah.auditService.ListEvents(c, query)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponSuccess(c, http.StatusOK, "Audit events retrieved successfully", map[string]any{
+		"data":       events,
+		"pagination": pagination,
+	})
+}
+
+// ExportAuditEvents godoc
+// @Summary [Admin] Export audit events
+// @Description Stream every audit event matching the filters as NDJSON or CSV, newest first. The export itself is recorded in the audit log (Admin only)
+// @Tags admin
+// @Produce application/x-ndjson,text/csv
+// @Param actor_uuid query string false "Only events performed by this user"
+// @Param action query string false "Action, e.g. auth.login_failed, user.deleted, room.deleted"
+// @Param target_type query string false "Target type (user, room, import)"
+// @Param target_id query string false "Target ID (user UUID, room ID...)"
+// @Param from query string false "Created at or after (RFC3339)"
+// @Param to query string false "Created before (RFC3339)"
+// @Param format query string false "ndjson (default) or csv"
+// @Success 200 {file} binary
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/v1/admin/audit/export [get]
+func (ah *AuditHandler) ExportAuditEvents(c *gin.Context) {
+	var query v1Dto.AuditExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ResponseError(c, utils.WrapError(err, "invalid export parameters", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	file, stream, err := ah.auditService.ExportEvents(c, query)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(file.FileName)))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Type", file.ContentType)
+	c.Status(http.StatusOK)
+
+	// Header đã gửi, lỗi giữa chừng chỉ có thể ghi log (file bị cắt ngang)
+	if err := stream(c.Writer); err != nil {
+		log.Printf("❌ Error exporting audit log: %v", err)
+	}
+}
@@ -6,22 +6,33 @@ import (
 	"chat-app/internal/services/v1"
 	"chat-app/internal/utils"
 	"chat-app/pkg/auth"
+	wsmanager "chat-app/pkg/websocket"
+	"encoding/json"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type AuthHandler struct {
-	userService  services.UserService
-	authService  services.AuthService
-	tokenService auth.TokenService
+	userService         services.UserService
+	authService         services.AuthService
+	tokenService        auth.TokenService
+	verificationService services.EmailVerificationService
+	passwordService     services.PasswordService
+	policy              services.EmailVerificationPolicy
+	manager             *wsmanager.Manager
 }
 
-func NewAuthHandler(userService services.UserService, authService services.AuthService, tokenService auth.TokenService) *AuthHandler {
+func NewAuthHandler(userService services.UserService, authService services.AuthService, tokenService auth.TokenService, verificationService services.EmailVerificationService, passwordService services.PasswordService, policy services.EmailVerificationPolicy, manager *wsmanager.Manager) *AuthHandler {
 	return &AuthHandler{
-		userService:  userService,
-		authService:  authService,
-		tokenService: tokenService,
+		userService:         userService,
+		authService:         authService,
+		tokenService:        tokenService,
+		verificationService: verificationService,
+		passwordService:     passwordService,
+		policy:              policy,
+		manager:             manager,
 	}
 }
 
@@ -31,7 +42,7 @@ type LoginRequest struct {
 }
 
 type RegisterRequest struct {
-	UserEmail    string `json:"user_email" binding:"required,email"`
+	UserEmail    string `json:"user_email" binding:"required,email,email_advanced"`
 	UserPassword string `json:"user_password" binding:"required,min=6"`
 	UserFullname string `json:"user_fullname" binding:"required,min=2"`
 	UserRole     string `json:"user_role,omitempty"` // Optional, default to Member
@@ -39,7 +50,29 @@ type RegisterRequest struct {
 
 type AuthResponse struct {
 	User  *v1Dto.UserDTO `json:"user"`
-	Token string         `json:"token"`
+	Token string         `json:"token,omitempty"` // Không có khi đăng nhập yêu cầu xác minh email
+}
+
+type VerifyEmailRequest struct {
+	Token string `json:"token" binding:"required"`
+}
+
+type ResendVerificationRequest struct {
+	UserEmail string `json:"user_email" binding:"required,email"`
+}
+
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" binding:"required"`
+	NewPassword     string `json:"new_password" binding:"required,min=6"`
+}
+
+type ForgotPasswordRequest struct {
+	UserEmail string `json:"user_email" binding:"required,email"`
+}
+
+type ResetPasswordRequest struct {
+	Token       string `json:"token" binding:"required"`
+	NewPassword string `json:"new_password" binding:"required,min=6"`
 }
 
 // Login godoc
@@ -116,16 +149,23 @@ func (ah *AuthHandler) Register(c *gin.Context) {
 		return
 	}
 	userDto := v1Dto.MapUserToDTO(user)
-	// Generate token for new user
-	token, err := ah.tokenService.GenerateToken(user.UserUuid, user.UserEmail, user.UserFullname, user.UserRole)
-	if err != nil {
-		utils.ResponseError(c, utils.NewError("Could not generate token", utils.ErrorCodeInternalServer))
-		return
-	}
+
+	// Gửi email xác minh ở background, không chặn việc đăng ký khi SMTP lỗi
+	ah.verificationService.QueueVerification(user)
 
 	response := AuthResponse{
-		User:  userDto,
-		Token: token,
+		User: userDto,
+	}
+
+	// Chưa xác minh email thì chưa được đăng nhập
+	if !ah.policy.RequireForLogin {
+		// Generate token for new user
+		token, err := ah.tokenService.GenerateToken(user.UserUuid, user.UserEmail, user.UserFullname, user.UserRole)
+		if err != nil {
+			utils.ResponseError(c, utils.NewError("Could not generate token", utils.ErrorCodeInternalServer))
+			return
+		}
+		response.Token = token
 	}
 
 	c.JSON(http.StatusCreated, utils.APIResponse{
@@ -135,6 +175,156 @@ func (ah *AuthHandler) Register(c *gin.Context) {
 	})
 }
 
+// VerifyEmail godoc
+// @Summary Verify email address
+// @Description Verify the user's email address with the single-use token from the verification email
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body VerifyEmailRequest true "Verification token"
+// @Success 200 {object} utils.Response{data=v1Dto.UserDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /api/v1/auth/verify-email [post]
+func (ah *AuthHandler) VerifyEmail(c *gin.Context) {
+	var req VerifyEmailRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.NewError("Invalid input", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	user, err := ah.verificationService.VerifyEmail(c, req.Token)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Email verified successfully", v1Dto.MapUserToDTO(user))
+}
+
+// ResendVerification godoc
+// @Summary Resend verification email
+// @Description Send a new verification link in the background. Always succeeds for unknown or already verified emails, and when the resend limit is reached, so the response does not reveal whether an account exists
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResendVerificationRequest true "Email address"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /api/v1/auth/verify-email/resend [post]
+func (ah *AuthHandler) ResendVerification(c *gin.Context) {
+	var req ResendVerificationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.NewError("Invalid input", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	if err := ah.verificationService.ResendVerification(c, req.UserEmail); err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "If the email belongs to an unverified account, a verification link has been sent", nil)
+}
+
+// ChangePassword godoc
+// @Summary Change password
+// @Description Change the authenticated user's password. All sessions, including the current one, are signed out
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ChangePasswordRequest true "Current and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/auth/change-password [post]
+func (ah *AuthHandler) ChangePassword(c *gin.Context) {
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	var req ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.NewError("Invalid input", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	if err := ah.passwordService.ChangePassword(c, userUUID, req.CurrentPassword, req.NewPassword); err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Password changed successfully, please log in again", nil)
+}
+
+// ForgotPassword godoc
+// @Summary Request password reset
+// @Description Email a single-use password reset link. Always succeeds so registered emails cannot be discovered
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ForgotPasswordRequest true "Email address"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /api/v1/auth/forgot-password [post]
+func (ah *AuthHandler) ForgotPassword(c *gin.Context) {
+	var req ForgotPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.NewError("Invalid input", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	if err := ah.passwordService.RequestPasswordReset(c, req.UserEmail); err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "If the email is registered, a password reset link has been sent", nil)
+}
+
+// ResetPassword godoc
+// @Summary Reset password
+// @Description Set a new password with the token from the password reset email. All sessions are signed out
+// @Tags auth
+// @Accept json
+// @Produce json
+// @Param request body ResetPasswordRequest true "Reset token and new password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Router /api/v1/auth/reset-password [post]
+func (ah *AuthHandler) ResetPassword(c *gin.Context) {
+	var req ResetPasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.NewError("Invalid input", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	if err := ah.passwordService.ResetPassword(c, req.Token, req.NewPassword); err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Password has been reset, please log in with your new password", nil)
+}
+
+// DisconnectSessions gửi sự kiện "session_revoked" rồi đóng mọi kết nối websocket của user
+func (ah *AuthHandler) DisconnectSessions(userUUID uuid.UUID) {
+	disconnectUser(ah.manager, userUUID, "password_changed")
+}
+
+// disconnectUser gửi sự kiện "session_revoked" kèm lý do rồi đóng mọi kết nối websocket của user
+func disconnectUser(manager *wsmanager.Manager, userUUID uuid.UUID, reason string) {
+	dataBytes, _ := json.Marshal(gin.H{
+		"reason": reason,
+	})
+	manager.DisconnectUser(userUUID, wsmanager.Message{
+		Type:     "session_revoked",
+		UserUUID: userUUID,
+		Data:     dataBytes,
+	})
+}
+
 // Logout godoc
 // @Summary User logout
 // @Description Logout user and revoke JWT token
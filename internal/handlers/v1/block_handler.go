@@ -0,0 +1,107 @@
+package v1Handler
+
+import (
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
+	"chat-app/internal/validation"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type BlockHandler struct {
+	blockService services.BlockService
+}
+
+func NewBlockHandler(blockService services.BlockService) *BlockHandler {
+	return &BlockHandler{blockService: blockService}
+}
+
+// ListBlockedUsers godoc
+// @Summary List blocked users
+// @Tags users
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]v1Dto.BlockedUserDTO}
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/blocks [get]
+func (bh *BlockHandler) ListBlockedUsers(c *gin.Context) {
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	blocks, err := bh.blockService.ListBlockedUsers(c, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Blocked users retrieved successfully", blocks)
+}
+
+// BlockUser godoc
+// @Summary Block a user
+// @Description Hide the user's messages in shared rooms, stop their mentions and notifications, and prevent direct messages in both directions. Blocking an already blocked user is a no-op
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body v1Dto.BlockUserInput true "User to block"
+// @Success 200 {object} utils.Response{data=v1Dto.BlockedUserDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/blocks [post]
+func (bh *BlockHandler) BlockUser(c *gin.Context) {
+	var req v1Dto.BlockUserInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(c, validation.HandleValidationError(err))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	block, err := bh.blockService.BlockUser(c, userUUID, uuid.MustParse(req.UserUUID))
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "User blocked successfully", block)
+}
+
+// UnblockUser godoc
+// @Summary Unblock a user
+// @Tags users
+// @Produce json
+// @Param userUUID path string true "Blocked user UUID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/blocks/{userUUID} [delete]
+func (bh *BlockHandler) UnblockUser(c *gin.Context) {
+	blockedUUID, err := uuid.Parse(c.Param("userUUID"))
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid user UUID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	if err := bh.blockService.UnblockUser(c, userUUID, blockedUUID); err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "User unblocked successfully", nil)
+}
@@ -0,0 +1,226 @@
+package v1Handler
+
+import (
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
+	wsmanager "chat-app/pkg/websocket"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type DataExportHandler struct {
+	dataExportService services.DataExportService
+	manager           *wsmanager.Manager
+}
+
+func NewDataExportHandler(dataExportService services.DataExportService, manager *wsmanager.Manager) *DataExportHandler {
+	return &DataExportHandler{dataExportService: dataExportService, manager: manager}
+}
+
+// RequestExport godoc
+// @Summary Request a personal data export
+// @Description Start an asynchronous export of the authenticated user's data: a ZIP with profile.json, memberships.json, messages.json and attachments/. Returns the export already in progress if there is one. A data_export_finished event is sent over WebSocket when the job ends
+// @Tags users
+// @Produce json
+// @Success 202 {object} utils.Response{data=v1Dto.DataExportDTO}
+// @Failure 401 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/exports [post]
+func (dh *DataExportHandler) RequestExport(c *gin.Context) {
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	export, err := dh.dataExportService.RequestExport(c, userUUID, nil)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponSuccess(c, http.StatusAccepted, "Data export requested successfully", export)
+}
+
+// ListExports godoc
+// @Summary List my data exports
+// @Description List the 20 most recent data exports of the authenticated user
+// @Tags users
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]v1Dto.DataExportDTO}
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/exports [get]
+func (dh *DataExportHandler) ListExports(c *gin.Context) {
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	exports, err := dh.dataExportService.ListExports(c, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Data exports retrieved successfully", exports)
+}
+
+// GetExport godoc
+// @Summary Get data export status
+// @Description Get the status of one of the authenticated user's data exports. Completed exports include a fresh signed download_url
+// @Tags users
+// @Produce json
+// @Param exportID path int true "Export ID"
+// @Success 200 {object} utils.Response{data=v1Dto.DataExportDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/exports/{exportID} [get]
+func (dh *DataExportHandler) GetExport(c *gin.Context) {
+	exportID, err := strconv.ParseInt(c.Param("exportID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid export ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	export, err := dh.dataExportService.GetExport(c, exportID, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Data export retrieved successfully", export)
+}
+
+// RequestUserExport godoc
+// @Summary [Admin] Request a data export for a user
+// @Description Start an asynchronous personal data export on behalf of a user, e.g. for a compliance request (Admin only). The requesting admin receives the data_export_finished event
+// @Tags admin
+// @Produce json
+// @Param userID path string true "User UUID"
+// @Success 202 {object} utils.Response{data=v1Dto.DataExportDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/admin/users/{userID}/exports [post]
+func (dh *DataExportHandler) RequestUserExport(c *gin.Context) {
+	userUUID, err := uuid.Parse(c.Param("user_uuid"))
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid user ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	adminUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	export, err := dh.dataExportService.RequestExport(c, userUUID, &adminUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponSuccess(c, http.StatusAccepted, "Data export requested successfully", export)
+}
+
+// GetUserExport godoc
+// @Summary [Admin] Get data export status
+// @Description Get the status of any data export, with a fresh signed download_url when completed (Admin only)
+// @Tags admin
+// @Produce json
+// @Param exportID path int true "Export ID"
+// @Success 200 {object} utils.Response{data=v1Dto.DataExportDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/admin/exports/{exportID} [get]
+func (dh *DataExportHandler) GetUserExport(c *gin.Context) {
+	exportID, err := strconv.ParseInt(c.Param("exportID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid export ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	export, err := dh.dataExportService.GetExportByID(c, exportID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Data export retrieved successfully", export)
+}
+
+// DownloadExport godoc
+// @Summary Download data export
+// @Description Stream the export ZIP. Requires the expires/signature pair from download_url instead of an Authorization header
+// @Tags users
+// @Produce application/zip
+// @Param exportID path int true "Export ID"
+// @Param expires query int true "Expiry (unix seconds)"
+// @Param signature query string true "Signature"
+// @Success 200 {file} binary
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/exports/{exportID}/download [get]
+func (dh *DataExportHandler) DownloadExport(c *gin.Context) {
+	exportID, err := strconv.ParseInt(c.Param("exportID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid export ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	expires, err := strconv.ParseInt(c.Query("expires"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("download link is invalid or expired", utils.ErrorCodeForbidden))
+		return
+	}
+
+	export, reader, err := dh.dataExportService.OpenSignedExport(c, exportID, expires, c.Query("signature"))
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+	defer reader.Close()
+
+	fileName := fmt.Sprintf("data-export-%d-%s.zip", export.ExportID, export.ExportCreatedAt.UTC().Format("20060102"))
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", fileName))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Type", "application/zip")
+	if export.SizeBytes != nil {
+		c.Header("Content-Length", strconv.FormatInt(*export.SizeBytes, 10))
+	}
+	c.Status(http.StatusOK)
+
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		log.Printf("❌ Error streaming data export %d: %v", exportID, err)
+	}
+}
+
+// NotifyExportFinished gửi sự kiện "data_export_finished" tới mọi kết nối websocket của người đã yêu cầu
+func (dh *DataExportHandler) NotifyExportFinished(recipientUUID uuid.UUID, export v1Dto.DataExportDTO) {
+	dataBytes, _ := json.Marshal(export)
+	dh.manager.SendToUser(recipientUUID, wsmanager.Message{
+		Type:      "data_export_finished",
+		UserUUID:  recipientUUID,
+		Timestamp: time.Now().Format(time.RFC3339),
+		Data:      dataBytes,
+	})
+}
@@ -0,0 +1,75 @@
+package v1Handler
+
+import (
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
+	"chat-app/internal/validation"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DigestHandler struct {
+	digestService services.DigestService
+}
+
+func NewDigestHandler(digestService services.DigestService) *DigestHandler {
+	return &DigestHandler{digestService: digestService}
+}
+
+// GetDigestSettings godoc
+// @Summary Get email digest settings
+// @Description How often the user receives an email summarising unread mentions and direct messages while offline
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} utils.Response{data=v1Dto.DigestSettingsDTO}
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/email-digest [get]
+func (dh *DigestHandler) GetDigestSettings(c *gin.Context) {
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	settings, err := dh.digestService.GetSettings(c, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Digest settings retrieved successfully", settings)
+}
+
+// UpdateDigestSettings godoc
+// @Summary Update email digest settings
+// @Description Set the digest frequency (hourly, daily, weekly) or opt out with "never"
+// @Tags notifications
+// @Accept json
+// @Produce json
+// @Param request body v1Dto.DigestSettingsInput true "Digest settings"
+// @Success 200 {object} utils.Response{data=v1Dto.DigestSettingsDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/email-digest [put]
+func (dh *DigestHandler) UpdateDigestSettings(c *gin.Context) {
+	var req v1Dto.DigestSettingsInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(c, validation.HandleValidationError(err))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	settings, err := dh.digestService.UpdateSettings(c, userUUID, req)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Digest settings updated successfully", settings)
+}
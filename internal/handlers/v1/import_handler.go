@@ -0,0 +1,65 @@
+package v1Handler
+
+import (
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
+	"chat-app/internal/validation"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportHandler struct {
+	importService services.ImportService
+	maxSizeBytes  int64
+}
+
+func NewImportHandler(importService services.ImportService, maxSizeBytes int64) *ImportHandler {
+	return &ImportHandler{importService: importService, maxSizeBytes: maxSizeBytes}
+}
+
+// ImportArchive godoc
+// @Summary [Admin] Import a Slack or Discord export
+// @Description Import a Slack workspace export (ZIP of per-channel JSON) or a Discord export (ZIP of DiscordChatExporter JSON files plus users.json mapping Discord ids to emails). Users are matched by email, rooms and members are created and messages are bulk inserted with their original timestamps. Re-running the same import only adds what is missing (Admin only)
+// @Tags admin
+// @Accept multipart/form-data
+// @Produce json
+// @Param source formData string true "Export source" Enums(slack, discord)
+// @Param file formData file true "Export ZIP"
+// @Success 200 {object} utils.Response{data=v1Dto.ImportResultDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/v1/admin/imports [post]
+func (ih *ImportHandler) ImportArchive(c *gin.Context) {
+	adminUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	// Chặn body quá lớn trước khi gin parse multipart (cộng thêm 1MB cho các trường khác)
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, ih.maxSizeBytes+1<<20)
+
+	var input v1Dto.ImportInput
+	if err := c.ShouldBind(&input); err != nil {
+		utils.ResponseValidator(c, validation.HandleValidationError(err))
+		return
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		utils.ResponseError(c, utils.NewError(fmt.Sprintf("file is required and must be at most %d MB", ih.maxSizeBytes>>20), utils.ErrorCodeBadRequest))
+		return
+	}
+
+	result, err := ih.importService.ImportArchive(c, adminUUID, input.Source, file)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Import completed successfully", result)
+}
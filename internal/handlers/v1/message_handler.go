@@ -1,8 +1,13 @@
 package v1Handler
 
 import (
+	v1Dto "chat-app/internal/dto/v1"
 	"chat-app/internal/services/v1"
 	"chat-app/internal/utils"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
@@ -10,24 +15,28 @@ import (
 )
 
 type MessageHandler struct {
-	messageService services.MessageService
+	messageService    services.MessageService
+	roomExportService services.RoomExportService
 }
 
-func NewMessageHandler(messageService services.MessageService) *MessageHandler {
+func NewMessageHandler(messageService services.MessageService, roomExportService services.RoomExportService) *MessageHandler {
 	return &MessageHandler{
-		messageService: messageService,
+		messageService:    messageService,
+		roomExportService: roomExportService,
 	}
 }
 
 // GetRoomMessages godoc
 // @Summary Get room messages
-// @Description Get messages from a specific room with pagination
+// @Description Get messages from a specific room, newest first. Use before/after message-ID cursors (keyset pagination); offset is kept for older clients
 // @Tags messages
 // @Produce json
 // @Param roomID path int true "Room ID"
+// @Param before query int false "Return messages older than this message ID"
+// @Param after query int false "Return messages newer than this message ID"
 // @Param limit query int false "Limit (default 50)"
-// @Param offset query int false "Offset (default 0)"
-// @Success 200 {object} utils.Response{data=[]sqlc.Message}
+// @Param offset query int false "Offset (deprecated, ignored when before/after is set)"
+// @Success 200 {object} utils.Response{data=[]v1Dto.MessageWithUser}
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 403 {object} utils.ErrorResponse
 // @Router /api/v1/rooms/{roomID}/messages [get]
@@ -53,6 +62,18 @@ func (mh *MessageHandler) GetRoomMessages(c *gin.Context) {
 		offset = 0
 	}
 
+	before, err := parseCursor(c, "before")
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	after, err := parseCursor(c, "after")
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
 	// Get authenticated user
 	userUUID, exists := c.Get("userUUID")
 	if !exists {
@@ -66,14 +87,103 @@ func (mh *MessageHandler) GetRoomMessages(c *gin.Context) {
 		return
 	}
 
+	// Client cũ vẫn dùng offset
+	if offset > 0 && before == nil && after == nil {
+		messages, err := mh.messageService.GetRoomMessagesWithUsers(c, roomID, userID, int32(limit), int32(offset))
+		if err != nil {
+			utils.ResponseError(c, err)
+			return
+		}
+
+		utils.ResponseSuccess(c, "Messages retrieved successfully", messages)
+		return
+	}
+
 	// Get room messages with user info
-	messages, err := mh.messageService.GetRoomMessagesWithUsers(c, roomID, userID, int32(limit), int32(offset))
+	messages, pagination, err := mh.messageService.GetRoomMessagesPage(c, roomID, userID, v1Dto.MessagePageQuery{
+		Before: before,
+		After:  after,
+		Limit:  int32(limit),
+	})
 	if err != nil {
 		utils.ResponseError(c, err)
 		return
 	}
 
-	utils.ResponseSuccess(c, "Messages retrieved successfully", messages)
+	utils.ResponSuccess(c, http.StatusOK, "Messages retrieved successfully", map[string]any{
+		"data":       messages,
+		"pagination": pagination,
+	})
+}
+
+// GetMessageContext godoc
+// @Summary Get messages around a message
+// @Description Get the message plus up to N older and N newer messages (newest first) with cursors to keep scrolling both ways
+// @Tags messages
+// @Produce json
+// @Param roomID path int true "Room ID"
+// @Param messageID path int true "Message ID"
+// @Param before query int false "Number of older messages (default 25, max 100)"
+// @Param after query int false "Number of newer messages (default 25, max 100)"
+// @Success 200 {object} utils.Response{data=[]v1Dto.MessageWithUser}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID}/messages/{messageID}/context [get]
+func (mh *MessageHandler) GetMessageContext(c *gin.Context) {
+	roomID, err := strconv.ParseInt(c.Param("roomID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	messageID, err := strconv.ParseInt(c.Param("messageID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid message ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	before, err := strconv.ParseInt(c.DefaultQuery("before", "25"), 10, 32)
+	if err != nil || before < 0 || before > 100 {
+		before = 25
+	}
+
+	after, err := strconv.ParseInt(c.DefaultQuery("after", "25"), 10, 32)
+	if err != nil || after < 0 || after > 100 {
+		after = 25
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	messages, pagination, err := mh.messageService.GetMessageContext(c, roomID, messageID, userUUID, int32(before), int32(after))
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponSuccess(c, http.StatusOK, "Messages retrieved successfully", map[string]any{
+		"data":       messages,
+		"pagination": pagination,
+	})
+}
+
+// parseCursor đọc cursor message_id từ query string, nil nếu không truyền
+func parseCursor(c *gin.Context, key string) (*int64, error) {
+	value := c.Query(key)
+	if value == "" {
+		return nil, nil
+	}
+
+	cursor, err := strconv.ParseInt(value, 10, 64)
+	if err != nil || cursor < 1 {
+		return nil, utils.NewError("invalid "+key+" cursor", utils.ErrorCodeBadRequest)
+	}
+
+	return &cursor, nil
 }
 
 // SendMessage godoc
@@ -88,6 +198,8 @@ func (mh *MessageHandler) GetRoomMessages(c *gin.Context) {
 // @Failure 400 {object} utils.ErrorResponse
 // @Failure 401 {object} utils.ErrorResponse
 // @Failure 403 {object} utils.ErrorResponse
+// @Failure 429 {object} utils.ErrorResponse "Slow mode cooldown not elapsed"
+// @Header 429 {integer} Retry-After "Seconds until the slow mode cooldown ends"
 // @Router /api/v1/rooms/{roomID}/messages [post]
 func (mh *MessageHandler) SendMessage(c *gin.Context) {
 	roomIDStr := c.Param("roomID")
@@ -129,3 +241,125 @@ func (mh *MessageHandler) SendMessage(c *gin.Context) {
 
 	utils.ResponseSuccess(c, "Message sent successfully", message)
 }
+
+// MarkMentionsRead godoc
+// @Summary Mark mentions as read
+// @Description Mark every unread @mention of the authenticated user in a room as read (resets mention_count in the room list)
+// @Tags messages
+// @Produce json
+// @Param roomID path int true "Room ID"
+// @Success 200 {object} utils.Response{data=object{updated=int}}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID}/mentions/read [post]
+func (mh *MessageHandler) MarkMentionsRead(c *gin.Context) {
+	roomID, err := strconv.ParseInt(c.Param("roomID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	updated, err := mh.messageService.MarkMentionsRead(c, roomID, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Mentions marked as read", gin.H{"updated": updated})
+}
+
+// ExportRoomHistory godoc
+// @Summary Export room history
+// @Description Stream the full history of a room (room owner only) as NDJSON, CSV or a self-contained HTML transcript, oldest first, with sender names and attachment metadata
+// @Tags messages
+// @Produce application/x-ndjson,text/csv,text/html
+// @Param roomID path int true "Room ID"
+// @Param format query string false "ndjson (default), csv or html"
+// @Success 200 {file} binary
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID}/export [get]
+func (mh *MessageHandler) ExportRoomHistory(c *gin.Context) {
+	roomID, err := strconv.ParseInt(c.Param("roomID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	var query v1Dto.RoomExportQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ResponseError(c, utils.WrapError(err, "invalid export parameters", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	file, stream, err := mh.roomExportService.ExportRoomHistory(c, roomID, userUUID, query.Format)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename*=UTF-8''%s", url.PathEscape(file.FileName)))
+	c.Header("X-Content-Type-Options", "nosniff")
+	c.Header("Cache-Control", "private, no-store")
+	c.Header("Content-Type", file.ContentType)
+	c.Status(http.StatusOK)
+
+	// Header đã gửi, lỗi giữa chừng chỉ có thể ghi log (file bị cắt ngang)
+	if err := stream(c.Writer); err != nil {
+		log.Printf("❌ Error exporting history of room %d: %v", roomID, err)
+	}
+}
+
+// SearchMessages godoc
+// @Summary Search messages
+// @Description Full-text search over messages in rooms the authenticated user is a member of, newest first
+// @Tags messages
+// @Produce json
+// @Param q query string true "Search query (supports \"quoted phrases\", OR and -exclude)"
+// @Param room_id query int false "Only search in this room"
+// @Param sender_uuid query string false "Only messages from this sender"
+// @Param from query string false "Created at or after (RFC3339)"
+// @Param to query string false "Created before (RFC3339)"
+// @Param cursor query int false "next_cursor from the previous page"
+// @Param limit query int false "Limit (default 20, max 100)"
+// @Success 200 {object} utils.Response{data=[]v1Dto.MessageSearchResult}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/search/messages [get]
+func (mh *MessageHandler) SearchMessages(c *gin.Context) {
+	var query v1Dto.SearchMessagesQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ResponseError(c, utils.WrapError(err, "invalid search parameters", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	results, pagination, err := mh.messageService.SearchMessages(c, userUUID, query)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponSuccess(c, http.StatusOK, "Messages retrieved successfully", map[string]any{
+		"data":       results,
+		"pagination": pagination,
+	})
+}
@@ -0,0 +1,138 @@
+package v1Handler
+
+import (
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
+	wsmanager "chat-app/pkg/websocket"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+type NotificationHandler struct {
+	notificationService services.NotificationService
+	manager             *wsmanager.Manager
+}
+
+func NewNotificationHandler(notificationService services.NotificationService, manager *wsmanager.Manager) *NotificationHandler {
+	return &NotificationHandler{
+		notificationService: notificationService,
+		manager:             manager,
+	}
+}
+
+// ListNotifications godoc
+// @Summary List notifications
+// @Description Get the authenticated user's notifications (currently mentions), newest first, with the total unread count
+// @Tags notifications
+// @Produce json
+// @Param unread_only query bool false "Only unread notifications"
+// @Param cursor query int false "next_cursor from the previous page"
+// @Param limit query int false "Limit (default 20, max 100)"
+// @Success 200 {object} utils.Response{data=[]v1Dto.NotificationDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/notifications [get]
+func (nh *NotificationHandler) ListNotifications(c *gin.Context) {
+	var query v1Dto.NotificationQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ResponseError(c, utils.WrapError(err, "invalid notification parameters", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	notifications, pagination, unreadCount, err := nh.notificationService.ListNotifications(c, userUUID, query)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponSuccess(c, http.StatusOK, "Notifications retrieved successfully", map[string]any{
+		"data":         notifications,
+		"pagination":   pagination,
+		"unread_count": unreadCount,
+	})
+}
+
+// MarkNotificationRead godoc
+// @Summary Mark a notification as read
+// @Tags notifications
+// @Produce json
+// @Param notificationID path int true "Notification ID"
+// @Success 200 {object} utils.Response{data=v1Dto.NotificationDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/notifications/{notificationID}/read [post]
+func (nh *NotificationHandler) MarkNotificationRead(c *gin.Context) {
+	notificationID, err := strconv.ParseInt(c.Param("notificationID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid notification ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	notification, err := nh.notificationService.MarkRead(c, userUUID, notificationID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Notification marked as read", notification)
+}
+
+// MarkAllNotificationsRead godoc
+// @Summary Mark all notifications as read
+// @Tags notifications
+// @Produce json
+// @Success 200 {object} utils.Response{data=object{updated=int}}
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/notifications/read-all [post]
+func (nh *NotificationHandler) MarkAllNotificationsRead(c *gin.Context) {
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	updated, err := nh.notificationService.MarkAllRead(c, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Notifications marked as read", gin.H{"updated": updated})
+}
+
+// DeliverNotification gửi sự kiện "notification" tới mọi kết nối websocket của user,
+// không phụ thuộc các kết nối đó đang join phòng nào
+func (nh *NotificationHandler) DeliverNotification(userUUID uuid.UUID, notification v1Dto.NotificationDTO) {
+	dataBytes, _ := json.Marshal(notification)
+
+	message := wsmanager.Message{
+		Type:      "notification",
+		UserUUID:  userUUID,
+		Timestamp: notification.CreatedAt.Format(time.RFC3339),
+		Data:      dataBytes,
+		MessageID: notification.MessageID,
+	}
+	if notification.RoomID != nil {
+		message.RoomID = *notification.RoomID
+	}
+
+	nh.manager.SendToUser(userUUID, message)
+}
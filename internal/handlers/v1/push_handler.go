@@ -0,0 +1,126 @@
+package v1Handler
+
+import (
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PushHandler struct {
+	pushService    services.PushService
+	vapidPublicKey string
+}
+
+func NewPushHandler(pushService services.PushService, vapidPublicKey string) *PushHandler {
+	return &PushHandler{
+		pushService:    pushService,
+		vapidPublicKey: vapidPublicKey,
+	}
+}
+
+// RegisterDevice godoc
+// @Summary Register a push device
+// @Description Register a Web Push subscription (token = subscription endpoint, plus p256dh/auth keys), an FCM registration token or an APNs device token. Push notifications are only sent while the user has no open websocket connection.
+// @Tags push
+// @Accept json
+// @Produce json
+// @Param request body v1Dto.RegisterPushDeviceInput true "Device"
+// @Success 201 {object} utils.Response{data=v1Dto.PushDeviceDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/devices [post]
+func (ph *PushHandler) RegisterDevice(c *gin.Context) {
+	var req v1Dto.RegisterPushDeviceInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.WrapError(err, "invalid request body", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	device, err := ph.pushService.RegisterDevice(c, userUUID, req)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponSuccess(c, http.StatusCreated, "Device registered successfully", device)
+}
+
+// ListDevices godoc
+// @Summary List push devices
+// @Tags push
+// @Produce json
+// @Success 200 {object} utils.Response{data=[]v1Dto.PushDeviceDTO}
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/devices [get]
+func (ph *PushHandler) ListDevices(c *gin.Context) {
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	devices, err := ph.pushService.ListDevices(c, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Devices retrieved successfully", devices)
+}
+
+// DeleteDevice godoc
+// @Summary Unregister a push device
+// @Tags push
+// @Produce json
+// @Param deviceID path int true "Device ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/users/me/devices/{deviceID} [delete]
+func (ph *PushHandler) DeleteDevice(c *gin.Context) {
+	deviceID, err := strconv.ParseInt(c.Param("deviceID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid device ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	if err := ph.pushService.DeleteDevice(c, userUUID, deviceID); err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Device removed successfully", nil)
+}
+
+// GetVAPIDPublicKey godoc
+// @Summary Get the VAPID public key
+// @Description applicationServerKey for PushManager.subscribe() in the browser
+// @Tags push
+// @Produce json
+// @Success 200 {object} utils.Response{data=object{public_key=string}}
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/push/vapid-public-key [get]
+func (ph *PushHandler) GetVAPIDPublicKey(c *gin.Context) {
+	if ph.vapidPublicKey == "" {
+		utils.ResponseError(c, utils.NewError("web push is not enabled on this server", utils.ErrorCodeNotFound))
+		return
+	}
+
+	utils.ResponseSuccess(c, "VAPID public key retrieved successfully", gin.H{"public_key": ph.vapidPublicKey})
+}
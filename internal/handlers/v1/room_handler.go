@@ -4,7 +4,10 @@ import (
 	v1Dto "chat-app/internal/dto/v1"
 	"chat-app/internal/services/v1"
 	"chat-app/internal/utils"
+	wsmanager "chat-app/pkg/websocket"
+	"encoding/json"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -12,11 +15,15 @@ import (
 
 type RoomHandler struct {
 	roomService services.RoomService
+	userService services.UserService
+	manager     *wsmanager.Manager
 }
 
-func NewRoomHandler(roomService services.RoomService) *RoomHandler {
+func NewRoomHandler(roomService services.RoomService, userService services.UserService, manager *wsmanager.Manager) *RoomHandler {
 	return &RoomHandler{
 		roomService: roomService,
+		userService: userService,
+		manager:     manager,
 	}
 }
 
@@ -68,9 +75,10 @@ func (rh *RoomHandler) CreateRoom(c *gin.Context) {
 
 // ListRooms godoc
 // @Summary Get user's rooms with last message
-// @Description Get all rooms that the authenticated user is a member of with last message info
+// @Description Get all rooms that the authenticated user is a member of with last message info. Archived rooms are hidden unless include_archived=true
 // @Tags rooms
 // @Produce json
+// @Param include_archived query bool false "Include archived rooms (default false)"
 // @Success 200 {object} utils.Response{data=[]v1Dto.RoomWithLastMessage}
 // @Failure 401 {object} utils.ErrorResponse
 // @Router /api/v1/rooms [get]
@@ -88,8 +96,10 @@ func (rh *RoomHandler) ListRooms(c *gin.Context) {
 		return
 	}
 
+	includeArchived, _ := strconv.ParseBool(c.DefaultQuery("include_archived", "false"))
+
 	// Get user rooms with last message
-	roomRows, err := rh.roomService.GetUserRoomsWithLastMessage(c, userID)
+	roomRows, err := rh.roomService.GetUserRoomsWithLastMessage(c, userID, includeArchived)
 	if err != nil {
 		utils.ResponseError(c, err)
 		return
@@ -99,13 +109,23 @@ func (rh *RoomHandler) ListRooms(c *gin.Context) {
 	var rooms []v1Dto.RoomWithLastMessage
 	for _, row := range roomRows {
 		room := v1Dto.RoomWithLastMessage{
-			RoomID:           row.RoomID,
-			RoomCode:         row.RoomCode,
-			RoomName:         row.RoomName,
-			RoomIsDirectChat: row.RoomIsDirectChat,
-			RoomCreatedBy:    row.RoomCreatedBy.String(),
-			RoomCreatedAt:    row.RoomCreatedAt,
-			RoomUpdatedAt:    row.RoomUpdatedAt,
+			RoomID:                row.RoomID,
+			RoomCode:              row.RoomCode,
+			RoomName:              row.RoomName,
+			RoomIsDirectChat:      row.RoomIsDirectChat,
+			RoomCreatedBy:         row.RoomCreatedBy.String(),
+			RoomCreatedAt:         row.RoomCreatedAt,
+			RoomUpdatedAt:         row.RoomUpdatedAt,
+			RoomTopic:             row.RoomTopic,
+			RoomDescription:       row.RoomDescription,
+			RoomAvatarURL:         row.RoomAvatarUrl,
+			RoomArchivedAt:        row.RoomArchivedAt,
+			RoomPostingPermission: row.RoomPostingPermission,
+			RoomSlowModeSeconds:   row.RoomSlowModeSeconds,
+			MentionCount:          row.MentionCount,
+			NotificationLevel:     row.MemberNotificationLevel,
+			MutedUntil:            row.MemberMutedUntil,
+			IsMuted:               row.MemberMutedUntil != nil && row.MemberMutedUntil.After(time.Now()),
 		}
 
 		// Add last message if exists (check if message_id > 0 since it's not nullable)
@@ -131,12 +151,13 @@ func (rh *RoomHandler) ListRooms(c *gin.Context) {
 
 // GetRoom godoc
 // @Summary Get room details
-// @Description Get details of a specific room
+// @Description Get details of a specific room (members only)
 // @Tags rooms
 // @Produce json
 // @Param roomID path int true "Room ID"
 // @Success 200 {object} utils.Response{data=sqlc.Room}
 // @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
 // @Failure 404 {object} utils.ErrorResponse
 // @Router /api/v1/rooms/{roomID} [get]
 func (rh *RoomHandler) GetRoom(c *gin.Context) {
@@ -147,18 +168,81 @@ func (rh *RoomHandler) GetRoom(c *gin.Context) {
 		return
 	}
 
-	// TODO: Implement GetRoomByID in service
-	// room, err := rh.roomService.GetRoomByID(c, roomID)
-	// if err != nil {
-	// 	utils.ResponseError(c, err)
-	// 	return
-	// }
+	// Get authenticated user
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
 
-	// Temporary response
-	utils.ResponseSuccess(c, "Room details", map[string]interface{}{
-		"room_id": roomID,
-		"message": "GetRoom not implemented yet",
-	})
+	room, err := rh.roomService.GetRoomDetails(c, roomID, userUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Room details retrieved successfully", room)
+}
+
+// UpdateRoom godoc
+// @Summary Update room settings
+// @Description Change name, topic, description or avatar of a room (Owner/Admin only)
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Param roomID path int true "Room ID"
+// @Param room body v1Dto.UpdateRoomInput true "Fields to update"
+// @Success 200 {object} utils.Response{data=sqlc.Room}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 404 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID} [patch]
+func (rh *RoomHandler) UpdateRoom(c *gin.Context) {
+	roomIDStr := c.Param("roomID")
+	roomID, err := strconv.ParseInt(roomIDStr, 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	// Get authenticated user
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	var req v1Dto.UpdateRoomInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.NewError("invalid request body", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	room, systemMessages, err := rh.roomService.UpdateRoomSettings(c, roomID, userUUID, req)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	// Thông báo realtime cho các client đang mở phòng
+	if len(systemMessages) > 0 {
+		actor, err := rh.userService.GetUserByUUIDWithContext(c.Request.Context(), userUUID.String())
+		if err == nil {
+			for _, message := range systemMessages {
+				rh.manager.SendToRoom(newMessageEvent(message, actor))
+			}
+		}
+
+		dataBytes, _ := json.Marshal(room)
+		rh.manager.SendToRoom(wsmanager.Message{
+			Type:     "room_updated",
+			RoomID:   room.RoomID,
+			UserUUID: userUUID,
+			Data:     dataBytes,
+		})
+	}
+
+	utils.ResponseSuccess(c, "Room updated successfully", room)
 }
 
 // JoinRoomByCode godoc
@@ -315,3 +399,227 @@ func (rh *RoomHandler) LeaveRoom(c *gin.Context) {
 
 	utils.ResponseSuccess(c, "Successfully left room", response)
 }
+
+// TransferOwnership godoc
+// @Summary Transfer room ownership
+// @Description Hand the Owner role to another member; the current owner becomes Admin (Owner only)
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Param roomID path int true "Room ID"
+// @Param request body v1Dto.TransferOwnershipInput true "New owner"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID}/transfer-ownership [post]
+func (rh *RoomHandler) TransferOwnership(c *gin.Context) {
+	roomIDStr := c.Param("roomID")
+	roomID, err := strconv.ParseInt(roomIDStr, 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	// Get authenticated user
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	var req v1Dto.TransferOwnershipInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.NewError("invalid request body", utils.ErrorCodeBadRequest))
+		return
+	}
+	newOwnerUUID, _ := uuid.Parse(req.NewOwnerUUID)
+
+	systemMessage, err := rh.roomService.TransferOwnership(c, roomID, userUUID, newOwnerUUID)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	actor, err := rh.userService.GetUserByUUIDWithContext(c.Request.Context(), userUUID.String())
+	if err == nil {
+		rh.manager.SendToRoom(newMessageEvent(systemMessage, actor))
+	}
+
+	dataBytes, _ := json.Marshal(gin.H{
+		"previous_owner_uuid": userUUID.String(),
+		"new_owner_uuid":      newOwnerUUID.String(),
+	})
+	rh.manager.SendToRoom(wsmanager.Message{
+		Type:     "ownership_transferred",
+		RoomID:   roomID,
+		UserUUID: userUUID,
+		Data:     dataBytes,
+	})
+
+	response := gin.H{
+		"room_id":        roomID,
+		"new_owner_uuid": newOwnerUUID.String(),
+	}
+
+	utils.ResponseSuccess(c, "Room ownership transferred successfully", response)
+}
+
+// DeleteRoom godoc
+// @Summary Delete a room
+// @Description Delete a room and all its messages (room Owner only)
+// @Tags rooms
+// @Param roomID path int true "Room ID"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID} [delete]
+func (rh *RoomHandler) DeleteRoom(c *gin.Context) {
+	roomIDStr := c.Param("roomID")
+	roomID, err := strconv.ParseInt(roomIDStr, 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	// Get authenticated user
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	if err := rh.roomService.DeleteOwnedRoom(c, roomID, userUUID); err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	// Đẩy tất cả client đang mở phòng ra ngoài
+	rh.manager.CloseRoom(roomDeletedEvent(roomID, userUUID))
+
+	utils.ResponseSuccess(c, "Room deleted successfully", nil)
+}
+
+// roomDeletedEvent tạo sự kiện "room_deleted" gửi tới client trước khi bị đẩy khỏi phòng
+func roomDeletedEvent(roomID int64, actorUUID uuid.UUID) wsmanager.Message {
+	dataBytes, _ := json.Marshal(gin.H{
+		"room_id":    roomID,
+		"deleted_by": actorUUID.String(),
+	})
+	return wsmanager.Message{
+		Type:     "room_deleted",
+		RoomID:   roomID,
+		UserUUID: actorUUID,
+		Data:     dataBytes,
+	}
+}
+
+// UpdateNotificationSettings godoc
+// @Summary Update room notification settings
+// @Description Set the authenticated member's notification level for a room (all, mentions, none) and optionally mute it until a given time
+// @Tags rooms
+// @Accept json
+// @Produce json
+// @Param roomID path int true "Room ID"
+// @Param settings body v1Dto.UpdateNotificationSettingsInput true "Notification settings"
+// @Success 200 {object} utils.Response{data=sqlc.RoomMember}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID}/notification-settings [put]
+func (rh *RoomHandler) UpdateNotificationSettings(c *gin.Context) {
+	roomID, err := strconv.ParseInt(c.Param("roomID"), 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	var req v1Dto.UpdateNotificationSettingsInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseError(c, utils.NewError("invalid request body", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	member, err := rh.roomService.UpdateNotificationSettings(c, roomID, userUUID, req)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Notification settings updated successfully", member)
+}
+
+// ArchiveRoom godoc
+// @Summary Archive a room
+// @Description Make a room read-only and hide it from the default room list (Owner only)
+// @Tags rooms
+// @Produce json
+// @Param roomID path int true "Room ID"
+// @Success 200 {object} utils.Response{data=sqlc.Room}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID}/archive [post]
+func (rh *RoomHandler) ArchiveRoom(c *gin.Context) {
+	rh.setRoomArchived(c, true)
+}
+
+// UnarchiveRoom godoc
+// @Summary Unarchive a room
+// @Description Make an archived room writable again (Owner only)
+// @Tags rooms
+// @Produce json
+// @Param roomID path int true "Room ID"
+// @Success 200 {object} utils.Response{data=sqlc.Room}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 403 {object} utils.ErrorResponse
+// @Failure 409 {object} utils.ErrorResponse
+// @Router /api/v1/rooms/{roomID}/unarchive [post]
+func (rh *RoomHandler) UnarchiveRoom(c *gin.Context) {
+	rh.setRoomArchived(c, false)
+}
+
+func (rh *RoomHandler) setRoomArchived(c *gin.Context, archived bool) {
+	roomIDStr := c.Param("roomID")
+	roomID, err := strconv.ParseInt(roomIDStr, 10, 64)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("invalid room ID", utils.ErrorCodeBadRequest))
+		return
+	}
+
+	// Get authenticated user
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	room, systemMessage, err := rh.roomService.SetRoomArchived(c, roomID, userUUID, archived)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	actor, err := rh.userService.GetUserByUUIDWithContext(c.Request.Context(), userUUID.String())
+	if err == nil {
+		rh.manager.SendToRoom(newMessageEvent(systemMessage, actor))
+	}
+
+	dataBytes, _ := json.Marshal(room)
+	rh.manager.SendToRoom(wsmanager.Message{
+		Type:     "room_updated",
+		RoomID:   room.RoomID,
+		UserUUID: userUUID,
+		Data:     dataBytes,
+	})
+
+	message := "Room archived successfully"
+	if !archived {
+		message = "Room unarchived successfully"
+	}
+	utils.ResponseSuccess(c, message, room)
+}
@@ -5,17 +5,23 @@ import (
 	"chat-app/internal/services/v1"
 	"chat-app/internal/utils"
 	"chat-app/internal/validation"
+	wsmanager "chat-app/pkg/websocket"
+	"encoding/json"
+	"log"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
 type UserHandler struct {
-	userService services.UserService
+	userService    services.UserService
+	accountService services.AccountService
+	manager        *wsmanager.Manager
 }
 
-func NewUserHandler(userService services.UserService) *UserHandler {
-	return &UserHandler{userService: userService}
+func NewUserHandler(userService services.UserService, accountService services.AccountService, manager *wsmanager.Manager) *UserHandler {
+	return &UserHandler{userService: userService, accountService: accountService, manager: manager}
 }
 
 func (uh *UserHandler) CreateUser(ctx *gin.Context) {
@@ -37,3 +43,125 @@ func (uh *UserHandler) CreateUser(ctx *gin.Context) {
 	// ctx.JSON(http.StatusOK, user)
 
 }
+
+// UpdateProfile godoc
+// @Summary Update my profile
+// @Description Update the authenticated user's display name, avatar, bio, timezone and locale. Only fields present in the body are changed; send "" to clear avatar or bio
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body v1Dto.UpdateProfileInput true "Profile fields"
+// @Success 200 {object} utils.Response{data=v1Dto.UserDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/me [patch]
+func (uh *UserHandler) UpdateProfile(c *gin.Context) {
+	var req v1Dto.UpdateProfileInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(c, validation.HandleValidationError(err))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	user, err := uh.userService.UpdateProfile(c, userUUID, req)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	// Thông báo cho các phòng có người khác để client cập nhật danh sách thành viên đã cache
+	roomIDs, err := uh.userService.ListSharedRoomIDs(c.Request.Context(), userUUID)
+	if err != nil {
+		log.Printf("❌ Error loading shared rooms of user %s: %v", userUUID, err)
+	}
+	dataBytes, _ := json.Marshal(v1Dto.MapUserToProfileDTO(user))
+	for _, roomID := range roomIDs {
+		uh.manager.SendToRoom(wsmanager.Message{
+			Type:     "user_updated",
+			RoomID:   roomID,
+			UserUUID: userUUID,
+			Data:     dataBytes,
+		})
+	}
+
+	utils.ResponseSuccess(c, "Profile updated successfully", v1Dto.MapUserToDTO(user))
+}
+
+// DeleteAccount godoc
+// @Summary Delete my account
+// @Description Permanently erase the authenticated user's account after confirming the password. Profile, memberships, notifications and devices are removed; messages stay in their rooms attributed to "Deleted user"
+// @Tags users
+// @Accept json
+// @Produce json
+// @Param request body v1Dto.DeleteAccountInput true "Current password"
+// @Success 200 {object} utils.Response
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/me [delete]
+func (uh *UserHandler) DeleteAccount(c *gin.Context) {
+	var req v1Dto.DeleteAccountInput
+	if err := c.ShouldBindJSON(&req); err != nil {
+		utils.ResponseValidator(c, validation.HandleValidationError(err))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	if err := uh.accountService.DeleteOwnAccount(c, userUUID, req.Password); err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponseSuccess(c, "Account deleted successfully", nil)
+}
+
+// DisconnectAccount đóng mọi kết nối websocket của user khi tài khoản bị xóa
+func (uh *UserHandler) DisconnectAccount(userUUID uuid.UUID, status string) {
+	disconnectUser(uh.manager, userUUID, "account_"+status)
+}
+
+// SearchDirectory godoc
+// @Summary Search user directory
+// @Description Find people to start a direct message with or invite to a room. Matches name (prefix, word prefix, fuzzy) and email prefix; deactivated and non-discoverable users are excluded
+// @Tags users
+// @Produce json
+// @Param q query string true "Name or email (at least 2 characters)"
+// @Param cursor query string false "next_cursor from the previous page"
+// @Param limit query int false "Limit (default 20, max 50)"
+// @Success 200 {object} utils.Response{data=[]v1Dto.UserProfileDTO}
+// @Failure 400 {object} utils.ErrorResponse
+// @Failure 401 {object} utils.ErrorResponse
+// @Router /api/v1/users/directory [get]
+func (uh *UserHandler) SearchDirectory(c *gin.Context) {
+	var query v1Dto.UserDirectoryQuery
+	if err := c.ShouldBindQuery(&query); err != nil {
+		utils.ResponseValidator(c, validation.HandleValidationError(err))
+		return
+	}
+
+	userUUID, err := utils.GetUserUUID(c)
+	if err != nil {
+		utils.ResponseError(c, utils.NewError("unauthorized", utils.ErrorCodeUnauthorized))
+		return
+	}
+
+	users, pagination, err := uh.userService.SearchDirectory(c, userUUID, query)
+	if err != nil {
+		utils.ResponseError(c, err)
+		return
+	}
+
+	utils.ResponSuccess(c, http.StatusOK, "Users retrieved successfully", map[string]any{
+		"data":       users,
+		"pagination": pagination,
+	})
+}
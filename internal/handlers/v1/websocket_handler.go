@@ -2,11 +2,14 @@ package v1Handler
 
 import (
 	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
 	"chat-app/internal/services/v1"
+	"chat-app/internal/utils"
 	"chat-app/pkg/auth"
 	wsmanager "chat-app/pkg/websocket"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
@@ -18,6 +21,9 @@ import (
 	"github.com/gorilla/websocket"
 )
 
+// blockersLookupTimeout giới hạn thời gian tải danh sách người chặn trong luồng broadcast
+const blockersLookupTimeout = 2 * time.Second
+
 var upgrader = websocket.Upgrader{
 	ReadBufferSize:  1024,
 	WriteBufferSize: 1024,
@@ -36,6 +42,7 @@ type WebSocketHandler struct {
 	roomService    services.RoomService
 	messageService services.MessageService
 	userService    services.UserService
+	blockService   services.BlockService
 	jwtService     auth.TokenService
 
 	// Async message processing
@@ -64,6 +71,7 @@ func NewWebSocketHandler(manager *wsmanager.Manager,
 	roomService services.RoomService,
 	messageService services.MessageService,
 	userService services.UserService,
+	blockService services.BlockService,
 	jwtService auth.TokenService) *WebSocketHandler {
 
 	// Create membership cache with 5 minute TTL
@@ -74,6 +82,7 @@ func NewWebSocketHandler(manager *wsmanager.Manager,
 		roomService:     roomService,
 		messageService:  messageService,
 		userService:     userService,
+		blockService:    blockService,
 		jwtService:      jwtService,
 		messageQueue:    make(chan MessageTask, 1000),    // Buffer 1000 messages
 		workerPool:      make(chan chan MessageTask, 10), // 10 workers
@@ -91,6 +100,9 @@ func NewWebSocketHandler(manager *wsmanager.Manager,
 	cachedCallback := wsmanager.CachedRoomMembershipCheckFunc(originalCallback, membershipCache)
 	manager.SetRoomMembershipCallback(cachedCallback)
 
+	// Tin nhắn mới không được gửi tới người đã chặn người gửi
+	manager.SetRecipientFilterCallback(handler.hiddenRecipients)
+
 	return handler
 }
 
@@ -171,6 +183,13 @@ func (wh *WebSocketHandler) HandleWebSocket(c *gin.Context) {
 		return
 	}
 
+	// Tài khoản bị tạm khóa, vô hiệu hóa hoặc đã xóa không được kết nối
+	if err := wh.userService.EnsureAccountActive(c.Request.Context(), userID); err != nil {
+		log.Printf("WebSocket rejected for user %s: %v", userID, err)
+		utils.ResponseError(c, err)
+		return
+	}
+
 	// Bỏ yêu cầu room_id khi kết nối
 	log.Printf("WebSocket auth success for user: %s", claims.UserUUID)
 
@@ -371,11 +390,7 @@ func (wh *WebSocketHandler) handleSendMessage(msg wsmanager.Message, client *wsm
 	message, err := wh.messageService.CreateMessage(context.Background(), params)
 	if err != nil {
 		log.Printf("❌ Error saving message to DB: %v", err)
-		errMsg := wsmanager.Message{
-			Type:    "error",
-			Content: "Failed to save message",
-		}
-		wh.sendToClient(client, errMsg)
+		wh.sendError(client, msg.RoomID, err, "Failed to save message")
 		return
 	}
 
@@ -388,20 +403,55 @@ func (wh *WebSocketHandler) handleSendMessage(msg wsmanager.Message, client *wsm
 		return
 	}
 
-	// Prepare message data with user info
+	// Broadcast message to room với thứ tự đảm bảo
+	broadcastMsg := newMessageEvent(message, user)
+
+	log.Printf("📡 Broadcasting message %d to room %d by user %s", message.MessageID, message.RoomID, user.UserFullname)
+	wh.manager.SendToRoom(broadcastMsg)
+	log.Printf("✅ Message broadcast completed")
+}
+
+// sendError gửi lỗi nghiệp vụ (AppError) về client, các lỗi khác dùng thông báo mặc định
+func (wh *WebSocketHandler) sendError(client *wsmanager.Client, roomID int64, err error, fallback string) {
+	errMsg := wsmanager.Message{
+		Type:    "error",
+		RoomID:  roomID,
+		Content: fallback,
+	}
+
+	var appErr *utils.AppError
+	if errors.As(err, &appErr) && appErr.Code != utils.ErrorCodeInternalServer {
+		errMsg.Content = utils.CapitalizeFirst(appErr.Message)
+		data := map[string]interface{}{
+			"code": appErr.Code,
+		}
+		if appErr.RetryAfter > 0 {
+			data["retry_after"] = appErr.RetryAfterSeconds() // số giây phải chờ
+		}
+		errMsg.Data, _ = json.Marshal(data)
+	}
+
+	wh.sendToClient(client, errMsg)
+}
+
+// newMessageEvent tạo sự kiện "new_message" kèm thông tin người gửi (và file đính kèm nếu có)
+func newMessageEvent(message sqlc.Message, user sqlc.User, attachments ...v1Dto.AttachmentDTO) wsmanager.Message {
 	messageData := map[string]interface{}{
 		"message_id":    message.MessageID,
 		"content":       message.Content,
+		"message_type":  message.MessageType,
 		"user_uuid":     message.UserUuid.String(),
 		"user_fullname": user.UserFullname,
 		"user_email":    user.UserEmail,
 		"created_at":    message.MessageCreatedAt.Format(time.RFC3339),
 	}
+	if len(attachments) > 0 {
+		messageData["attachments"] = attachments
+	}
 
 	dataBytes, _ := json.Marshal(messageData)
 
-	// Broadcast message to room với thứ tự đảm bảo
-	broadcastMsg := wsmanager.Message{
+	return wsmanager.Message{
 		Type:      "new_message",
 		RoomID:    message.RoomID,
 		UserUUID:  message.UserUuid,
@@ -411,10 +461,57 @@ func (wh *WebSocketHandler) handleSendMessage(msg wsmanager.Message, client *wsm
 		MessageID: &message.MessageID,
 		Priority:  1,
 	}
+}
 
-	log.Printf("📡 Broadcasting message %d to room %d by user %s", message.MessageID, message.RoomID, user.UserFullname)
-	wh.manager.SendToRoom(broadcastMsg)
-	log.Printf("✅ Message broadcast completed")
+// hiddenRecipients trả về những người đã chặn người gửi của sự kiện "new_message"
+func (wh *WebSocketHandler) hiddenRecipients(msg wsmanager.Message) map[uuid.UUID]bool {
+	if msg.Type != "new_message" {
+		return nil
+	}
+
+	// Chạy trong luồng broadcast của manager nên giới hạn thời gian chờ DB (kết quả thường lấy từ cache)
+	ctx, cancel := context.WithTimeout(context.Background(), blockersLookupTimeout)
+	defer cancel()
+
+	blockers, err := wh.blockService.ListBlockers(ctx, msg.UserUUID)
+	if err != nil {
+		log.Printf("❌ Error loading blockers of user %s: %v", msg.UserUUID, err)
+		return nil
+	}
+	return blockers
+}
+
+// NotifyMentions gửi sự kiện "mention" tới mọi kết nối của người được nhắc,
+// kể cả khi họ chưa join phòng đó qua websocket
+func (wh *WebSocketHandler) NotifyMentions(message sqlc.Message, mentions []sqlc.MessageMention) {
+	sender, err := wh.userService.GetUserByUUIDWithContext(context.Background(), message.UserUuid.String())
+	if err != nil {
+		log.Printf("❌ Error getting user info: %v", err)
+		return
+	}
+
+	for _, mention := range mentions {
+		dataBytes, _ := json.Marshal(map[string]interface{}{
+			"mention_id":      mention.MentionID,
+			"mention_type":    mention.MentionType,
+			"message_id":      message.MessageID,
+			"room_id":         message.RoomID,
+			"content":         message.Content,
+			"sender_uuid":     message.UserUuid.String(),
+			"sender_fullname": sender.UserFullname,
+			"created_at":      message.MessageCreatedAt.Format(time.RFC3339),
+		})
+
+		wh.manager.SendToUser(mention.MentionedUserUuid, wsmanager.Message{
+			Type:      "mention",
+			RoomID:    message.RoomID,
+			UserUUID:  message.UserUuid,
+			Content:   message.Content,
+			Timestamp: message.MessageCreatedAt.Format(time.RFC3339),
+			Data:      dataBytes,
+			MessageID: &message.MessageID,
+		})
+	}
 }
 
 // sendToClient safely sends message to client with backpressure handling
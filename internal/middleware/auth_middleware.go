@@ -3,13 +3,19 @@ package middleware
 import (
 	"chat-app/internal/utils"
 	"chat-app/pkg/auth"
+	"context"
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 )
 
+// AccountStatusCheckFunc trả về lỗi nếu tài khoản không còn được phép dùng API (bị tạm khóa, vô hiệu hóa, xóa)
+type AccountStatusCheckFunc func(ctx context.Context, userUUID uuid.UUID) error
+
 var (
 	jwtService auth.TokenService // Khai báo biến jwtService để sử dụng trong middleware
+	accountStatusCheck AccountStatusCheckFunc // Kiểm tra trạng thái tài khoản sau khi token hợp lệ
 	//cacheService cache.RedisCacheService // Khai báo biến cacheService để sử dụng trong middleware
 )
 func InitAuthMiddleware(jwtSvc auth.TokenService) {
@@ -17,6 +23,11 @@ func InitAuthMiddleware(jwtSvc auth.TokenService) {
 	//cacheService = cache // Khởi tạo cacheService với RedisCacheService đã được inject
 }
 
+// SetAccountStatusCheck đăng ký hàm kiểm tra trạng thái tài khoản cho AuthMiddleware
+func SetAccountStatusCheck(check AccountStatusCheckFunc) {
+	accountStatusCheck = check
+}
+
 func AuthMiddleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		authHeader := c.GetHeader("Authorization")
@@ -44,6 +55,21 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// Token còn hạn nhưng tài khoản có thể đã bị tạm khóa/vô hiệu hóa sau khi đăng nhập
+		if accountStatusCheck != nil {
+			userUUID, err := uuid.Parse(claims.UserUUID)
+			if err != nil {
+				utils.ResponseError(c, utils.NewError("invalid token", utils.ErrorCodeUnauthorized))
+				c.Abort()
+				return
+			}
+			if err := accountStatusCheck(c.Request.Context(), userUUID); err != nil {
+				utils.ResponseError(c, err)
+				c.Abort()
+				return
+			}
+		}
+
 		// Set claims in context với đầy đủ thông tin
 		c.Set("userUUID", claims.UserUUID)
 		c.Set("userEmail", claims.Email)
@@ -0,0 +1,35 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SqlAccountRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlAccountRepository(db sqlc.Querier) AccountRepository {
+	return &SqlAccountRepository{db: db}
+}
+
+func (r *SqlAccountRepository) SuspendUser(ctx context.Context, params sqlc.SuspendUserParams) (sqlc.User, error) {
+	return r.db.SuspendUser(ctx, params)
+}
+
+func (r *SqlAccountRepository) UnsuspendUser(ctx context.Context, userUUID uuid.UUID) (sqlc.User, error) {
+	return r.db.UnsuspendUser(ctx, userUUID)
+}
+
+func (r *SqlAccountRepository) SetUserDeactivated(ctx context.Context, userUUID uuid.UUID, deactivated bool) (sqlc.User, error) {
+	return r.db.SetUserDeactivated(ctx, sqlc.SetUserDeactivatedParams{
+		Deactivated: deactivated,
+		UserUuid:    userUUID,
+	})
+}
+
+func (r *SqlAccountRepository) AnonymizeUser(ctx context.Context, userUUID uuid.UUID) (sqlc.AnonymizeUserRow, error) {
+	return r.db.AnonymizeUser(ctx, userUUID)
+}
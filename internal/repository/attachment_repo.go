@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+)
+
+type SqlAttachmentRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlAttachmentRepository(db sqlc.Querier) AttachmentRepository {
+	return &SqlAttachmentRepository{db: db}
+}
+
+func (r *SqlAttachmentRepository) GetAttachmentByID(ctx context.Context, attachmentID int64) (sqlc.MessageAttachment, error) {
+	return r.db.GetAttachmentByID(ctx, attachmentID)
+}
+
+func (r *SqlAttachmentRepository) ListAttachmentsByMessageIDs(ctx context.Context, messageIDs []int64) ([]sqlc.MessageAttachment, error) {
+	return r.db.ListAttachmentsByMessageIDs(ctx, messageIDs)
+}
+
+func (r *SqlAttachmentRepository) MarkAttachmentProcessed(ctx context.Context, params sqlc.MarkAttachmentProcessedParams) (sqlc.MessageAttachment, error) {
+	return r.db.MarkAttachmentProcessed(ctx, params)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+)
+
+type SqlAuditRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlAuditRepository(db sqlc.Querier) AuditRepository {
+	return &SqlAuditRepository{db: db}
+}
+
+func (r *SqlAuditRepository) CreateAuditEvent(ctx context.Context, params sqlc.CreateAuditEventParams) error {
+	return r.db.CreateAuditEvent(ctx, params)
+}
+
+func (r *SqlAuditRepository) ListAuditEvents(ctx context.Context, params sqlc.ListAuditEventsParams) ([]sqlc.ListAuditEventsRow, error) {
+	return r.db.ListAuditEvents(ctx, params)
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SqlBlockRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlBlockRepository(db sqlc.Querier) BlockRepository {
+	return &SqlBlockRepository{db: db}
+}
+
+func (r *SqlBlockRepository) CreateUserBlock(ctx context.Context, blockerUUID, blockedUUID uuid.UUID) (sqlc.UserBlock, error) {
+	return r.db.CreateUserBlock(ctx, sqlc.CreateUserBlockParams{
+		BlockerUuid: blockerUUID,
+		BlockedUuid: blockedUUID,
+	})
+}
+
+func (r *SqlBlockRepository) DeleteUserBlock(ctx context.Context, blockerUUID, blockedUUID uuid.UUID) (int64, error) {
+	return r.db.DeleteUserBlock(ctx, sqlc.DeleteUserBlockParams{
+		BlockerUuid: blockerUUID,
+		BlockedUuid: blockedUUID,
+	})
+}
+
+func (r *SqlBlockRepository) ListUserBlocks(ctx context.Context, blockerUUID uuid.UUID) ([]sqlc.ListUserBlocksRow, error) {
+	return r.db.ListUserBlocks(ctx, blockerUUID)
+}
+
+func (r *SqlBlockRepository) ListBlockedUserIDs(ctx context.Context, blockerUUID uuid.UUID) ([]uuid.UUID, error) {
+	return r.db.ListBlockedUserIDs(ctx, blockerUUID)
+}
+
+func (r *SqlBlockRepository) ListBlockerIDs(ctx context.Context, blockedUUID uuid.UUID) ([]uuid.UUID, error) {
+	return r.db.ListBlockerIDs(ctx, blockedUUID)
+}
+
+func (r *SqlBlockRepository) HasBlockWithRoomMembers(ctx context.Context, userUUID uuid.UUID, roomID int64) (bool, error) {
+	return r.db.HasBlockWithRoomMembers(ctx, sqlc.HasBlockWithRoomMembersParams{
+		RoomID:   roomID,
+		UserUuid: userUUID,
+	})
+}
@@ -0,0 +1,65 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SqlDataExportRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlDataExportRepository(db sqlc.Querier) DataExportRepository {
+	return &SqlDataExportRepository{db: db}
+}
+
+func (r *SqlDataExportRepository) CreateDataExport(ctx context.Context, userUUID uuid.UUID, requestedBy *uuid.UUID) (sqlc.DataExport, error) {
+	return r.db.CreateDataExport(ctx, sqlc.CreateDataExportParams{
+		UserUuid:        userUUID,
+		RequestedByUuid: requestedBy,
+	})
+}
+
+func (r *SqlDataExportRepository) GetDataExport(ctx context.Context, exportID int64) (sqlc.DataExport, error) {
+	return r.db.GetDataExport(ctx, exportID)
+}
+
+func (r *SqlDataExportRepository) GetActiveDataExport(ctx context.Context, userUUID uuid.UUID) (sqlc.DataExport, error) {
+	return r.db.GetActiveDataExport(ctx, userUUID)
+}
+
+func (r *SqlDataExportRepository) ListUserDataExports(ctx context.Context, userUUID uuid.UUID, limit int32) ([]sqlc.DataExport, error) {
+	return r.db.ListUserDataExports(ctx, sqlc.ListUserDataExportsParams{
+		UserUuid: userUUID,
+		Limit:    limit,
+	})
+}
+
+func (r *SqlDataExportRepository) StartDataExport(ctx context.Context, exportID int64) (sqlc.DataExport, error) {
+	return r.db.StartDataExport(ctx, exportID)
+}
+
+func (r *SqlDataExportRepository) CompleteDataExport(ctx context.Context, params sqlc.CompleteDataExportParams) (sqlc.DataExport, error) {
+	return r.db.CompleteDataExport(ctx, params)
+}
+
+func (r *SqlDataExportRepository) FailDataExport(ctx context.Context, exportID int64, reason string) (sqlc.DataExport, error) {
+	return r.db.FailDataExport(ctx, sqlc.FailDataExportParams{
+		ExportError: &reason,
+		ExportID:    exportID,
+	})
+}
+
+func (r *SqlDataExportRepository) ResumeDataExports(ctx context.Context) ([]sqlc.DataExport, error) {
+	return r.db.ResumeDataExports(ctx)
+}
+
+func (r *SqlDataExportRepository) ListExpiredDataExports(ctx context.Context, limit int32) ([]sqlc.DataExport, error) {
+	return r.db.ListExpiredDataExports(ctx, limit)
+}
+
+func (r *SqlDataExportRepository) ExpireDataExport(ctx context.Context, exportID int64) error {
+	return r.db.ExpireDataExport(ctx, exportID)
+}
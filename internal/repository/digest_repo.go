@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SqlDigestRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlDigestRepository(db sqlc.Querier) DigestRepository {
+	return &SqlDigestRepository{db: db}
+}
+
+func (r *SqlDigestRepository) ListDigestRecipients(ctx context.Context, params sqlc.ListDigestRecipientsParams) ([]sqlc.User, error) {
+	return r.db.ListDigestRecipients(ctx, params)
+}
+
+func (r *SqlDigestRepository) ListDigestMentions(ctx context.Context, params sqlc.ListDigestMentionsParams) ([]sqlc.ListDigestMentionsRow, error) {
+	return r.db.ListDigestMentions(ctx, params)
+}
+
+func (r *SqlDigestRepository) ListDigestDirectMessages(ctx context.Context, params sqlc.ListDigestDirectMessagesParams) ([]sqlc.ListDigestDirectMessagesRow, error) {
+	return r.db.ListDigestDirectMessages(ctx, params)
+}
+
+func (r *SqlDigestRepository) MarkDigestSent(ctx context.Context, userUUID uuid.UUID) error {
+	return r.db.MarkDigestSent(ctx, userUUID)
+}
+
+func (r *SqlDigestRepository) UpdateUserDigestFrequency(ctx context.Context, userUUID uuid.UUID, frequency string) (sqlc.User, error) {
+	return r.db.UpdateUserDigestFrequency(ctx, sqlc.UpdateUserDigestFrequencyParams{
+		UserUuid:            userUUID,
+		UserDigestFrequency: frequency,
+	})
+}
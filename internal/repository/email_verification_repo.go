@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SqlEmailVerificationRepository struct {
+	db   sqlc.Querier
+	pool *pgxpool.Pool
+}
+
+func NewSqlEmailVerificationRepository(db sqlc.Querier, pool *pgxpool.Pool) EmailVerificationRepository {
+	return &SqlEmailVerificationRepository{db: db, pool: pool}
+}
+
+func (r *SqlEmailVerificationRepository) CreateEmailVerificationToken(ctx context.Context, params sqlc.CreateEmailVerificationTokenParams) (sqlc.EmailVerificationToken, error) {
+	return r.db.CreateEmailVerificationToken(ctx, params)
+}
+
+func (r *SqlEmailVerificationRepository) GetEmailVerificationToken(ctx context.Context, tokenID uuid.UUID) (sqlc.EmailVerificationToken, error) {
+	return r.db.GetEmailVerificationToken(ctx, tokenID)
+}
+
+func (r *SqlEmailVerificationRepository) CountEmailVerificationTokensSince(ctx context.Context, userUUID uuid.UUID, since time.Time) (int64, error) {
+	return r.db.CountEmailVerificationTokensSince(ctx, sqlc.CountEmailVerificationTokensSinceParams{
+		UserUuid:       userUUID,
+		TokenCreatedAt: since,
+	})
+}
+
+// VerifyEmailWithToken đánh dấu token đã dùng và xác minh email của user trong cùng một transaction.
+// Trả về false nếu token đã được dùng (request đồng thời với cùng token), pgx.ErrNoRows nếu user đã đổi email
+func (r *SqlEmailVerificationRepository) VerifyEmailWithToken(ctx context.Context, tokenID, userUUID uuid.UUID, email string) (sqlc.User, bool, error) {
+	var user sqlc.User
+	used := false
+	err := inTx(ctx, r.pool, func(q *sqlc.Queries) error {
+		rows, err := q.UseEmailVerificationToken(ctx, tokenID)
+		if err != nil || rows == 0 {
+			return err
+		}
+		user, err = q.MarkUserEmailVerified(ctx, sqlc.MarkUserEmailVerifiedParams{
+			UserUuid:  userUUID,
+			UserEmail: email,
+		})
+		if err != nil {
+			return err
+		}
+		used = true
+		return nil
+	})
+	return user, used, err
+}
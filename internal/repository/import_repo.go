@@ -0,0 +1,72 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SqlImportRepository struct {
+	db   sqlc.Querier
+	pool *pgxpool.Pool
+}
+
+func NewSqlImportRepository(db sqlc.Querier, pool *pgxpool.Pool) ImportRepository {
+	return &SqlImportRepository{db: db, pool: pool}
+}
+
+func (r *SqlImportRepository) GetImportedRoom(ctx context.Context, source, externalID string) (sqlc.ImportedRoom, error) {
+	return r.db.GetImportedRoom(ctx, sqlc.GetImportedRoomParams{
+		ImportSource: source,
+		ExternalID:   externalID,
+	})
+}
+
+// CreateImportedRoomWithOwner tạo phòng, thêm chủ phòng và ghi nhận phòng đã import trong cùng một transaction,
+// tránh để lại phòng không có chủ hoặc chưa ghi nhận (lần import sau sẽ tạo trùng) khi một bước bị lỗi
+func (r *SqlImportRepository) CreateImportedRoomWithOwner(ctx context.Context, params sqlc.CreateRoomParams, ownerRole, source, externalID string) (sqlc.Room, error) {
+	var room sqlc.Room
+	err := inTx(ctx, r.pool, func(q *sqlc.Queries) error {
+		var err error
+		if room, err = q.CreateRoom(ctx, params); err != nil {
+			return err
+		}
+		if err := q.AddImportedRoomMember(ctx, sqlc.AddImportedRoomMemberParams{
+			UserUuid:   params.RoomCreatedBy,
+			RoomID:     room.RoomID,
+			MemberRole: ownerRole,
+		}); err != nil {
+			return err
+		}
+		_, err = q.CreateImportedRoom(ctx, sqlc.CreateImportedRoomParams{
+			ImportSource: source,
+			ExternalID:   externalID,
+			RoomID:       room.RoomID,
+		})
+		return err
+	})
+	return room, err
+}
+
+// AddImportedRoomMember bỏ qua nếu user đã là thành viên (giữ nguyên vai trò hiện tại)
+func (r *SqlImportRepository) AddImportedRoomMember(ctx context.Context, userUUID uuid.UUID, roomID int64, role string) error {
+	return r.db.AddImportedRoomMember(ctx, sqlc.AddImportedRoomMemberParams{
+		UserUuid:   userUUID,
+		RoomID:     roomID,
+		MemberRole: role,
+	})
+}
+
+func (r *SqlImportRepository) ListExistingExternalMessageIDs(ctx context.Context, roomID int64, externalIDs []string) ([]string, error) {
+	return r.db.ListExistingExternalMessageIDs(ctx, sqlc.ListExistingExternalMessageIDsParams{
+		RoomID:      roomID,
+		ExternalIds: externalIDs,
+	})
+}
+
+// CopyImportedMessages ghi một lô tin nhắn bằng COPY, nhanh hơn nhiều so với INSERT từng dòng
+func (r *SqlImportRepository) CopyImportedMessages(ctx context.Context, messages []sqlc.CopyImportedMessagesParams) (int64, error) {
+	return r.db.CopyImportedMessages(ctx, messages)
+}
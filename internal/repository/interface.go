@@ -3,6 +3,7 @@ package repository
 import (
 	"chat-app/internal/db/sqlc"
 	"context"
+	"time"
 
 	"github.com/google/uuid"
 )
@@ -11,23 +12,36 @@ type UserRepository interface {
 	CreateUser(ctx context.Context, userParam sqlc.CreateUserParams) (sqlc.User, error)
 	GetUserByEmail(ctx context.Context, email string) (sqlc.User, error)
 	GetUserByUUID(ctx context.Context, uuid uuid.UUID) (sqlc.User, error)
+	UpdateUserLastSeen(ctx context.Context, userUUID uuid.UUID) error
+	UpdateUserPassword(ctx context.Context, userUUID uuid.UUID, hashedPassword string) (sqlc.User, error)
+	UpdateUserProfile(ctx context.Context, params sqlc.UpdateUserProfileParams) (sqlc.User, error)
+	ListSharedRoomIDs(ctx context.Context, userUUID uuid.UUID) ([]int64, error)
+	SearchUserDirectory(ctx context.Context, params sqlc.SearchUserDirectoryParams) ([]sqlc.SearchUserDirectoryRow, error)
 
 	// Admin methods
 	GetAllUsers(ctx context.Context, limit, offset int32) ([]sqlc.User, error)
-	DeleteUser(ctx context.Context, userUUID uuid.UUID) error
 }
 
 type RoomRepository interface {
 	CreateRoom(ctx context.Context, params sqlc.CreateRoomParams) (sqlc.Room, error)
 	JoinRoom(ctx context.Context, userUUID uuid.UUID, roomID int64) (sqlc.RoomMember, error)
-	LeaveRoom(ctx context.Context, userUUID uuid.UUID, roomID int64) error
+	AddRoomMember(ctx context.Context, userUUID uuid.UUID, roomID int64, role string) (sqlc.RoomMember, error)
+	GetRoomMember(ctx context.Context, userUUID uuid.UUID, roomID int64) (sqlc.RoomMember, error)
+	LeaveRoom(ctx context.Context, userUUID uuid.UUID, roomID int64) (*sqlc.RoomMember, error)
+	TransferRoomOwnership(ctx context.Context, roomID int64, currentOwnerUUID, newOwnerUUID uuid.UUID) error
 	GetRoomByID(ctx context.Context, roomID int64) (sqlc.Room, error)
 	GetRoomByCode(ctx context.Context, code string) (sqlc.Room, error)
 	ListUserRooms(ctx context.Context, userUUID uuid.UUID) ([]sqlc.Room, error)
-	ListUserRoomsWithLastMessage(ctx context.Context, userUUID uuid.UUID) ([]sqlc.ListUserRoomsWithLastMessageRow, error)
+	ListUserRoomsWithLastMessage(ctx context.Context, userUUID uuid.UUID, includeArchived bool) ([]sqlc.ListUserRoomsWithLastMessageRow, error)
 	IsUserMemberOfRoom(ctx context.Context, userUUID uuid.UUID, roomID int64) (bool, error)
 	GetRoomMembers(ctx context.Context, roomID int64) ([]sqlc.User, error)
 	GenerateUniqueRoomCode(ctx context.Context) (string, error)
+	UpdateRoomSettings(ctx context.Context, params sqlc.UpdateRoomSettingsParams, systemMessages []sqlc.CreateSystemMessageParams) (sqlc.Room, []sqlc.Message, error)
+	ArchiveRoom(ctx context.Context, roomID int64) (sqlc.Room, error)
+	UnarchiveRoom(ctx context.Context, roomID int64) (sqlc.Room, error)
+	UpdateRoomMemberNotificationSettings(ctx context.Context, params sqlc.UpdateRoomMemberNotificationSettingsParams) (sqlc.RoomMember, error)
+	ListRoomMemberNotificationSettings(ctx context.Context, roomID int64) ([]sqlc.ListRoomMemberNotificationSettingsRow, error)
+	ListUserMemberships(ctx context.Context, userUUID uuid.UUID) ([]sqlc.ListUserMembershipsRow, error)
 
 	// Admin methods
 	GetAllRoomsWithMemberCount(ctx context.Context, limit, offset int32) ([]sqlc.GetAllRoomsWithMemberCountRow, error)
@@ -35,7 +49,104 @@ type RoomRepository interface {
 }
 
 type MessageRepository interface {
-	CreateMessage(ctx context.Context, params sqlc.CreateMessageParams) (sqlc.Message, error)
+	CreateMessage(ctx context.Context, params sqlc.CreateMessageParams, slowMode time.Duration) (sqlc.Message, error)
+	CreateMessageWithAttachment(ctx context.Context, params sqlc.CreateMessageParams, slowMode time.Duration, attachment sqlc.CreateAttachmentParams) (sqlc.Message, sqlc.MessageAttachment, error)
+	CreateSystemMessage(ctx context.Context, params sqlc.CreateSystemMessageParams) (sqlc.Message, error)
 	GetRoomMessages(ctx context.Context, params sqlc.GetRoomMessagesParams) ([]sqlc.Message, error)
+	GetRoomMessage(ctx context.Context, roomID, messageID int64) (sqlc.Message, error)
+	GetRoomMessagesBefore(ctx context.Context, params sqlc.GetRoomMessagesBeforeParams) ([]sqlc.Message, error)
+	GetRoomMessagesAfter(ctx context.Context, params sqlc.GetRoomMessagesAfterParams) ([]sqlc.Message, error)
 	CountRoomMessages(ctx context.Context, roomID int64) (int64, error)
+	SearchMessages(ctx context.Context, params sqlc.SearchMessagesParams) ([]sqlc.SearchMessagesRow, error)
+	ListUserMessages(ctx context.Context, params sqlc.ListUserMessagesParams) ([]sqlc.Message, error)
+	ListRoomMessagesForExport(ctx context.Context, params sqlc.ListRoomMessagesForExportParams) ([]sqlc.ListRoomMessagesForExportRow, error)
+	CreateMessageMentions(ctx context.Context, params sqlc.CreateMessageMentionsParams) ([]sqlc.MessageMention, error)
+	MarkRoomMentionsRead(ctx context.Context, userUUID uuid.UUID, roomID int64) (int64, error)
+}
+
+type AttachmentRepository interface {
+	GetAttachmentByID(ctx context.Context, attachmentID int64) (sqlc.MessageAttachment, error)
+	ListAttachmentsByMessageIDs(ctx context.Context, messageIDs []int64) ([]sqlc.MessageAttachment, error)
+	MarkAttachmentProcessed(ctx context.Context, params sqlc.MarkAttachmentProcessedParams) (sqlc.MessageAttachment, error)
+}
+
+type NotificationRepository interface {
+	CreateNotifications(ctx context.Context, params sqlc.CreateNotificationsParams) ([]sqlc.Notification, error)
+	ListNotifications(ctx context.Context, params sqlc.ListNotificationsParams) ([]sqlc.Notification, error)
+	CountUnreadNotifications(ctx context.Context, userUUID uuid.UUID) (int64, error)
+	MarkNotificationRead(ctx context.Context, notificationID int64, userUUID uuid.UUID) (sqlc.Notification, error)
+	MarkAllNotificationsRead(ctx context.Context, userUUID uuid.UUID) (int64, error)
+}
+
+type PushDeviceRepository interface {
+	UpsertPushDevice(ctx context.Context, params sqlc.UpsertPushDeviceParams) (sqlc.PushDevice, error)
+	ListUserPushDevices(ctx context.Context, userUUID uuid.UUID) ([]sqlc.PushDevice, error)
+	ListPushDevicesByUsers(ctx context.Context, userUUIDs []uuid.UUID) ([]sqlc.PushDevice, error)
+	DeletePushDevice(ctx context.Context, deviceID int64, userUUID uuid.UUID) (int64, error)
+	DeletePushDeviceByID(ctx context.Context, deviceID int64) error
+}
+
+type DigestRepository interface {
+	ListDigestRecipients(ctx context.Context, params sqlc.ListDigestRecipientsParams) ([]sqlc.User, error)
+	ListDigestMentions(ctx context.Context, params sqlc.ListDigestMentionsParams) ([]sqlc.ListDigestMentionsRow, error)
+	ListDigestDirectMessages(ctx context.Context, params sqlc.ListDigestDirectMessagesParams) ([]sqlc.ListDigestDirectMessagesRow, error)
+	MarkDigestSent(ctx context.Context, userUUID uuid.UUID) error
+	UpdateUserDigestFrequency(ctx context.Context, userUUID uuid.UUID, frequency string) (sqlc.User, error)
+}
+
+type EmailVerificationRepository interface {
+	CreateEmailVerificationToken(ctx context.Context, params sqlc.CreateEmailVerificationTokenParams) (sqlc.EmailVerificationToken, error)
+	GetEmailVerificationToken(ctx context.Context, tokenID uuid.UUID) (sqlc.EmailVerificationToken, error)
+	CountEmailVerificationTokensSince(ctx context.Context, userUUID uuid.UUID, since time.Time) (int64, error)
+	VerifyEmailWithToken(ctx context.Context, tokenID, userUUID uuid.UUID, email string) (sqlc.User, bool, error)
+}
+
+type PasswordResetRepository interface {
+	CreatePasswordResetToken(ctx context.Context, params sqlc.CreatePasswordResetTokenParams) (sqlc.PasswordResetToken, error)
+	GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (sqlc.PasswordResetToken, error)
+	ResetPasswordWithToken(ctx context.Context, tokenID, userUUID uuid.UUID, hashedPassword string) (bool, error)
+	InvalidateUserPasswordResetTokens(ctx context.Context, userUUID uuid.UUID) error
+	CountPasswordResetTokensSince(ctx context.Context, userUUID uuid.UUID, since time.Time) (int64, error)
+}
+
+type BlockRepository interface {
+	CreateUserBlock(ctx context.Context, blockerUUID, blockedUUID uuid.UUID) (sqlc.UserBlock, error)
+	DeleteUserBlock(ctx context.Context, blockerUUID, blockedUUID uuid.UUID) (int64, error)
+	ListUserBlocks(ctx context.Context, blockerUUID uuid.UUID) ([]sqlc.ListUserBlocksRow, error)
+	ListBlockedUserIDs(ctx context.Context, blockerUUID uuid.UUID) ([]uuid.UUID, error)
+	ListBlockerIDs(ctx context.Context, blockedUUID uuid.UUID) ([]uuid.UUID, error)
+	HasBlockWithRoomMembers(ctx context.Context, userUUID uuid.UUID, roomID int64) (bool, error)
+}
+
+type AccountRepository interface {
+	SuspendUser(ctx context.Context, params sqlc.SuspendUserParams) (sqlc.User, error)
+	UnsuspendUser(ctx context.Context, userUUID uuid.UUID) (sqlc.User, error)
+	SetUserDeactivated(ctx context.Context, userUUID uuid.UUID, deactivated bool) (sqlc.User, error)
+	AnonymizeUser(ctx context.Context, userUUID uuid.UUID) (sqlc.AnonymizeUserRow, error)
+}
+
+type DataExportRepository interface {
+	CreateDataExport(ctx context.Context, userUUID uuid.UUID, requestedBy *uuid.UUID) (sqlc.DataExport, error)
+	GetDataExport(ctx context.Context, exportID int64) (sqlc.DataExport, error)
+	GetActiveDataExport(ctx context.Context, userUUID uuid.UUID) (sqlc.DataExport, error)
+	ListUserDataExports(ctx context.Context, userUUID uuid.UUID, limit int32) ([]sqlc.DataExport, error)
+	StartDataExport(ctx context.Context, exportID int64) (sqlc.DataExport, error)
+	CompleteDataExport(ctx context.Context, params sqlc.CompleteDataExportParams) (sqlc.DataExport, error)
+	FailDataExport(ctx context.Context, exportID int64, reason string) (sqlc.DataExport, error)
+	ResumeDataExports(ctx context.Context) ([]sqlc.DataExport, error)
+	ListExpiredDataExports(ctx context.Context, limit int32) ([]sqlc.DataExport, error)
+	ExpireDataExport(ctx context.Context, exportID int64) error
+}
+
+type ImportRepository interface {
+	GetImportedRoom(ctx context.Context, source, externalID string) (sqlc.ImportedRoom, error)
+	CreateImportedRoomWithOwner(ctx context.Context, params sqlc.CreateRoomParams, ownerRole, source, externalID string) (sqlc.Room, error)
+	AddImportedRoomMember(ctx context.Context, userUUID uuid.UUID, roomID int64, role string) error
+	ListExistingExternalMessageIDs(ctx context.Context, roomID int64, externalIDs []string) ([]string, error)
+	CopyImportedMessages(ctx context.Context, messages []sqlc.CopyImportedMessagesParams) (int64, error)
+}
+
+type AuditRepository interface {
+	CreateAuditEvent(ctx context.Context, params sqlc.CreateAuditEventParams) error
+	ListAuditEvents(ctx context.Context, params sqlc.ListAuditEventsParams) ([]sqlc.ListAuditEventsRow, error)
 }
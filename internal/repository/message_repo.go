@@ -3,26 +3,175 @@ package repository
 import (
 	"chat-app/internal/db/sqlc"
 	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 
 
 type SqlMessageRepository struct {
-	db sqlc.Querier
+	db   sqlc.Querier
+	pool *pgxpool.Pool
+}
+
+func NewSqlMessageRepository(db sqlc.Querier, pool *pgxpool.Pool) MessageRepository {
+	return &SqlMessageRepository{db: db, pool: pool}
+}
+
+// SlowModeError: user đã gửi tin nhắn trong khoảng slow mode, RetryAfter là thời gian còn phải chờ
+type SlowModeError struct {
+	RetryAfter time.Duration
+}
+
+func (e *SlowModeError) Error() string {
+	return fmt.Sprintf("slow mode: retry after %s", e.RetryAfter)
+}
+
+// CreateMessage lưu tin nhắn. slowMode > 0 thì kiểm tra lần gửi gần nhất và lưu trong cùng một transaction
+// (giữ advisory lock theo phòng và user) nên các request đồng thời không cùng vượt qua slow mode
+func (r *SqlMessageRepository) CreateMessage(ctx context.Context, params sqlc.CreateMessageParams, slowMode time.Duration) (sqlc.Message, error) {
+	if slowMode <= 0 {
+		row, err := r.db.CreateMessage(ctx, params)
+		return toMessage(row), err
+	}
+
+	var message sqlc.Message
+	err := inTx(ctx, r.pool, func(q *sqlc.Queries) error {
+		var err error
+		message, err = createMessage(ctx, q, params, slowMode)
+		return err
+	})
+	return message, err
 }
 
-func NewSqlMessageRepository(db sqlc.Querier) MessageRepository {
-	return &SqlMessageRepository{db: db}
+// CreateMessageWithAttachment lưu tin nhắn và file đính kèm trong cùng một transaction,
+// không để lại tin nhắn thiếu file khi lưu file đính kèm bị lỗi. slowMode giống CreateMessage
+func (r *SqlMessageRepository) CreateMessageWithAttachment(ctx context.Context, params sqlc.CreateMessageParams, slowMode time.Duration, attachment sqlc.CreateAttachmentParams) (sqlc.Message, sqlc.MessageAttachment, error) {
+	var message sqlc.Message
+	var saved sqlc.MessageAttachment
+	err := inTx(ctx, r.pool, func(q *sqlc.Queries) error {
+		var err error
+		if message, err = createMessage(ctx, q, params, slowMode); err != nil {
+			return err
+		}
+
+		attachment.MessageID = message.MessageID
+		saved, err = q.CreateAttachment(ctx, attachment)
+		return err
+	})
+	return message, saved, err
 }
 
-func (r *SqlMessageRepository) CreateMessage(ctx context.Context, params sqlc.CreateMessageParams) (sqlc.Message, error) {
-	return r.db.CreateMessage(ctx, params)
+// createMessage lưu tin nhắn trong transaction q, trả về *SlowModeError nếu user vừa gửi trong khoảng slowMode
+func createMessage(ctx context.Context, q *sqlc.Queries, params sqlc.CreateMessageParams, slowMode time.Duration) (sqlc.Message, error) {
+	if slowMode > 0 {
+		if err := q.LockUserRoomPosting(ctx, sqlc.LockUserRoomPostingParams{
+			RoomID:   params.RoomID,
+			UserUuid: params.UserUuid,
+		}); err != nil {
+			return sqlc.Message{}, err
+		}
+
+		lastSentAt, err := q.GetLastUserMessageTime(ctx, sqlc.GetLastUserMessageTimeParams{
+			RoomID:   params.RoomID,
+			UserUuid: params.UserUuid,
+		})
+		if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.Message{}, err
+		}
+		if err == nil {
+			if remaining := time.Until(lastSentAt.Add(slowMode)); remaining > 0 {
+				return sqlc.Message{}, &SlowModeError{RetryAfter: remaining}
+			}
+		}
+	}
+
+	row, err := q.CreateMessage(ctx, params)
+	return toMessage(row), err
+}
+
+func (r *SqlMessageRepository) CreateSystemMessage(ctx context.Context, params sqlc.CreateSystemMessageParams) (sqlc.Message, error) {
+	row, err := r.db.CreateSystemMessage(ctx, params)
+	return toMessage(row), err
 }
 
 func (r *SqlMessageRepository) GetRoomMessages(ctx context.Context, params sqlc.GetRoomMessagesParams) ([]sqlc.Message, error) {
-	return r.db.GetRoomMessages(ctx, params)
+	rows, err := r.db.GetRoomMessages(ctx, params)
+	return toMessages(rows), err
+}
+
+func (r *SqlMessageRepository) GetRoomMessage(ctx context.Context, roomID, messageID int64) (sqlc.Message, error) {
+	row, err := r.db.GetRoomMessage(ctx, sqlc.GetRoomMessageParams{
+		RoomID:    roomID,
+		MessageID: messageID,
+	})
+	return toMessage(row), err
+}
+
+func (r *SqlMessageRepository) GetRoomMessagesBefore(ctx context.Context, params sqlc.GetRoomMessagesBeforeParams) ([]sqlc.Message, error) {
+	rows, err := r.db.GetRoomMessagesBefore(ctx, params)
+	return toMessages(rows), err
+}
+
+func (r *SqlMessageRepository) GetRoomMessagesAfter(ctx context.Context, params sqlc.GetRoomMessagesAfterParams) ([]sqlc.Message, error) {
+	rows, err := r.db.GetRoomMessagesAfter(ctx, params)
+	return toMessages(rows), err
 }
 
 func (r *SqlMessageRepository) CountRoomMessages(ctx context.Context, roomID int64) (int64, error) {
 	return r.db.CountRoomMessages(ctx, roomID)
-}
\ No newline at end of file
+}
+
+func (r *SqlMessageRepository) SearchMessages(ctx context.Context, params sqlc.SearchMessagesParams) ([]sqlc.SearchMessagesRow, error) {
+	return r.db.SearchMessages(ctx, params)
+}
+func (r *SqlMessageRepository) ListUserMessages(ctx context.Context, params sqlc.ListUserMessagesParams) ([]sqlc.Message, error) {
+	rows, err := r.db.ListUserMessages(ctx, params)
+	return toMessages(rows), err
+}
+func (r *SqlMessageRepository) ListRoomMessagesForExport(ctx context.Context, params sqlc.ListRoomMessagesForExportParams) ([]sqlc.ListRoomMessagesForExportRow, error) {
+	return r.db.ListRoomMessagesForExport(ctx, params)
+}
+func (r *SqlMessageRepository) CreateMessageMentions(ctx context.Context, params sqlc.CreateMessageMentionsParams) ([]sqlc.MessageMention, error) {
+	return r.db.CreateMessageMentions(ctx, params)
+}
+
+func (r *SqlMessageRepository) MarkRoomMentionsRead(ctx context.Context, userUUID uuid.UUID, roomID int64) (int64, error) {
+	return r.db.MarkRoomMentionsRead(ctx, sqlc.MarkRoomMentionsReadParams{
+		MentionedUserUuid: userUUID,
+		RoomID:            roomID,
+	})
+}
+
+// messageRow là các row sqlc của query tin nhắn. Các query này liệt kê cột thay vì * để không kéo
+// content_tsv về ứng dụng nên có chung một bộ cột, đổi được sang nhau và sang sqlc.Message
+type messageRow interface {
+	sqlc.CreateMessageRow | sqlc.CreateSystemMessageRow | sqlc.GetRoomMessageRow | sqlc.GetRoomMessagesRow |
+		sqlc.GetRoomMessagesBeforeRow | sqlc.GetRoomMessagesAfterRow | sqlc.ListUserMessagesRow
+}
+
+func toMessage[T messageRow](row T) sqlc.Message {
+	m := sqlc.GetRoomMessageRow(row)
+	return sqlc.Message{
+		MessageID:         m.MessageID,
+		RoomID:            m.RoomID,
+		UserUuid:          m.UserUuid,
+		Content:           m.Content,
+		MessageCreatedAt:  m.MessageCreatedAt,
+		MessageType:       m.MessageType,
+		MessageExternalID: m.MessageExternalID,
+	}
+}
+
+func toMessages[T messageRow](rows []T) []sqlc.Message {
+	messages := make([]sqlc.Message, 0, len(rows))
+	for _, row := range rows {
+		messages = append(messages, toMessage(row))
+	}
+	return messages
+}
@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SqlNotificationRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlNotificationRepository(db sqlc.Querier) NotificationRepository {
+	return &SqlNotificationRepository{db: db}
+}
+
+func (r *SqlNotificationRepository) CreateNotifications(ctx context.Context, params sqlc.CreateNotificationsParams) ([]sqlc.Notification, error) {
+	return r.db.CreateNotifications(ctx, params)
+}
+
+func (r *SqlNotificationRepository) ListNotifications(ctx context.Context, params sqlc.ListNotificationsParams) ([]sqlc.Notification, error) {
+	return r.db.ListNotifications(ctx, params)
+}
+
+func (r *SqlNotificationRepository) CountUnreadNotifications(ctx context.Context, userUUID uuid.UUID) (int64, error) {
+	return r.db.CountUnreadNotifications(ctx, userUUID)
+}
+
+func (r *SqlNotificationRepository) MarkNotificationRead(ctx context.Context, notificationID int64, userUUID uuid.UUID) (sqlc.Notification, error) {
+	return r.db.MarkNotificationRead(ctx, sqlc.MarkNotificationReadParams{
+		NotificationID: notificationID,
+		UserUuid:       userUUID,
+	})
+}
+
+func (r *SqlNotificationRepository) MarkAllNotificationsRead(ctx context.Context, userUUID uuid.UUID) (int64, error) {
+	return r.db.MarkAllNotificationsRead(ctx, userUUID)
+}
@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+type SqlPasswordResetRepository struct {
+	db   sqlc.Querier
+	pool *pgxpool.Pool
+}
+
+func NewSqlPasswordResetRepository(db sqlc.Querier, pool *pgxpool.Pool) PasswordResetRepository {
+	return &SqlPasswordResetRepository{db: db, pool: pool}
+}
+
+func (r *SqlPasswordResetRepository) CreatePasswordResetToken(ctx context.Context, params sqlc.CreatePasswordResetTokenParams) (sqlc.PasswordResetToken, error) {
+	return r.db.CreatePasswordResetToken(ctx, params)
+}
+
+func (r *SqlPasswordResetRepository) GetPasswordResetTokenByHash(ctx context.Context, tokenHash string) (sqlc.PasswordResetToken, error) {
+	return r.db.GetPasswordResetTokenByHash(ctx, tokenHash)
+}
+
+// ResetPasswordWithToken dùng token, lưu mật khẩu mới và hủy các token còn lại của user trong cùng một transaction.
+// Trả về false (không đổi gì) nếu token đã được dùng, vd: bởi một request đồng thời.
+func (r *SqlPasswordResetRepository) ResetPasswordWithToken(ctx context.Context, tokenID, userUUID uuid.UUID, hashedPassword string) (bool, error) {
+	used := false
+	err := inTx(ctx, r.pool, func(q *sqlc.Queries) error {
+		rows, err := q.UsePasswordResetToken(ctx, tokenID)
+		if err != nil || rows == 0 {
+			return err
+		}
+		if _, err := q.UpdateUserPassword(ctx, sqlc.UpdateUserPasswordParams{
+			UserUuid:     userUUID,
+			UserPassword: hashedPassword,
+		}); err != nil {
+			return err
+		}
+		if err := q.InvalidateUserPasswordResetTokens(ctx, userUUID); err != nil {
+			return err
+		}
+		used = true
+		return nil
+	})
+	return used, err
+}
+
+func (r *SqlPasswordResetRepository) InvalidateUserPasswordResetTokens(ctx context.Context, userUUID uuid.UUID) error {
+	return r.db.InvalidateUserPasswordResetTokens(ctx, userUUID)
+}
+
+func (r *SqlPasswordResetRepository) CountPasswordResetTokensSince(ctx context.Context, userUUID uuid.UUID, since time.Time) (int64, error) {
+	return r.db.CountPasswordResetTokensSince(ctx, sqlc.CountPasswordResetTokensSinceParams{
+		UserUuid:       userUUID,
+		TokenCreatedAt: since,
+	})
+}
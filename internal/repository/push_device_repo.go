@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type SqlPushDeviceRepository struct {
+	db sqlc.Querier
+}
+
+func NewSqlPushDeviceRepository(db sqlc.Querier) PushDeviceRepository {
+	return &SqlPushDeviceRepository{db: db}
+}
+
+func (r *SqlPushDeviceRepository) UpsertPushDevice(ctx context.Context, params sqlc.UpsertPushDeviceParams) (sqlc.PushDevice, error) {
+	return r.db.UpsertPushDevice(ctx, params)
+}
+
+func (r *SqlPushDeviceRepository) ListUserPushDevices(ctx context.Context, userUUID uuid.UUID) ([]sqlc.PushDevice, error) {
+	return r.db.ListUserPushDevices(ctx, userUUID)
+}
+
+func (r *SqlPushDeviceRepository) ListPushDevicesByUsers(ctx context.Context, userUUIDs []uuid.UUID) ([]sqlc.PushDevice, error) {
+	return r.db.ListPushDevicesByUsers(ctx, userUUIDs)
+}
+
+func (r *SqlPushDeviceRepository) DeletePushDevice(ctx context.Context, deviceID int64, userUUID uuid.UUID) (int64, error) {
+	return r.db.DeletePushDevice(ctx, sqlc.DeletePushDeviceParams{
+		DeviceID: deviceID,
+		UserUuid: userUUID,
+	})
+}
+
+func (r *SqlPushDeviceRepository) DeletePushDeviceByID(ctx context.Context, deviceID int64) error {
+	return r.db.DeletePushDeviceByID(ctx, deviceID)
+}
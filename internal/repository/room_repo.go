@@ -3,16 +3,20 @@ package repository
 import (
 	"chat-app/internal/db/sqlc"
 	"context"
+	"errors"
 
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
 )
 
 type SqlRoomRepository struct {
-	db sqlc.Querier
+	db   sqlc.Querier
+	pool *pgxpool.Pool
 }
 
-func NewSqlRoomRepository(db sqlc.Querier) RoomRepository {
-	return &SqlRoomRepository{db: db}
+func NewSqlRoomRepository(db sqlc.Querier, pool *pgxpool.Pool) RoomRepository {
+	return &SqlRoomRepository{db: db, pool: pool}
 }
 
 func (r *SqlRoomRepository) CreateRoom(ctx context.Context, params sqlc.CreateRoomParams) (sqlc.Room, error) {
@@ -27,12 +31,59 @@ func (r *SqlRoomRepository) JoinRoom(ctx context.Context, userUUID uuid.UUID, ro
 	return r.db.JoinRoom(ctx, params)
 }
 
-func (r *SqlRoomRepository) LeaveRoom(ctx context.Context, userUUID uuid.UUID, roomID int64) error {
-	params := sqlc.LeaveRoomParams{
+func (r *SqlRoomRepository) AddRoomMember(ctx context.Context, userUUID uuid.UUID, roomID int64, role string) (sqlc.RoomMember, error) {
+	params := sqlc.AddRoomMemberParams{
+		UserUuid:   userUUID,
+		RoomID:     roomID,
+		MemberRole: role,
+	}
+	return r.db.AddRoomMember(ctx, params)
+}
+
+func (r *SqlRoomRepository) GetRoomMember(ctx context.Context, userUUID uuid.UUID, roomID int64) (sqlc.RoomMember, error) {
+	params := sqlc.GetRoomMemberParams{
 		UserUuid: userUUID,
 		RoomID:   roomID,
 	}
-	return r.db.LeaveRoom(ctx, params)
+	return r.db.GetRoomMember(ctx, params)
+}
+
+// LeaveRoom xóa user khỏi phòng. Nếu user là Owner, trigger_promote_room_owner chọn chủ mới
+// trong cùng câu lệnh; chủ mới được trả về (nil nếu không có ai được chuyển quyền)
+func (r *SqlRoomRepository) LeaveRoom(ctx context.Context, userUUID uuid.UUID, roomID int64) (*sqlc.RoomMember, error) {
+	var newOwner *sqlc.RoomMember
+	err := inTx(ctx, r.pool, func(q *sqlc.Queries) error {
+		member, err := q.LeaveRoom(ctx, sqlc.LeaveRoomParams{
+			UserUuid: userUUID,
+			RoomID:   roomID,
+		})
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Đã rời phòng từ trước
+		}
+		if err != nil || member.MemberRole != "Owner" {
+			return err
+		}
+
+		owner, err := q.GetRoomOwner(ctx, roomID)
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil // Phòng không còn thành viên nào
+		}
+		if err != nil {
+			return err
+		}
+		newOwner = &owner
+		return nil
+	})
+	return newOwner, err
+}
+
+func (r *SqlRoomRepository) TransferRoomOwnership(ctx context.Context, roomID int64, currentOwnerUUID, newOwnerUUID uuid.UUID) error {
+	params := sqlc.TransferRoomOwnershipParams{
+		NewOwnerUuid:     newOwnerUUID,
+		RoomID:           roomID,
+		CurrentOwnerUuid: currentOwnerUUID,
+	}
+	return r.db.TransferRoomOwnership(ctx, params)
 }
 
 func (r *SqlRoomRepository) GetRoomByID(ctx context.Context, roomID int64) (sqlc.Room, error) {
@@ -47,8 +98,12 @@ func (r *SqlRoomRepository) ListUserRooms(ctx context.Context, userUUID uuid.UUI
 	return r.db.ListUserRooms(ctx, userUUID)
 }
 
-func (r *SqlRoomRepository) ListUserRoomsWithLastMessage(ctx context.Context, userUUID uuid.UUID) ([]sqlc.ListUserRoomsWithLastMessageRow, error) {
-	return r.db.ListUserRoomsWithLastMessage(ctx, userUUID)
+func (r *SqlRoomRepository) ListUserRoomsWithLastMessage(ctx context.Context, userUUID uuid.UUID, includeArchived bool) ([]sqlc.ListUserRoomsWithLastMessageRow, error) {
+	params := sqlc.ListUserRoomsWithLastMessageParams{
+		UserUuid:        userUUID,
+		IncludeArchived: includeArchived,
+	}
+	return r.db.ListUserRoomsWithLastMessage(ctx, params)
 }
 
 func (r *SqlRoomRepository) IsUserMemberOfRoom(ctx context.Context, userUUID uuid.UUID, roomID int64) (bool, error) {
@@ -75,6 +130,52 @@ func (r *SqlRoomRepository) GenerateUniqueRoomCode(ctx context.Context) (string,
 	return code, nil
 }
 
+// UpdateRoomSettings cập nhật phòng và lưu các tin nhắn hệ thống mô tả thay đổi trong cùng một transaction
+func (r *SqlRoomRepository) UpdateRoomSettings(ctx context.Context, params sqlc.UpdateRoomSettingsParams, systemMessages []sqlc.CreateSystemMessageParams) (sqlc.Room, []sqlc.Message, error) {
+	var room sqlc.Room
+	messages := make([]sqlc.Message, 0, len(systemMessages))
+	err := inTx(ctx, r.pool, func(q *sqlc.Queries) error {
+		var err error
+		room, err = q.UpdateRoomSettings(ctx, params)
+		if err != nil {
+			return err
+		}
+
+		for _, messageParams := range systemMessages {
+			row, err := q.CreateSystemMessage(ctx, messageParams)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, toMessage(row))
+		}
+		return nil
+	})
+	if err != nil {
+		return sqlc.Room{}, nil, err
+	}
+	return room, messages, nil
+}
+
+func (r *SqlRoomRepository) ArchiveRoom(ctx context.Context, roomID int64) (sqlc.Room, error) {
+	return r.db.ArchiveRoom(ctx, roomID)
+}
+
+func (r *SqlRoomRepository) UnarchiveRoom(ctx context.Context, roomID int64) (sqlc.Room, error) {
+	return r.db.UnarchiveRoom(ctx, roomID)
+}
+
+func (r *SqlRoomRepository) UpdateRoomMemberNotificationSettings(ctx context.Context, params sqlc.UpdateRoomMemberNotificationSettingsParams) (sqlc.RoomMember, error) {
+	return r.db.UpdateRoomMemberNotificationSettings(ctx, params)
+}
+
+func (r *SqlRoomRepository) ListRoomMemberNotificationSettings(ctx context.Context, roomID int64) ([]sqlc.ListRoomMemberNotificationSettingsRow, error) {
+	return r.db.ListRoomMemberNotificationSettings(ctx, roomID)
+}
+
+func (r *SqlRoomRepository) ListUserMemberships(ctx context.Context, userUUID uuid.UUID) ([]sqlc.ListUserMembershipsRow, error) {
+	return r.db.ListUserMemberships(ctx, userUUID)
+}
+
 // Admin methods
 func (r *SqlRoomRepository) GetAllRoomsWithMemberCount(ctx context.Context, limit, offset int32) ([]sqlc.GetAllRoomsWithMemberCountRow, error) {
 	return r.db.GetAllRoomsWithMemberCount(ctx, sqlc.GetAllRoomsWithMemberCountParams{
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"chat-app/internal/db/sqlc"
+	"context"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// inTx chạy fn trong một transaction, commit nếu fn thành công và rollback nếu fn trả về lỗi
+func inTx(ctx context.Context, pool *pgxpool.Pool, fn func(q *sqlc.Queries) error) error {
+	tx, err := pool.Begin(ctx)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback(ctx)
+
+	if err := fn(sqlc.New(pool).WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit(ctx)
+}
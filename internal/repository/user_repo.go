@@ -31,6 +31,29 @@ func (ur *SqlUserRepository) GetUserByUUID(ctx context.Context, userUuid uuid.UU
 	return ur.db.GetUserByUUID(ctx, userUuid)
 }
 
+func (ur *SqlUserRepository) UpdateUserLastSeen(ctx context.Context, userUUID uuid.UUID) error {
+	return ur.db.UpdateUserLastSeen(ctx, userUUID)
+}
+
+func (ur *SqlUserRepository) UpdateUserPassword(ctx context.Context, userUUID uuid.UUID, hashedPassword string) (sqlc.User, error) {
+	return ur.db.UpdateUserPassword(ctx, sqlc.UpdateUserPasswordParams{
+		UserUuid:     userUUID,
+		UserPassword: hashedPassword,
+	})
+}
+
+func (ur *SqlUserRepository) UpdateUserProfile(ctx context.Context, params sqlc.UpdateUserProfileParams) (sqlc.User, error) {
+	return ur.db.UpdateUserProfile(ctx, params)
+}
+
+func (ur *SqlUserRepository) ListSharedRoomIDs(ctx context.Context, userUUID uuid.UUID) ([]int64, error) {
+	return ur.db.ListSharedRoomIDs(ctx, userUUID)
+}
+
+func (ur *SqlUserRepository) SearchUserDirectory(ctx context.Context, params sqlc.SearchUserDirectoryParams) ([]sqlc.SearchUserDirectoryRow, error) {
+	return ur.db.SearchUserDirectory(ctx, params)
+}
+
 // Admin methods
 func (ur *SqlUserRepository) GetAllUsers(ctx context.Context, limit, offset int32) ([]sqlc.User, error) {
 	return ur.db.GetAllUsers(ctx, sqlc.GetAllUsersParams{
@@ -38,7 +61,3 @@ func (ur *SqlUserRepository) GetAllUsers(ctx context.Context, limit, offset int3
 		Offset: offset,
 	})
 }
-
-func (ur *SqlUserRepository) DeleteUser(ctx context.Context, userUUID uuid.UUID) error {
-	return ur.db.DeleteUser(ctx, userUUID)
-}
@@ -25,8 +25,12 @@ func (ar *AdminRoutes) Register(r *gin.RouterGroup) {
 	adminGroup.Use(middleware.RequireAdmin())   // Then check if user is admin
 
 	// User management routes
-	adminGroup.GET("/users", ar.adminHandler.GetAllUsers)              //✅
-	adminGroup.DELETE("/users/:user_uuid", ar.adminHandler.DeleteUser) //✅
+	adminGroup.GET("/users", ar.adminHandler.GetAllUsers)                           //✅
+	adminGroup.DELETE("/users/:user_uuid", ar.adminHandler.DeleteUser)              //✅
+	adminGroup.POST("/users/:user_uuid/suspend", ar.adminHandler.SuspendUser)       //✅ NEW
+	adminGroup.DELETE("/users/:user_uuid/suspend", ar.adminHandler.UnsuspendUser)   //✅ NEW
+	adminGroup.POST("/users/:user_uuid/deactivate", ar.adminHandler.DeactivateUser) //✅ NEW
+	adminGroup.POST("/users/:user_uuid/reactivate", ar.adminHandler.ReactivateUser) //✅ NEW
 
 	// Room management routes
 	adminGroup.GET("/rooms", ar.adminHandler.GetAllRooms)             //✅
@@ -0,0 +1,28 @@
+package v1Routes
+
+import (
+	v1Handler "chat-app/internal/handlers/v1"
+	"chat-app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditRoutes struct {
+	auditHandler *v1Handler.AuditHandler
+}
+
+func NewAuditRoutes(auditHandler *v1Handler.AuditHandler) *AuditRoutes {
+	return &AuditRoutes{auditHandler: auditHandler}
+}
+
+// Register implements Routes interface
+func (ar *AuditRoutes) Register(r *gin.RouterGroup) {
+	// Xem và xuất audit log
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(middleware.AuthMiddleware())
+	adminGroup.Use(middleware.RequireAdmin())
+	{
+		adminGroup.GET("/audit", ar.auditHandler.ListAuditEvents)          //✅ NEW
+		adminGroup.GET("/audit/export", ar.auditHandler.ExportAuditEvents) //✅ NEW
+	}
+}
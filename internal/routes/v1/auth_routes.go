@@ -20,10 +20,15 @@ func (ar *AuthRoutes) Register(r *gin.RouterGroup) {
 	authGroup := r.Group("/auth")
 
 	// Public routes
-	authGroup.POST("/login", ar.authHandler.Login)       //✅
-	authGroup.POST("/register", ar.authHandler.Register) //✅
+	authGroup.POST("/login", ar.authHandler.Login)                            //✅
+	authGroup.POST("/register", ar.authHandler.Register)                      //✅
+	authGroup.POST("/verify-email", ar.authHandler.VerifyEmail)               //✅ NEW
+	authGroup.POST("/verify-email/resend", ar.authHandler.ResendVerification) //✅ NEW
+	authGroup.POST("/forgot-password", ar.authHandler.ForgotPassword)         //✅ NEW
+	authGroup.POST("/reset-password", ar.authHandler.ResetPassword)           //✅ NEW
 
 	// Protected routes - require authentication
 	authGroup.POST("/logout", middleware.AuthMiddleware(), ar.authHandler.Logout) //✅ Now requires auth
 	authGroup.GET("/me", middleware.AuthMiddleware(), ar.authHandler.GetMe)
+	authGroup.POST("/change-password", middleware.AuthMiddleware(), ar.authHandler.ChangePassword) //✅ NEW
 }
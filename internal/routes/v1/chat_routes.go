@@ -8,14 +8,20 @@ import (
 )
 
 type ChatRoutes struct {
-	wsHandler      *v1Handler.WebSocketHandler
-	messageHandler *v1Handler.MessageHandler
+	wsHandler           *v1Handler.WebSocketHandler
+	messageHandler      *v1Handler.MessageHandler
+	attachmentHandler   *v1Handler.AttachmentHandler
+	notificationHandler *v1Handler.NotificationHandler
+	pushHandler         *v1Handler.PushHandler
 }
 
-func NewChatRoutes(wsHandler *v1Handler.WebSocketHandler, messageHandler *v1Handler.MessageHandler) *ChatRoutes {
+func NewChatRoutes(wsHandler *v1Handler.WebSocketHandler, messageHandler *v1Handler.MessageHandler, attachmentHandler *v1Handler.AttachmentHandler, notificationHandler *v1Handler.NotificationHandler, pushHandler *v1Handler.PushHandler) *ChatRoutes {
 	return &ChatRoutes{
-		wsHandler:      wsHandler,
-		messageHandler: messageHandler,
+		wsHandler:           wsHandler,
+		messageHandler:      messageHandler,
+		attachmentHandler:   attachmentHandler,
+		notificationHandler: notificationHandler,
+		pushHandler:         pushHandler,
 	}
 }
 
@@ -39,6 +45,43 @@ func (cr *ChatRoutes) Register(r *gin.RouterGroup) {
 	roomGroup.Use(middleware.AuthMiddleware()) // Add auth middleware!
 	{
 		roomGroup.GET("/:roomID/messages", cr.messageHandler.GetRoomMessages)
-		roomGroup.POST("/:roomID/messages", cr.messageHandler.SendMessage) /// api này sẽ không được dùng vì đã dùng thông qua websocket realtime thay vì dùng REST API nữa
+		roomGroup.GET("/:roomID/messages/:messageID/context", cr.messageHandler.GetMessageContext) //✅ NEW
+		roomGroup.POST("/:roomID/messages", cr.messageHandler.SendMessage)                         /// api này sẽ không được dùng vì đã dùng thông qua websocket realtime thay vì dùng REST API nữa
+		roomGroup.POST("/:roomID/attachments", cr.attachmentHandler.UploadAttachment)              //✅ NEW
+		roomGroup.POST("/:roomID/mentions/read", cr.messageHandler.MarkMentionsRead)               //✅ NEW
+		roomGroup.GET("/:roomID/export", cr.messageHandler.ExportRoomHistory)                      //✅ NEW
 	}
+
+	attachmentGroup := r.Group("/attachments")
+	{
+		// Tải file bằng URL có chữ ký, không cần header Authorization (dùng được cho thẻ <img>)
+		attachmentGroup.GET("/:attachmentID/download", cr.attachmentHandler.DownloadAttachment)
+		attachmentGroup.GET("/:attachmentID/thumbnail", cr.attachmentHandler.DownloadThumbnail)
+		attachmentGroup.GET("/:attachmentID", middleware.AuthMiddleware(), cr.attachmentHandler.GetAttachment) //✅ NEW
+	}
+
+	searchGroup := r.Group("/search")
+	searchGroup.Use(middleware.AuthMiddleware())
+	{
+		searchGroup.GET("/messages", cr.messageHandler.SearchMessages) //✅ NEW
+	}
+
+	notificationGroup := r.Group("/notifications")
+	notificationGroup.Use(middleware.AuthMiddleware())
+	{
+		notificationGroup.GET("", cr.notificationHandler.ListNotifications)                          //✅ NEW
+		notificationGroup.POST("/read-all", cr.notificationHandler.MarkAllNotificationsRead)         //✅ NEW
+		notificationGroup.POST("/:notificationID/read", cr.notificationHandler.MarkNotificationRead) //✅ NEW
+	}
+
+	deviceGroup := r.Group("/users/me/devices")
+	deviceGroup.Use(middleware.AuthMiddleware())
+	{
+		deviceGroup.POST("", cr.pushHandler.RegisterDevice)           //✅ NEW
+		deviceGroup.GET("", cr.pushHandler.ListDevices)               //✅ NEW
+		deviceGroup.DELETE("/:deviceID", cr.pushHandler.DeleteDevice) //✅ NEW
+	}
+
+	// Public key cho PushManager.subscribe() trên trình duyệt
+	r.GET("/push/vapid-public-key", cr.pushHandler.GetVAPIDPublicKey)
 }
@@ -0,0 +1,39 @@
+package v1Routes
+
+import (
+	v1Handler "chat-app/internal/handlers/v1"
+	"chat-app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DataExportRoutes struct {
+	dataExportHandler *v1Handler.DataExportHandler
+}
+
+func NewDataExportRoutes(dataExportHandler *v1Handler.DataExportHandler) *DataExportRoutes {
+	return &DataExportRoutes{dataExportHandler: dataExportHandler}
+}
+
+// Register implements Routes interface
+func (dr *DataExportRoutes) Register(r *gin.RouterGroup) {
+	meGroup := r.Group("/users/me/exports")
+	meGroup.Use(middleware.AuthMiddleware())
+	{
+		meGroup.POST("", dr.dataExportHandler.RequestExport)      //✅ NEW
+		meGroup.GET("", dr.dataExportHandler.ListExports)         //✅ NEW
+		meGroup.GET("/:exportID", dr.dataExportHandler.GetExport) //✅ NEW
+	}
+
+	// Tải file ZIP bằng URL có chữ ký, không cần header Authorization
+	r.GET("/exports/:exportID/download", dr.dataExportHandler.DownloadExport) //✅ NEW
+
+	// Xuất dữ liệu thay cho user (yêu cầu từ bộ phận compliance)
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(middleware.AuthMiddleware())
+	adminGroup.Use(middleware.RequireAdmin())
+	{
+		adminGroup.POST("/users/:user_uuid/exports", dr.dataExportHandler.RequestUserExport) //✅ NEW
+		adminGroup.GET("/exports/:exportID", dr.dataExportHandler.GetUserExport)             //✅ NEW
+	}
+}
@@ -0,0 +1,27 @@
+package v1Routes
+
+import (
+	v1Handler "chat-app/internal/handlers/v1"
+	"chat-app/internal/middleware"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ImportRoutes struct {
+	importHandler *v1Handler.ImportHandler
+}
+
+func NewImportRoutes(importHandler *v1Handler.ImportHandler) *ImportRoutes {
+	return &ImportRoutes{importHandler: importHandler}
+}
+
+// Register implements Routes interface
+func (ir *ImportRoutes) Register(r *gin.RouterGroup) {
+	// Import lịch sử chat từ Slack/Discord
+	adminGroup := r.Group("/admin")
+	adminGroup.Use(middleware.AuthMiddleware())
+	adminGroup.Use(middleware.RequireAdmin())
+	{
+		adminGroup.POST("/imports", ir.importHandler.ImportArchive) //✅ NEW
+	}
+}
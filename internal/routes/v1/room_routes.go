@@ -21,12 +21,18 @@ func (rr *RoomRoutes) Register(r *gin.RouterGroup) {
 	roomGroup := r.Group("/rooms")
 	roomGroup.Use(middleware.AuthMiddleware()) // Add auth middleware!
 	{
-		roomGroup.POST("", rr.roomHandler.CreateRoom)                    //✅
-		roomGroup.GET("", rr.roomHandler.ListRooms)                      //✅
-		roomGroup.GET("/:roomID", rr.roomHandler.GetRoom)                // chưa làm xong, ko có tác vụ trong web hiện tại
-		roomGroup.GET("/:roomID/members", rr.roomHandler.GetRoomMembers) //✅
-		roomGroup.POST("/join-by-code", rr.roomHandler.JoinRoomByCode)   //✅
-		roomGroup.POST("/:roomID/join", rr.roomHandler.JoinRoomByID)     //✅ NEW
-		roomGroup.POST("/:roomID/leave", rr.roomHandler.LeaveRoom)       //✅ NEW
+		roomGroup.POST("", rr.roomHandler.CreateRoom)                                              //✅
+		roomGroup.GET("", rr.roomHandler.ListRooms)                                                //✅
+		roomGroup.GET("/:roomID", rr.roomHandler.GetRoom)                                          //✅
+		roomGroup.PATCH("/:roomID", rr.roomHandler.UpdateRoom)                                     //✅ NEW
+		roomGroup.DELETE("/:roomID", rr.roomHandler.DeleteRoom)                                    //✅ NEW
+		roomGroup.GET("/:roomID/members", rr.roomHandler.GetRoomMembers)                           //✅
+		roomGroup.POST("/join-by-code", rr.roomHandler.JoinRoomByCode)                             //✅
+		roomGroup.POST("/:roomID/join", rr.roomHandler.JoinRoomByID)                               //✅ NEW
+		roomGroup.POST("/:roomID/leave", rr.roomHandler.LeaveRoom)                                 //✅ NEW
+		roomGroup.POST("/:roomID/transfer-ownership", rr.roomHandler.TransferOwnership)            //✅ NEW
+		roomGroup.POST("/:roomID/archive", rr.roomHandler.ArchiveRoom)                             //✅ NEW
+		roomGroup.POST("/:roomID/unarchive", rr.roomHandler.UnarchiveRoom)                         //✅ NEW
+		roomGroup.PUT("/:roomID/notification-settings", rr.roomHandler.UpdateNotificationSettings) //✅ NEW
 	}
 }
@@ -2,16 +2,19 @@ package v1Routes
 
 import (
 	v1Handler "chat-app/internal/handlers/v1"
+	"chat-app/internal/middleware"
 
 	"github.com/gin-gonic/gin"
 )
 
 type UserRoutes struct {
-	userHandle *v1Handler.UserHandler
+	userHandle    *v1Handler.UserHandler
+	digestHandler *v1Handler.DigestHandler
+	blockHandler  *v1Handler.BlockHandler
 }
 
-func NewUserRoutes(userHandle *v1Handler.UserHandler) *UserRoutes {
-	return &UserRoutes{userHandle: userHandle}
+func NewUserRoutes(userHandle *v1Handler.UserHandler, digestHandler *v1Handler.DigestHandler, blockHandler *v1Handler.BlockHandler) *UserRoutes {
+	return &UserRoutes{userHandle: userHandle, digestHandler: digestHandler, blockHandler: blockHandler}
 }
 
 // đăng kí các route liên quan đến user(implements Routes interface )
@@ -19,5 +22,18 @@ func (ur *UserRoutes) Register(r *gin.RouterGroup) {
 	userGroup := r.Group("/users")
 	{
 		userGroup.POST("", ur.userHandle.CreateUser)
+		userGroup.GET("/directory", middleware.AuthMiddleware(), ur.userHandle.SearchDirectory) //✅ NEW
+	}
+
+	meGroup := r.Group("/users/me")
+	meGroup.Use(middleware.AuthMiddleware())
+	{
+		meGroup.PATCH("", ur.userHandle.UpdateProfile)                      //✅ NEW
+		meGroup.DELETE("", ur.userHandle.DeleteAccount)                     //✅ NEW
+		meGroup.GET("/email-digest", ur.digestHandler.GetDigestSettings)    //✅ NEW
+		meGroup.PUT("/email-digest", ur.digestHandler.UpdateDigestSettings) //✅ NEW
+		meGroup.GET("/blocks", ur.blockHandler.ListBlockedUsers)            //✅ NEW
+		meGroup.POST("/blocks", ur.blockHandler.BlockUser)                  //✅ NEW
+		meGroup.DELETE("/blocks/:userUUID", ur.blockHandler.UnblockUser)    //✅ NEW
 	}
 }
\ No newline at end of file
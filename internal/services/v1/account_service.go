@@ -0,0 +1,246 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/utils"
+	"chat-app/pkg/auth"
+	"errors"
+	"log"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// AccountStatusCallback được gọi khi tài khoản bị tạm khóa, vô hiệu hóa hoặc xóa (vd: đóng kết nối websocket)
+type AccountStatusCallback func(userUUID uuid.UUID, status string)
+
+type accountService struct {
+	accountRepo  repository.AccountRepository
+	userRepo     repository.UserRepository
+	tokenService auth.TokenService
+	auditService AuditService
+
+	statusCallback AccountStatusCallback
+}
+
+func NewAccountService(accountRepo repository.AccountRepository, userRepo repository.UserRepository, tokenService auth.TokenService, auditService AuditService) AccountService {
+	return &accountService{
+		accountRepo:  accountRepo,
+		userRepo:     userRepo,
+		tokenService: tokenService,
+		auditService: auditService,
+	}
+}
+
+// SetAccountStatusCallback đăng ký hàm được gọi khi tài khoản không còn hoạt động
+func (as *accountService) SetAccountStatusCallback(callback AccountStatusCallback) {
+	as.statusCallback = callback
+}
+
+// SuspendUser tạm khóa tài khoản đến input.Until (nil = đến khi admin mở), tạm khóa lại sẽ ghi đè lần trước
+func (as *accountService) SuspendUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID, input v1Dto.SuspendUserInput) (sqlc.User, error) {
+	context := ctx.Request.Context()
+
+	if actorUUID == userUUID {
+		return sqlc.User{}, utils.NewError("you cannot suspend your own account", utils.ErrorCodeBadRequest)
+	}
+	if input.Until != nil && !input.Until.After(time.Now()) {
+		return sqlc.User{}, utils.NewError("until must be in the future", utils.ErrorCodeBadRequest)
+	}
+
+	user, err := as.accountRepo.SuspendUser(context, sqlc.SuspendUserParams{
+		SuspendedUntil:   input.Until,
+		SuspensionReason: input.Reason,
+		UserUuid:         userUUID,
+	})
+	if err != nil {
+		return sqlc.User{}, accountUpdateError(err, "could not suspend user")
+	}
+
+	as.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionUserSuspended,
+		ActorUUID:  &actorUUID,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   userUUID.String(),
+		Metadata:   map[string]any{"reason": input.Reason, "until": input.Until},
+	})
+
+	as.notifyStatus(userUUID, v1Dto.AccountStatusSuspended)
+	return user, nil
+}
+
+func (as *accountService) UnsuspendUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID) (sqlc.User, error) {
+	context := ctx.Request.Context()
+
+	user, err := as.accountRepo.UnsuspendUser(context, userUUID)
+	if err != nil {
+		return sqlc.User{}, accountUpdateError(err, "could not unsuspend user")
+	}
+
+	as.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionUserUnsuspended,
+		ActorUUID:  &actorUUID,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   userUUID.String(),
+	})
+	return user, nil
+}
+
+// DeactivateUser vô hiệu hóa tài khoản vô thời hạn, dữ liệu vẫn được giữ nguyên
+func (as *accountService) DeactivateUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID) (sqlc.User, error) {
+	context := ctx.Request.Context()
+
+	if actorUUID == userUUID {
+		return sqlc.User{}, utils.NewError("you cannot deactivate your own account", utils.ErrorCodeBadRequest)
+	}
+
+	user, err := as.accountRepo.SetUserDeactivated(context, userUUID, true)
+	if err != nil {
+		return sqlc.User{}, accountUpdateError(err, "could not deactivate user")
+	}
+
+	as.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionUserDeactivated,
+		ActorUUID:  &actorUUID,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   userUUID.String(),
+	})
+
+	as.notifyStatus(userUUID, v1Dto.AccountStatusDeactivated)
+	return user, nil
+}
+
+func (as *accountService) ReactivateUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID) (sqlc.User, error) {
+	context := ctx.Request.Context()
+
+	user, err := as.accountRepo.SetUserDeactivated(context, userUUID, false)
+	if err != nil {
+		return sqlc.User{}, accountUpdateError(err, "could not reactivate user")
+	}
+
+	as.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionUserReactivated,
+		ActorUUID:  &actorUUID,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   userUUID.String(),
+	})
+	return user, nil
+}
+
+// DeleteUser xóa (ẩn danh hóa) tài khoản của user khác, dùng cho admin
+func (as *accountService) DeleteUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID) error {
+	if actorUUID == userUUID {
+		return utils.NewError("use DELETE /users/me to delete your own account", utils.ErrorCodeBadRequest)
+	}
+	if err := as.erase(ctx, actorUUID, userUUID); err != nil {
+		return err
+	}
+
+	as.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionUserDeleted,
+		ActorUUID:  &actorUUID,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   userUUID.String(),
+	})
+	return nil
+}
+
+// DeleteOwnAccount xóa tài khoản của chính user sau khi xác nhận mật khẩu
+func (as *accountService) DeleteOwnAccount(ctx *gin.Context, userUUID uuid.UUID, password string) error {
+	context := ctx.Request.Context()
+
+	user, err := as.userRepo.GetUserByUUID(context, userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.UserPassword), []byte(password)); err != nil {
+		return utils.NewError("password is incorrect", utils.ErrorCodeBadRequest)
+	}
+
+	if err := as.erase(ctx, userUUID, userUUID); err != nil {
+		return err
+	}
+
+	as.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionUserDeleted,
+		ActorUUID:  &userUUID,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   userUUID.String(),
+		Metadata:   map[string]any{"self_service": true},
+	})
+	return nil
+}
+
+// erase ẩn danh hóa tài khoản: xóa dữ liệu cá nhân, giữ tin nhắn dưới tên "Deleted user",
+// thu hồi mọi phiên đăng nhập. Mỗi phòng được chuyển chủ ghi một sự kiện audit riêng.
+func (as *accountService) erase(ctx *gin.Context, actorUUID, userUUID uuid.UUID) error {
+	erased, err := as.accountRepo.AnonymizeUser(ctx.Request.Context(), userUUID)
+	if err != nil {
+		return accountUpdateError(err, "could not delete user")
+	}
+
+	for k, roomID := range erased.TransferredRoomIds {
+		as.auditService.Record(ctx, AuditEvent{
+			Action:     v1Dto.AuditActionRoomOwnershipChanged,
+			ActorUUID:  &actorUUID,
+			TargetType: v1Dto.AuditTargetRoom,
+			TargetID:   strconv.FormatInt(roomID, 10),
+			Metadata: map[string]any{
+				"previous_owner_uuid": userUUID,
+				"new_owner_uuid":      erased.NewOwnerUuids[k],
+				"reason":              "account_erasure",
+			},
+		})
+	}
+
+	if err := as.tokenService.RevokeUserTokens(userUUID); err != nil {
+		log.Printf("❌ Error revoking tokens of deleted user %s: %v", userUUID, err)
+	}
+
+	as.notifyStatus(userUUID, v1Dto.AccountStatusDeleted)
+	return nil
+}
+
+func (as *accountService) notifyStatus(userUUID uuid.UUID, status string) {
+	if as.statusCallback != nil {
+		as.statusCallback(userUUID, status)
+	}
+}
+
+// accountUpdateError: không có dòng nào được cập nhật nghĩa là user không tồn tại hoặc đã bị xóa
+func accountUpdateError(err error, message string) error {
+	if errors.Is(err, pgx.ErrNoRows) {
+		return utils.NewError("user not found", utils.ErrorCodeNotFound)
+	}
+	return utils.WrapError(err, message, utils.ErrorCodeInternalServer)
+}
+
+// accountStatusError trả về lỗi nếu tài khoản đã bị xóa, vô hiệu hóa hoặc đang bị tạm khóa
+func accountStatusError(user sqlc.User) error {
+	switch v1Dto.AccountStatus(user) {
+	case v1Dto.AccountStatusDeleted:
+		return utils.NewError("account has been deleted", utils.ErrorCodeUnauthorized)
+	case v1Dto.AccountStatusDeactivated:
+		return utils.NewError("account has been deactivated", utils.ErrorCodeForbidden)
+	case v1Dto.AccountStatusSuspended:
+		message := "account is suspended"
+		if user.UserSuspendedUntil != nil {
+			message += " until " + user.UserSuspendedUntil.UTC().Format(time.RFC3339)
+		}
+		if user.UserSuspensionReason != nil && *user.UserSuspensionReason != "" {
+			message += ": " + *user.UserSuspensionReason
+		}
+		return utils.NewError(message, utils.ErrorCodeForbidden)
+	}
+	return nil
+}
@@ -0,0 +1,160 @@
+package services
+
+import (
+	"bytes"
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/pkg/media"
+	"chat-app/pkg/storage"
+	"context"
+	"log"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const thumbnailMaxSize = 320 // cạnh dài nhất của thumbnail (px)
+
+// AttachmentProcessor tạo thumbnail và đọc kích thước ảnh trong worker pool chạy nền
+type AttachmentProcessor struct {
+	attachmentRepo repository.AttachmentRepository
+	storage        storage.Storage
+	urlSigner      *storage.URLSigner
+	maxSizeBytes   int64
+
+	// Async processing
+	jobQueue   chan sqlc.MessageAttachment
+	workerPool chan chan sqlc.MessageAttachment
+	quit       chan bool
+
+	// Callback khi xử lý xong (vd: gửi sự kiện websocket)
+	onProcessed func(roomID int64, attachment v1Dto.AttachmentDTO)
+}
+
+func NewAttachmentProcessor(attachmentRepo repository.AttachmentRepository, store storage.Storage, urlSigner *storage.URLSigner, maxSizeBytes int64) *AttachmentProcessor {
+	return &AttachmentProcessor{
+		attachmentRepo: attachmentRepo,
+		storage:        store,
+		urlSigner:      urlSigner,
+		maxSizeBytes:   maxSizeBytes,
+		jobQueue:       make(chan sqlc.MessageAttachment, 500), // Buffer 500 files
+		workerPool:     make(chan chan sqlc.MessageAttachment, 4),
+		quit:           make(chan bool),
+	}
+}
+
+// SetProcessedCallback đăng ký hàm được gọi sau khi một file xử lý xong
+func (ap *AttachmentProcessor) SetProcessedCallback(callback func(roomID int64, attachment v1Dto.AttachmentDTO)) {
+	ap.onProcessed = callback
+}
+
+// StartWorkerPool starts the worker pool for thumbnail generation
+func (ap *AttachmentProcessor) StartWorkerPool(numWorkers int) {
+	// Start dispatcher
+	go ap.dispatcher()
+
+	// Start workers
+	for i := 0; i < numWorkers; i++ {
+		work := make(chan sqlc.MessageAttachment)
+		go func() {
+			for {
+				// Register worker in the worker queue
+				ap.workerPool <- work
+
+				select {
+				case attachment := <-work:
+					ap.process(attachment)
+				case <-ap.quit:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Enqueue đưa file vào hàng đợi, chỉ những ảnh giải mã được mới cần xử lý
+func (ap *AttachmentProcessor) Enqueue(attachment sqlc.MessageAttachment) {
+	if !media.CanDecode(attachment.ContentType) {
+		return
+	}
+
+	select {
+	case ap.jobQueue <- attachment:
+		// Queued successfully
+	default:
+		// Queue is full, log warning and skip
+		log.Printf("⚠️ Attachment queue full, skipping thumbnail for attachment %d", attachment.AttachmentID)
+	}
+}
+
+// Dispatcher distributes jobs to available workers
+func (ap *AttachmentProcessor) dispatcher() {
+	for {
+		select {
+		case job := <-ap.jobQueue:
+			// Get an available worker
+			go func() {
+				worker := <-ap.workerPool
+				worker <- job
+			}()
+		case <-ap.quit:
+			return
+		}
+	}
+}
+
+func (ap *AttachmentProcessor) process(attachment sqlc.MessageAttachment) {
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	reader, err := ap.storage.Get(ctx, attachment.StorageKey)
+	if err != nil {
+		log.Printf("❌ Error reading attachment %d: %v", attachment.AttachmentID, err)
+		return
+	}
+	data, err := media.ReadAllLimit(reader, ap.maxSizeBytes)
+	reader.Close()
+	if err != nil {
+		log.Printf("❌ Error reading attachment %d: %v", attachment.AttachmentID, err)
+		return
+	}
+
+	params := sqlc.MarkAttachmentProcessedParams{AttachmentID: attachment.AttachmentID}
+
+	thumb, err := media.MakeThumbnail(data, thumbnailMaxSize)
+	if err != nil {
+		// Vẫn đánh dấu đã xử lý để client không chờ mãi
+		log.Printf("⚠️ Could not create thumbnail for attachment %d: %v", attachment.AttachmentID, err)
+	} else {
+		thumbExt := ".jpg"
+		if thumb.ContentType == "image/png" {
+			thumbExt = ".png"
+		}
+		thumbKey := strings.TrimSuffix(attachment.StorageKey, filepath.Ext(attachment.StorageKey)) + "_thumb" + thumbExt
+		if err := ap.storage.Put(ctx, thumbKey, bytes.NewReader(thumb.Data), int64(len(thumb.Data)), thumb.ContentType); err != nil {
+			log.Printf("❌ Error storing thumbnail for attachment %d: %v", attachment.AttachmentID, err)
+			return
+		}
+
+		width, height := int32(thumb.Width), int32(thumb.Height)
+		thumbWidth, thumbHeight := int32(thumb.ThumbWidth), int32(thumb.ThumbHeight)
+		params.ImageWidth = &width
+		params.ImageHeight = &height
+		params.ThumbnailKey = &thumbKey
+		params.ThumbnailContentType = &thumb.ContentType
+		params.ThumbnailWidth = &thumbWidth
+		params.ThumbnailHeight = &thumbHeight
+	}
+
+	processed, err := ap.attachmentRepo.MarkAttachmentProcessed(ctx, params)
+	if err != nil {
+		log.Printf("❌ Error saving thumbnail info for attachment %d: %v", attachment.AttachmentID, err)
+		return
+	}
+
+	log.Printf("✅ Attachment %d processed", attachment.AttachmentID)
+	if ap.onProcessed != nil {
+		ap.onProcessed(processed.RoomID, toAttachmentDTO(processed, ap.urlSigner))
+	}
+}
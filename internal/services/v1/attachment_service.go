@@ -0,0 +1,289 @@
+package services
+
+import (
+	"bytes"
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/utils"
+	"chat-app/pkg/media"
+	"chat-app/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// allowedAttachmentTypes: MIME type nhận diện từ nội dung file -> các phần mở rộng hợp lệ.
+// Không tin Content-Type do client gửi lên.
+var allowedAttachmentTypes = map[string][]string{
+	"image/jpeg":      {".jpg", ".jpeg"},
+	"image/png":       {".png"},
+	"image/gif":       {".gif"},
+	"image/webp":      {".webp"},
+	"application/pdf": {".pdf"},
+	"text/plain":      {".txt", ".md", ".csv", ".log"},
+	"video/mp4":       {".mp4"},
+	"audio/mpeg":      {".mp3"},
+	"application/zip": {".zip", ".docx", ".xlsx", ".pptx"},
+}
+
+const maxMessageContentLength = 2000 // khớp với chk_message_length
+
+type attachmentService struct {
+	attachmentRepo repository.AttachmentRepository
+	roomRepo       repository.RoomRepository
+	messageService MessageService
+	storage        storage.Storage
+	urlSigner      *storage.URLSigner
+	processor      *AttachmentProcessor
+	maxSizeBytes   int64
+}
+
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, roomRepo repository.RoomRepository, messageService MessageService, store storage.Storage, urlSigner *storage.URLSigner, processor *AttachmentProcessor, maxSizeBytes int64) AttachmentService {
+	return &attachmentService{
+		attachmentRepo: attachmentRepo,
+		roomRepo:       roomRepo,
+		messageService: messageService,
+		storage:        store,
+		urlSigner:      urlSigner,
+		processor:      processor,
+		maxSizeBytes:   maxSizeBytes,
+	}
+}
+
+// UploadAttachment lưu file vào storage rồi tạo tin nhắn (caption có thể rỗng) kèm file đính kèm
+func (as *attachmentService) UploadAttachment(ctx *gin.Context, roomID int64, userUUID uuid.UUID, file *multipart.FileHeader, caption string) (sqlc.Message, v1Dto.AttachmentDTO, error) {
+	context := ctx.Request.Context()
+
+	// Kiểm tra quyền gửi trước khi đẩy file lên storage
+	slowMode, err := as.messageService.EnsureCanPost(context, roomID, userUUID)
+	if err != nil {
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, err
+	}
+
+	caption = strings.TrimSpace(caption)
+	if len([]rune(caption)) > maxMessageContentLength {
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.NewError(fmt.Sprintf("content must be at most %d characters", maxMessageContentLength), utils.ErrorCodeBadRequest)
+	}
+	if file.Size <= 0 {
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.NewError("file is empty", utils.ErrorCodeBadRequest)
+	}
+	if file.Size > as.maxSizeBytes {
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.NewError(fmt.Sprintf("file exceeds the maximum size of %d MB", as.maxSizeBytes>>20), utils.ErrorCodeBadRequest)
+	}
+
+	fileName := sanitizeFileName(file.Filename)
+	ext := strings.ToLower(filepath.Ext(fileName))
+
+	src, err := file.Open()
+	if err != nil {
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.WrapError(err, "could not read uploaded file", utils.ErrorCodeBadRequest)
+	}
+	defer src.Close()
+
+	// Nhận diện MIME type từ 512 byte đầu
+	head := make([]byte, 512)
+	n, err := io.ReadFull(src, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.WrapError(err, "could not read uploaded file", utils.ErrorCodeBadRequest)
+	}
+	head = head[:n]
+
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	allowedExts, ok := allowedAttachmentTypes[contentType]
+	if !ok || !slices.Contains(allowedExts, ext) {
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.NewError("file type is not allowed", utils.ErrorCodeBadRequest)
+	}
+
+	// Xóa vị trí GPS trong EXIF/XMP trước khi lưu ảnh gốc (kích thước file không đổi)
+	switch contentType {
+	case "image/jpeg":
+		prefix := make([]byte, media.JPEGMetadataPrefix-len(head))
+		n, err := io.ReadFull(src, prefix)
+		if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+			return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.WrapError(err, "could not read uploaded file", utils.ErrorCodeBadRequest)
+		}
+		head = append(head, prefix[:n]...)
+		media.StripLocation(contentType, head)
+	case "image/png", "image/webp":
+		// Metadata có thể nằm sau dữ liệu ảnh (WebP luôn để EXIF/XMP ở cuối) nên đọc cả file
+		rest, err := media.ReadAllLimit(src, as.maxSizeBytes)
+		if err != nil {
+			return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.WrapError(err, "could not read uploaded file", utils.ErrorCodeBadRequest)
+		}
+		head = append(head, rest...)
+		media.StripLocation(contentType, head)
+	}
+
+	storageKey := fmt.Sprintf("rooms/%d/%s%s", roomID, uuid.New().String(), ext)
+	body := io.MultiReader(bytes.NewReader(head), src)
+	if err := as.storage.Put(context, storageKey, body, file.Size, contentType); err != nil {
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, utils.WrapError(err, "could not store file", utils.ErrorCodeInternalServer)
+	}
+
+	message, attachment, err := as.messageService.CreateMessageWithAttachment(context, sqlc.CreateMessageParams{
+		RoomID:   roomID,
+		UserUuid: userUUID,
+		Content:  caption,
+	}, slowMode, sqlc.CreateAttachmentParams{
+		RoomID:       roomID,
+		UploaderUuid: userUUID,
+		StorageKey:   storageKey,
+		FileName:     fileName,
+		ContentType:  contentType,
+		SizeBytes:    file.Size,
+	})
+	if err != nil {
+		as.deleteObject(storageKey)
+		return sqlc.Message{}, v1Dto.AttachmentDTO{}, err
+	}
+
+	// Tạo thumbnail ở worker nền, client nhận sự kiện attachment_processed khi xong
+	as.processor.Enqueue(attachment)
+
+	return message, toAttachmentDTO(attachment, as.urlSigner), nil
+}
+
+// GetAttachment trả về metadata kèm URL tải mới cho thành viên của phòng
+func (as *attachmentService) GetAttachment(ctx *gin.Context, attachmentID int64, userUUID uuid.UUID) (v1Dto.AttachmentDTO, error) {
+	context := ctx.Request.Context()
+
+	attachment, err := as.getAttachment(context, attachmentID)
+	if err != nil {
+		return v1Dto.AttachmentDTO{}, err
+	}
+
+	isMember, err := as.roomRepo.IsUserMemberOfRoom(context, userUUID, attachment.RoomID)
+	if err != nil {
+		return v1Dto.AttachmentDTO{}, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+
+	if !isMember {
+		return v1Dto.AttachmentDTO{}, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+	}
+
+	return toAttachmentDTO(attachment, as.urlSigner), nil
+}
+
+// OpenSignedAttachment mở nội dung file (hoặc thumbnail) khi URL có chữ ký hợp lệ, caller phải Close reader.
+// Với thumbnail, ContentType của attachment trả về là của thumbnail.
+func (as *attachmentService) OpenSignedAttachment(ctx *gin.Context, attachmentID int64, thumbnail bool, expires int64, signature string) (sqlc.MessageAttachment, io.ReadCloser, error) {
+	context := ctx.Request.Context()
+
+	if !as.urlSigner.Verify(attachmentResource(attachmentID, thumbnail), expires, signature) {
+		return sqlc.MessageAttachment{}, nil, utils.NewError("download link is invalid or expired", utils.ErrorCodeForbidden)
+	}
+
+	attachment, err := as.getAttachment(context, attachmentID)
+	if err != nil {
+		return sqlc.MessageAttachment{}, nil, err
+	}
+
+	storageKey := attachment.StorageKey
+	if thumbnail {
+		if attachment.ThumbnailKey == nil || attachment.ThumbnailContentType == nil {
+			return sqlc.MessageAttachment{}, nil, utils.NewError("thumbnail not found", utils.ErrorCodeNotFound)
+		}
+		storageKey = *attachment.ThumbnailKey
+		attachment.ContentType = *attachment.ThumbnailContentType
+		attachment.FileName = strings.TrimSuffix(attachment.FileName, filepath.Ext(attachment.FileName)) + "_thumb" + filepath.Ext(storageKey)
+	}
+
+	reader, err := as.storage.Get(context, storageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return sqlc.MessageAttachment{}, nil, utils.NewError("file not found", utils.ErrorCodeNotFound)
+		}
+		return sqlc.MessageAttachment{}, nil, utils.WrapError(err, "could not read file", utils.ErrorCodeInternalServer)
+	}
+
+	return attachment, reader, nil
+}
+
+func (as *attachmentService) getAttachment(ctx context.Context, attachmentID int64) (sqlc.MessageAttachment, error) {
+	attachment, err := as.attachmentRepo.GetAttachmentByID(ctx, attachmentID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.MessageAttachment{}, utils.NewError("attachment not found", utils.ErrorCodeNotFound)
+		}
+		return sqlc.MessageAttachment{}, utils.WrapError(err, "could not get attachment", utils.ErrorCodeInternalServer)
+	}
+	return attachment, nil
+}
+
+// deleteObject dọn file đã upload khi không lưu được tin nhắn
+func (as *attachmentService) deleteObject(storageKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := as.storage.Delete(ctx, storageKey); err != nil {
+		log.Printf("❌ Error deleting orphaned object %s: %v", storageKey, err)
+	}
+}
+
+func attachmentResource(attachmentID int64, thumbnail bool) string {
+	if thumbnail {
+		return fmt.Sprintf("attachments/%d/thumbnail", attachmentID)
+	}
+	return fmt.Sprintf("attachments/%d", attachmentID)
+}
+
+// toAttachmentDTO tạo DTO kèm URL tải (và URL thumbnail nếu có) có chữ ký
+func toAttachmentDTO(attachment sqlc.MessageAttachment, urlSigner *storage.URLSigner) v1Dto.AttachmentDTO {
+	expires, signature := urlSigner.Sign(attachmentResource(attachment.AttachmentID, false))
+	dto := v1Dto.AttachmentDTO{
+		AttachmentID:    attachment.AttachmentID,
+		MessageID:       attachment.MessageID,
+		FileName:        attachment.FileName,
+		ContentType:     attachment.ContentType,
+		SizeBytes:       attachment.SizeBytes,
+		URL:             fmt.Sprintf("/api/v1/attachments/%d/download?expires=%d&signature=%s", attachment.AttachmentID, expires, signature),
+		URLExpiresAt:    time.Unix(expires, 0).UTC(),
+		Processed:       attachment.AttachmentProcessedAt != nil,
+		ImageWidth:      attachment.ImageWidth,
+		ImageHeight:     attachment.ImageHeight,
+		ThumbnailWidth:  attachment.ThumbnailWidth,
+		ThumbnailHeight: attachment.ThumbnailHeight,
+	}
+
+	if attachment.ThumbnailKey != nil {
+		thumbExpires, thumbSignature := urlSigner.Sign(attachmentResource(attachment.AttachmentID, true))
+		thumbnailURL := fmt.Sprintf("/api/v1/attachments/%d/thumbnail?expires=%d&signature=%s", attachment.AttachmentID, thumbExpires, thumbSignature)
+		dto.ThumbnailURL = &thumbnailURL
+	}
+
+	return dto
+}
+
+// sanitizeFileName bỏ đường dẫn và ký tự điều khiển, giới hạn 255 ký tự
+func sanitizeFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	name = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || r == '"' {
+			return -1
+		}
+		return r
+	}, name)
+	name = strings.TrimSpace(name)
+	if name == "" || name == "." || name == "/" {
+		name = "file"
+	}
+	if runes := []rune(name); len(runes) > 255 {
+		ext := []rune(filepath.Ext(name))
+		name = string(runes[:255-len(ext)]) + string(ext)
+	}
+	return name
+}
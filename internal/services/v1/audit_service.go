@@ -0,0 +1,293 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/utils"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const (
+	auditExportPageSize     = 1000
+	maxAuditUserAgentLength = 500 // khớp với audit_events.audit_user_agent VARCHAR(500)
+)
+
+// AuditEvent là một hành động cần ghi vào audit log.
+// ActorUUID = nil thì lấy user đang đăng nhập của request (nếu có).
+type AuditEvent struct {
+	Action     string
+	ActorUUID  *uuid.UUID
+	TargetType string
+	TargetID   string
+	Metadata   map[string]any
+}
+
+// AuditExportStream ghi toàn bộ audit log khớp bộ lọc vào w theo từng trang
+type AuditExportStream func(w io.Writer) error
+
+type auditService struct {
+	auditRepo repository.AuditRepository
+}
+
+func NewAuditService(auditRepo repository.AuditRepository) AuditService {
+	return &auditService{auditRepo: auditRepo}
+}
+
+// Record ghi một sự kiện kèm IP và User-Agent của request.
+// Lỗi chỉ được log lại để không làm hỏng hành động chính đã thực hiện xong.
+func (as *auditService) Record(ctx *gin.Context, event AuditEvent) {
+	// Vẫn ghi khi client ngắt kết nối giữa chừng
+	context := context.WithoutCancel(ctx.Request.Context())
+
+	actor := event.ActorUUID
+	if actor == nil {
+		if userUUID, err := utils.GetUserUUID(ctx); err == nil {
+			actor = &userUUID
+		}
+	}
+
+	metadata := []byte("{}")
+	if len(event.Metadata) > 0 {
+		data, err := json.Marshal(event.Metadata)
+		if err != nil {
+			log.Printf("❌ Error encoding audit metadata for %s: %v", event.Action, err)
+		} else {
+			metadata = data
+		}
+	}
+
+	params := sqlc.CreateAuditEventParams{
+		ActorUuid:     actor,
+		AuditAction:   event.Action,
+		TargetType:    optionalString(event.TargetType),
+		TargetID:      optionalString(event.TargetID),
+		AuditIp:       optionalString(ctx.ClientIP()),
+		AuditMetadata: metadata,
+	}
+	if userAgent := []rune(ctx.Request.UserAgent()); len(userAgent) > 0 {
+		if len(userAgent) > maxAuditUserAgentLength {
+			userAgent = userAgent[:maxAuditUserAgentLength]
+		}
+		params.AuditUserAgent = optionalString(string(userAgent))
+	}
+
+	if err := as.auditRepo.CreateAuditEvent(context, params); err != nil {
+		log.Printf("❌ Error writing audit event %s: %v", event.Action, err)
+	}
+}
+
+func (as *auditService) ListEvents(ctx *gin.Context, query v1Dto.AuditQuery) ([]v1Dto.AuditEventDTO, v1Dto.CursorPagination, error) {
+	context := ctx.Request.Context()
+
+	if err := validateAuditFilter(query.AuditFilter); err != nil {
+		return nil, v1Dto.CursorPagination{}, err
+	}
+	if query.Limit == 0 {
+		query.Limit = 50
+	}
+
+	// Lấy thêm 1 bản ghi để biết còn trang sau hay không
+	rows, err := as.auditRepo.ListAuditEvents(context, auditListParams(query.AuditFilter, query.Cursor, query.Limit+1))
+	if err != nil {
+		return nil, v1Dto.CursorPagination{}, utils.WrapError(err, "could not list audit events", utils.ErrorCodeInternalServer)
+	}
+
+	pagination := v1Dto.CursorPagination{Limit: query.Limit}
+	if len(rows) > int(query.Limit) {
+		rows = rows[:query.Limit]
+		pagination.HasMore = true
+		nextCursor := rows[len(rows)-1].AuditID
+		pagination.NextCursor = &nextCursor
+	}
+
+	events := make([]v1Dto.AuditEventDTO, 0, len(rows))
+	for _, row := range rows {
+		events = append(events, toAuditEventDTO(row))
+	}
+	return events, pagination, nil
+}
+
+// ExportEvents kiểm tra bộ lọc rồi trả về thông tin file và hàm stream nội dung (mới nhất trước).
+// Bản thân việc xuất cũng được ghi vào audit log.
+func (as *auditService) ExportEvents(ctx *gin.Context, query v1Dto.AuditExportQuery) (v1Dto.AuditExportFile, AuditExportStream, error) {
+	context := ctx.Request.Context()
+
+	if err := validateAuditFilter(query.AuditFilter); err != nil {
+		return v1Dto.AuditExportFile{}, nil, err
+	}
+	if query.Format == "" {
+		query.Format = v1Dto.AuditExportFormatNDJSON
+	}
+
+	file := v1Dto.AuditExportFile{
+		FileName: fmt.Sprintf("audit-%s.%s", time.Now().UTC().Format("20060102-150405"), query.Format),
+	}
+	var newWriter func(w io.Writer) auditExportWriter
+	switch query.Format {
+	case v1Dto.AuditExportFormatNDJSON:
+		file.ContentType = "application/x-ndjson"
+		newWriter = func(w io.Writer) auditExportWriter { return &ndjsonAuditExport{enc: json.NewEncoder(w)} }
+	case v1Dto.AuditExportFormatCSV:
+		file.ContentType = "text/csv; charset=utf-8"
+		newWriter = func(w io.Writer) auditExportWriter { return &csvAuditExport{w: csv.NewWriter(w)} }
+	default:
+		return v1Dto.AuditExportFile{}, nil, utils.NewError("format must be one of ndjson, csv", utils.ErrorCodeBadRequest)
+	}
+
+	as.Record(ctx, AuditEvent{
+		Action:   v1Dto.AuditActionAuditExported,
+		Metadata: map[string]any{"format": query.Format, "filter": query.AuditFilter},
+	})
+
+	stream := func(w io.Writer) error {
+		return as.writeEvents(context, query.AuditFilter, newWriter(w), w)
+	}
+	return file, stream, nil
+}
+
+func (as *auditService) writeEvents(ctx context.Context, filter v1Dto.AuditFilter, writer auditExportWriter, w io.Writer) error {
+	if err := writer.begin(); err != nil {
+		return err
+	}
+
+	var beforeID *int64
+	for {
+		rows, err := as.auditRepo.ListAuditEvents(ctx, auditListParams(filter, beforeID, auditExportPageSize))
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			if err := writer.write(toAuditEventDTO(row)); err != nil {
+				return err
+			}
+		}
+		if err := writer.flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		if len(rows) < auditExportPageSize {
+			return nil
+		}
+		lastID := rows[len(rows)-1].AuditID
+		beforeID = &lastID
+	}
+}
+
+func validateAuditFilter(filter v1Dto.AuditFilter) error {
+	if filter.From != nil && filter.To != nil && !filter.From.Before(*filter.To) {
+		return utils.NewError("from must be before to", utils.ErrorCodeBadRequest)
+	}
+	return nil
+}
+
+func auditListParams(filter v1Dto.AuditFilter, beforeID *int64, limit int32) sqlc.ListAuditEventsParams {
+	return sqlc.ListAuditEventsParams{
+		ActorUuid:   filter.ActorUUID,
+		AuditAction: filter.Action,
+		TargetType:  filter.TargetType,
+		TargetID:    filter.TargetID,
+		FromTime:    filter.From,
+		ToTime:      filter.To,
+		BeforeID:    beforeID,
+		Limit:       limit,
+	}
+}
+
+func toAuditEventDTO(row sqlc.ListAuditEventsRow) v1Dto.AuditEventDTO {
+	return v1Dto.AuditEventDTO{
+		AuditID:    row.AuditID,
+		ActorUUID:  row.ActorUuid,
+		ActorEmail: row.ActorEmail,
+		ActorName:  row.ActorFullname,
+		Action:     row.AuditAction,
+		TargetType: row.TargetType,
+		TargetID:   row.TargetID,
+		IP:         row.AuditIp,
+		UserAgent:  row.AuditUserAgent,
+		Metadata:   row.AuditMetadata,
+		CreatedAt:  row.AuditCreatedAt.UTC(),
+	}
+}
+
+func optionalString(value string) *string {
+	if value == "" {
+		return nil
+	}
+	return &value
+}
+
+// auditExportWriter ghi file xuất audit log theo một định dạng
+type auditExportWriter interface {
+	begin() error
+	write(event v1Dto.AuditEventDTO) error
+	flush() error // gọi sau mỗi trang
+}
+
+// ndjsonAuditExport: mỗi sự kiện một dòng JSON
+type ndjsonAuditExport struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonAuditExport) begin() error { return nil }
+
+func (e *ndjsonAuditExport) write(event v1Dto.AuditEventDTO) error {
+	return e.enc.Encode(event)
+}
+
+func (e *ndjsonAuditExport) flush() error { return nil }
+
+// csvAuditExport: metadata được giữ nguyên dạng JSON trong một cột
+type csvAuditExport struct {
+	w *csv.Writer
+}
+
+func (e *csvAuditExport) begin() error {
+	return e.w.Write([]string{"audit_id", "created_at", "actor_uuid", "actor_email", "action", "target_type", "target_id", "ip", "user_agent", "metadata"})
+}
+
+func (e *csvAuditExport) write(event v1Dto.AuditEventDTO) error {
+	actor := ""
+	if event.ActorUUID != nil {
+		actor = event.ActorUUID.String()
+	}
+	return e.w.Write([]string{
+		strconv.FormatInt(event.AuditID, 10),
+		event.CreatedAt.Format(time.RFC3339),
+		actor,
+		csvSafe(derefString(event.ActorEmail)),
+		event.Action,
+		csvSafe(derefString(event.TargetType)),
+		csvSafe(derefString(event.TargetID)),
+		csvSafe(derefString(event.IP)),
+		csvSafe(derefString(event.UserAgent)),
+		csvSafe(string(event.Metadata)),
+	})
+}
+
+func (e *csvAuditExport) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func derefString(value *string) string {
+	if value == nil {
+		return ""
+	}
+	return *value
+}
@@ -2,12 +2,14 @@ package services
 
 import (
 	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
 	"chat-app/internal/repository"
 	"chat-app/internal/utils"
 	"chat-app/pkg/auth"
 	"chat-app/pkg/cache"
 
 	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
 	"golang.org/x/crypto/bcrypt"
 )
 
@@ -15,13 +17,17 @@ type authService struct {
 	userRepo     repository.UserRepository
 	TokenService auth.TokenService
 	cacheService cache.RedisCacheService
+	auditService AuditService
+	policy       EmailVerificationPolicy
 }
 
-func NewAuthService(userRepo repository.UserRepository, TokenService auth.TokenService, cacheService cache.RedisCacheService) AuthService {
+func NewAuthService(userRepo repository.UserRepository, TokenService auth.TokenService, cacheService cache.RedisCacheService, auditService AuditService, policy EmailVerificationPolicy) AuthService {
 	return &authService{
 		userRepo:     userRepo,
 		TokenService: TokenService,
 		cacheService: cacheService,
+		auditService: auditService,
+		policy:       policy,
 	}
 }
 
@@ -31,24 +37,58 @@ func (as *authService) Login(ctx *gin.Context, email, password string) (string,
 	// Tìm user theo email
 	user, err := as.userRepo.GetUserByEmail(context, email)
 	if err != nil {
+		as.recordLoginFailed(ctx, nil, email, "unknown_email")
 		return "", sqlc.User{}, utils.NewError("invalid credentials", utils.ErrorCodeUnauthorized)
 	}
 
 	// Kiểm tra mật khẩu
 	err = bcrypt.CompareHashAndPassword([]byte(user.UserPassword), []byte(password))
 	if err != nil {
+		as.recordLoginFailed(ctx, &user.UserUuid, email, "invalid_password")
 		return "", sqlc.User{}, utils.NewError("invalid credentials", utils.ErrorCodeUnauthorized)
 	}
 
+	// Chỉ kiểm tra sau khi đúng mật khẩu để không lộ trạng thái tài khoản
+	if err := accountStatusError(user); err != nil {
+		as.recordLoginFailed(ctx, &user.UserUuid, email, "account_"+v1Dto.AccountStatus(user))
+		return "", sqlc.User{}, err
+	}
+	if as.policy.RequireForLogin && user.UserEmailVerifiedAt == nil {
+		as.recordLoginFailed(ctx, &user.UserUuid, email, "email_not_verified")
+		return "", sqlc.User{}, utils.NewError("please verify your email address before logging in", utils.ErrorCodeForbidden)
+	}
+
 	// Tạo JWT token bằng cách gọi GenerateToken
 	tokenString, err := as.TokenService.GenerateToken(user.UserUuid, email, user.UserFullname, user.UserRole)
 	if err != nil {
 		return "", sqlc.User{}, err
 	}
 
+	as.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionLogin,
+		ActorUUID:  &user.UserUuid,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   user.UserUuid.String(),
+	})
 	return tokenString, user, nil
 }
+
+// recordLoginFailed ghi lần đăng nhập thất bại; userUUID = nil khi email không tồn tại.
+// Người thử đăng nhập chưa xác thực nên actor để trống, tài khoản bị nhắm tới chỉ là đối tượng.
+func (as *authService) recordLoginFailed(ctx *gin.Context, userUUID *uuid.UUID, email, reason string) {
+	event := AuditEvent{
+		Action:   v1Dto.AuditActionLoginFailed,
+		Metadata: map[string]any{"email": email, "reason": reason},
+	}
+	if userUUID != nil {
+		event.TargetType = v1Dto.AuditTargetUser
+		event.TargetID = userUUID.String()
+	}
+	as.auditService.Record(ctx, event)
+}
+
 func (as *authService) Logout(ctx *gin.Context, tokenString string) error {
 	as.TokenService.ValidateJWTToken(tokenString)
+	as.auditService.Record(ctx, AuditEvent{Action: v1Dto.AuditActionLogout})
 	return nil
 }
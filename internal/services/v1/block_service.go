@@ -0,0 +1,150 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/utils"
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// blockersCacheTTL là thời gian giữ danh sách người chặn trong bộ nhớ.
+// Chặn/bỏ chặn qua service xóa cache ngay, TTL chỉ giới hạn dữ liệu cũ khi block thay đổi từ nơi khác (vd: xóa tài khoản)
+const blockersCacheTTL = 5 * time.Minute
+
+type blockersCacheEntry struct {
+	blockers  map[uuid.UUID]bool
+	expiresAt time.Time
+}
+
+// blockersCache lưu người đã chặn mỗi user, ListBlockers được gọi cho mọi tin nhắn broadcast qua websocket.
+// Dùng chung cho mọi blockService (module user chặn/bỏ chặn, module chat đọc khi broadcast).
+var blockersCache = struct {
+	sync.RWMutex
+	entries map[uuid.UUID]blockersCacheEntry
+}{entries: make(map[uuid.UUID]blockersCacheEntry)}
+
+type blockService struct {
+	blockRepo repository.BlockRepository
+	userRepo  repository.UserRepository
+}
+
+func NewBlockService(blockRepo repository.BlockRepository, userRepo repository.UserRepository) BlockService {
+	return &blockService{
+		blockRepo: blockRepo,
+		userRepo:  userRepo,
+	}
+}
+
+// BlockUser chặn blockedUUID, chặn lại người đã chặn không báo lỗi
+func (bs *blockService) BlockUser(ctx *gin.Context, blockerUUID, blockedUUID uuid.UUID) (v1Dto.BlockedUserDTO, error) {
+	context := ctx.Request.Context()
+
+	if blockerUUID == blockedUUID {
+		return v1Dto.BlockedUserDTO{}, utils.NewError("you cannot block yourself", utils.ErrorCodeBadRequest)
+	}
+
+	blocked, err := bs.userRepo.GetUserByUUID(context, blockedUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v1Dto.BlockedUserDTO{}, utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return v1Dto.BlockedUserDTO{}, utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	block, err := bs.blockRepo.CreateUserBlock(context, blockerUUID, blockedUUID)
+	if err != nil {
+		return v1Dto.BlockedUserDTO{}, utils.WrapError(err, "could not block user", utils.ErrorCodeInternalServer)
+	}
+	invalidateBlockers(blockedUUID)
+
+	return v1Dto.BlockedUserDTO{
+		User:      v1Dto.MapUserToProfileDTO(blocked),
+		BlockedAt: block.BlockCreatedAt,
+	}, nil
+}
+
+func (bs *blockService) UnblockUser(ctx *gin.Context, blockerUUID, blockedUUID uuid.UUID) error {
+	context := ctx.Request.Context()
+
+	deleted, err := bs.blockRepo.DeleteUserBlock(context, blockerUUID, blockedUUID)
+	if err != nil {
+		return utils.WrapError(err, "could not unblock user", utils.ErrorCodeInternalServer)
+	}
+	invalidateBlockers(blockedUUID)
+	if deleted == 0 {
+		return utils.NewError("user is not blocked", utils.ErrorCodeNotFound)
+	}
+	return nil
+}
+
+func (bs *blockService) ListBlockedUsers(ctx *gin.Context, blockerUUID uuid.UUID) ([]v1Dto.BlockedUserDTO, error) {
+	context := ctx.Request.Context()
+
+	rows, err := bs.blockRepo.ListUserBlocks(context, blockerUUID)
+	if err != nil {
+		return nil, utils.WrapError(err, "could not get blocked users", utils.ErrorCodeInternalServer)
+	}
+
+	result := make([]v1Dto.BlockedUserDTO, 0, len(rows))
+	for _, row := range rows {
+		result = append(result, v1Dto.BlockedUserDTO{
+			User: v1Dto.MapUserToProfileDTO(sqlc.User{
+				UserUuid:      row.UserUuid,
+				UserFullname:  row.UserFullname,
+				UserAvatarUrl: row.UserAvatarUrl,
+				UserBio:       row.UserBio,
+				UserTimezone:  row.UserTimezone,
+				UserLocale:    row.UserLocale,
+			}),
+			BlockedAt: row.BlockCreatedAt,
+		})
+	}
+	return result, nil
+}
+
+// ListBlockers trả về những người đã chặn userUUID, dùng để lọc người nhận tin nhắn realtime.
+// Kết quả được cache trong bộ nhớ, không được sửa map trả về.
+func (bs *blockService) ListBlockers(ctx context.Context, userUUID uuid.UUID) (map[uuid.UUID]bool, error) {
+	blockersCache.RLock()
+	entry, ok := blockersCache.entries[userUUID]
+	blockersCache.RUnlock()
+	if ok && time.Now().Before(entry.expiresAt) {
+		return entry.blockers, nil
+	}
+
+	blockers, err := loadUserSet(bs.blockRepo.ListBlockerIDs(ctx, userUUID))
+	if err != nil {
+		return nil, err
+	}
+
+	blockersCache.Lock()
+	blockersCache.entries[userUUID] = blockersCacheEntry{blockers: blockers, expiresAt: time.Now().Add(blockersCacheTTL)}
+	blockersCache.Unlock()
+	return blockers, nil
+}
+
+func invalidateBlockers(userUUID uuid.UUID) {
+	blockersCache.Lock()
+	delete(blockersCache.entries, userUUID)
+	blockersCache.Unlock()
+}
+
+// loadUserSet chuyển kết quả truy vấn danh sách user thành set
+func loadUserSet(userUUIDs []uuid.UUID, err error) (map[uuid.UUID]bool, error) {
+	if err != nil {
+		return nil, err
+	}
+	set := make(map[uuid.UUID]bool, len(userUUIDs))
+	for _, userUUID := range userUUIDs {
+		set[userUUID] = true
+	}
+	return set, nil
+}
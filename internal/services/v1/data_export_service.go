@@ -0,0 +1,642 @@
+package services
+
+import (
+	"archive/zip"
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/utils"
+	"chat-app/pkg/storage"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+const (
+	dataExportPageSize     = 500 // số tin nhắn đọc mỗi lượt khi ghi messages.json
+	dataExportListLimit    = 20
+	dataExportCleanupBatch = 100
+	dataExportTimeout      = 30 * time.Minute
+)
+
+// DataExportCallback được gọi khi job xuất dữ liệu kết thúc (thành công hoặc lỗi),
+// recipientUUID là người đã yêu cầu (user hoặc admin)
+type DataExportCallback func(recipientUUID uuid.UUID, export v1Dto.DataExportDTO)
+
+// exportProfile là nội dung profile.json, không có mật khẩu
+type exportProfile struct {
+	UUID            uuid.UUID  `json:"uuid"`
+	Email           string     `json:"email"`
+	FullName        string     `json:"full_name"`
+	Role            string     `json:"role"`
+	Status          string     `json:"status"`
+	AvatarURL       *string    `json:"avatar_url"`
+	Bio             *string    `json:"bio"`
+	Timezone        string     `json:"timezone"`
+	Locale          string     `json:"locale"`
+	Discoverable    bool       `json:"discoverable"`
+	DigestFrequency string     `json:"digest_frequency"`
+	EmailVerifiedAt *time.Time `json:"email_verified_at"`
+	LastSeenAt      *time.Time `json:"last_seen_at"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// exportMembership là một phần tử của memberships.json
+type exportMembership struct {
+	RoomID            int64      `json:"room_id"`
+	RoomCode          string     `json:"room_code"`
+	RoomName          *string    `json:"room_name"`
+	IsDirectChat      bool       `json:"is_direct_chat"`
+	Role              string     `json:"role"`
+	JoinedAt          time.Time  `json:"joined_at"`
+	NotificationLevel string     `json:"notification_level"`
+	MutedUntil        *time.Time `json:"muted_until"`
+}
+
+// exportMessage là một phần tử của messages.json
+type exportMessage struct {
+	MessageID   int64              `json:"message_id"`
+	RoomID      int64              `json:"room_id"`
+	Content     string             `json:"content"`
+	CreatedAt   time.Time          `json:"created_at"`
+	Attachments []exportAttachment `json:"attachments"`
+}
+
+// exportAttachment: Path là đường dẫn của file trong thư mục attachments/ của ZIP
+type exportAttachment struct {
+	AttachmentID int64  `json:"attachment_id"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    int64  `json:"size_bytes"`
+	Path         string `json:"path"`
+
+	storageKey string
+}
+
+type dataExportService struct {
+	exportRepo     repository.DataExportRepository
+	userRepo       repository.UserRepository
+	roomRepo       repository.RoomRepository
+	messageRepo    repository.MessageRepository
+	attachmentRepo repository.AttachmentRepository
+	storage        storage.Storage
+	urlSigner      *storage.URLSigner
+	auditService   AuditService
+	retention      time.Duration
+
+	jobQueue chan int64
+
+	// Callback khi job kết thúc (vd: gửi sự kiện websocket)
+	finishedCallback DataExportCallback
+}
+
+func NewDataExportService(exportRepo repository.DataExportRepository, userRepo repository.UserRepository, roomRepo repository.RoomRepository, messageRepo repository.MessageRepository, attachmentRepo repository.AttachmentRepository, store storage.Storage, urlSigner *storage.URLSigner, auditService AuditService, retention time.Duration) DataExportService {
+	return &dataExportService{
+		exportRepo:     exportRepo,
+		userRepo:       userRepo,
+		roomRepo:       roomRepo,
+		messageRepo:    messageRepo,
+		attachmentRepo: attachmentRepo,
+		storage:        store,
+		urlSigner:      urlSigner,
+		auditService:   auditService,
+		retention:      retention,
+		jobQueue:       make(chan int64, 100),
+	}
+}
+
+// SetFinishedCallback đăng ký hàm được gọi khi job xuất dữ liệu kết thúc
+func (ds *dataExportService) SetFinishedCallback(callback DataExportCallback) {
+	ds.finishedCallback = callback
+}
+
+// RequestExport tạo job xuất dữ liệu cho user. Nếu user đã có job đang chờ/đang chạy thì trả về job đó.
+// requestedBy khác nil khi admin yêu cầu thay cho user.
+func (ds *dataExportService) RequestExport(ctx *gin.Context, userUUID uuid.UUID, requestedBy *uuid.UUID) (v1Dto.DataExportDTO, error) {
+	context := ctx.Request.Context()
+
+	user, err := ds.userRepo.GetUserByUUID(context, userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v1Dto.DataExportDTO{}, utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return v1Dto.DataExportDTO{}, utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+	if user.UserDeletedAt != nil {
+		return v1Dto.DataExportDTO{}, utils.NewError("account has been deleted", utils.ErrorCodeBadRequest)
+	}
+
+	active, err := ds.exportRepo.GetActiveDataExport(context, userUUID)
+	if err == nil {
+		return ds.toDTO(active), nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return v1Dto.DataExportDTO{}, utils.WrapError(err, "could not check data exports", utils.ErrorCodeInternalServer)
+	}
+
+	export, err := ds.exportRepo.CreateDataExport(context, userUUID, requestedBy)
+	if err != nil {
+		var pgErr *pgconn.PgError
+		if errors.As(err, &pgErr) && pgErr.Code == "23505" {
+			return v1Dto.DataExportDTO{}, utils.NewError("a data export is already in progress", utils.ErrorCodeConflict)
+		}
+		return v1Dto.DataExportDTO{}, utils.WrapError(err, "could not create data export", utils.ErrorCodeInternalServer)
+	}
+
+	select {
+	case ds.jobQueue <- export.ExportID:
+	default:
+		// Hàng đợi đầy, không để job nằm ở trạng thái pending
+		if _, err := ds.exportRepo.FailDataExport(context, export.ExportID, "export queue is full"); err != nil {
+			log.Printf("❌ Error failing data export %d: %v", export.ExportID, err)
+		}
+		return v1Dto.DataExportDTO{}, utils.NewError("too many exports in progress, please try again later", utils.ErrorCodeTooManyRequests)
+	}
+
+	// Admin xuất dữ liệu của người khác
+	if requestedBy != nil {
+		ds.auditService.Record(ctx, AuditEvent{
+			Action:     v1Dto.AuditActionUserExportRequested,
+			ActorUUID:  requestedBy,
+			TargetType: v1Dto.AuditTargetUser,
+			TargetID:   userUUID.String(),
+			Metadata:   map[string]any{"export_id": export.ExportID},
+		})
+	}
+
+	return ds.toDTO(export), nil
+}
+
+// GetExport trả về trạng thái job (kèm link tải nếu đã xong), user chỉ xem được job của mình
+func (ds *dataExportService) GetExport(ctx *gin.Context, exportID int64, userUUID uuid.UUID) (v1Dto.DataExportDTO, error) {
+	export, err := ds.getExport(ctx.Request.Context(), exportID)
+	if err != nil {
+		return v1Dto.DataExportDTO{}, err
+	}
+	if export.UserUuid != userUUID {
+		return v1Dto.DataExportDTO{}, utils.NewError("data export not found", utils.ErrorCodeNotFound)
+	}
+	return ds.toDTO(export), nil
+}
+
+// GetExportByID dùng cho admin, xem được job của mọi user
+func (ds *dataExportService) GetExportByID(ctx *gin.Context, exportID int64) (v1Dto.DataExportDTO, error) {
+	export, err := ds.getExport(ctx.Request.Context(), exportID)
+	if err != nil {
+		return v1Dto.DataExportDTO{}, err
+	}
+	return ds.toDTO(export), nil
+}
+
+func (ds *dataExportService) ListExports(ctx *gin.Context, userUUID uuid.UUID) ([]v1Dto.DataExportDTO, error) {
+	exports, err := ds.exportRepo.ListUserDataExports(ctx.Request.Context(), userUUID, dataExportListLimit)
+	if err != nil {
+		return nil, utils.WrapError(err, "could not list data exports", utils.ErrorCodeInternalServer)
+	}
+
+	result := make([]v1Dto.DataExportDTO, 0, len(exports))
+	for _, export := range exports {
+		result = append(result, ds.toDTO(export))
+	}
+	return result, nil
+}
+
+// OpenSignedExport mở file ZIP khi URL có chữ ký hợp lệ và file chưa hết hạn, caller phải Close reader
+func (ds *dataExportService) OpenSignedExport(ctx *gin.Context, exportID int64, expires int64, signature string) (sqlc.DataExport, io.ReadCloser, error) {
+	context := ctx.Request.Context()
+
+	if !ds.urlSigner.Verify(dataExportResource(exportID), expires, signature) {
+		return sqlc.DataExport{}, nil, utils.NewError("download link is invalid or expired", utils.ErrorCodeForbidden)
+	}
+
+	export, err := ds.getExport(context, exportID)
+	if err != nil {
+		return sqlc.DataExport{}, nil, err
+	}
+	if !isDownloadable(export) {
+		return sqlc.DataExport{}, nil, utils.NewError("data export has expired", utils.ErrorCodeNotFound)
+	}
+
+	reader, err := ds.storage.Get(context, *export.StorageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			return sqlc.DataExport{}, nil, utils.NewError("data export has expired", utils.ErrorCodeNotFound)
+		}
+		return sqlc.DataExport{}, nil, utils.WrapError(err, "could not read data export", utils.ErrorCodeInternalServer)
+	}
+
+	return export, reader, nil
+}
+
+// Start chạy worker tạo file ZIP và job xóa file hết hạn, các job dở dang từ lần chạy trước được xếp lại hàng đợi
+func (ds *dataExportService) Start(numWorkers int, cleanupInterval time.Duration) {
+	for i := 0; i < numWorkers; i++ {
+		go func() {
+			for exportID := range ds.jobQueue {
+				ds.process(exportID)
+			}
+		}()
+	}
+
+	go func() {
+		exports, err := ds.exportRepo.ResumeDataExports(context.Background())
+		if err != nil {
+			log.Printf("❌ Error resuming data exports: %v", err)
+			return
+		}
+		for _, export := range exports {
+			ds.jobQueue <- export.ExportID
+		}
+		if len(exports) > 0 {
+			log.Printf("📦 Resumed %d data export(s)", len(exports))
+		}
+	}()
+
+	go func() {
+		ticker := time.NewTicker(cleanupInterval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ds.cleanup()
+		}
+	}()
+
+	log.Printf("📦 Data export workers started (%d workers, cleanup every %s)", numWorkers, cleanupInterval)
+}
+
+// process tạo file ZIP cho một job và đẩy lên storage
+func (ds *dataExportService) process(exportID int64) {
+	ctx, cancel := context.WithTimeout(context.Background(), dataExportTimeout)
+	defer cancel()
+
+	export, err := ds.exportRepo.StartDataExport(ctx, exportID)
+	if err != nil {
+		if !errors.Is(err, pgx.ErrNoRows) {
+			log.Printf("❌ Error starting data export %d: %v", exportID, err)
+		}
+		return
+	}
+
+	storageKey, size, err := ds.build(ctx, export)
+	if err != nil {
+		log.Printf("❌ Error building data export %d: %v", exportID, err)
+		ds.fail(export, "could not build export")
+		return
+	}
+
+	expiresAt := time.Now().Add(ds.retention)
+	export, err = ds.exportRepo.CompleteDataExport(ctx, sqlc.CompleteDataExportParams{
+		StorageKey: &storageKey,
+		SizeBytes:  &size,
+		ExpiresAt:  &expiresAt,
+		ExportID:   exportID,
+	})
+	if err != nil {
+		log.Printf("❌ Error completing data export %d: %v", exportID, err)
+		ds.deleteObject(storageKey)
+		return
+	}
+
+	log.Printf("📦 Data export %d completed (%d bytes)", exportID, size)
+	ds.notifyFinished(export)
+}
+
+// build ghi file ZIP ra file tạm rồi upload, trả về storage key và kích thước
+func (ds *dataExportService) build(ctx context.Context, export sqlc.DataExport) (string, int64, error) {
+	user, err := ds.userRepo.GetUserByUUID(ctx, export.UserUuid)
+	if err != nil {
+		return "", 0, err
+	}
+	if user.UserDeletedAt != nil {
+		return "", 0, errors.New("account has been deleted")
+	}
+
+	tmp, err := os.CreateTemp("", "data-export-*.zip")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	zw := zip.NewWriter(tmp)
+	if err := ds.writeArchive(ctx, zw, user); err != nil {
+		return "", 0, err
+	}
+	if err := zw.Close(); err != nil {
+		return "", 0, err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return "", 0, err
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return "", 0, err
+	}
+
+	storageKey := fmt.Sprintf("exports/%s/%d.zip", user.UserUuid, export.ExportID)
+	if err := ds.storage.Put(ctx, storageKey, tmp, size, "application/zip"); err != nil {
+		return "", 0, err
+	}
+	return storageKey, size, nil
+}
+
+// writeArchive ghi profile.json, memberships.json, messages.json và thư mục attachments/
+func (ds *dataExportService) writeArchive(ctx context.Context, zw *zip.Writer, user sqlc.User) error {
+	if err := writeZipJSON(zw, "profile.json", exportProfile{
+		UUID:            user.UserUuid,
+		Email:           user.UserEmail,
+		FullName:        user.UserFullname,
+		Role:            user.UserRole,
+		Status:          v1Dto.AccountStatus(user),
+		AvatarURL:       user.UserAvatarUrl,
+		Bio:             user.UserBio,
+		Timezone:        user.UserTimezone,
+		Locale:          user.UserLocale,
+		Discoverable:    user.UserDiscoverable,
+		DigestFrequency: user.UserDigestFrequency,
+		EmailVerifiedAt: user.UserEmailVerifiedAt,
+		LastSeenAt:      user.UserLastSeenAt,
+		CreatedAt:       user.UserCreatedAt,
+		UpdatedAt:       user.UserUpdatedAt,
+	}); err != nil {
+		return err
+	}
+
+	memberships, err := ds.roomRepo.ListUserMemberships(ctx, user.UserUuid)
+	if err != nil {
+		return err
+	}
+	rooms := make([]exportMembership, 0, len(memberships))
+	for _, m := range memberships {
+		rooms = append(rooms, exportMembership{
+			RoomID:            m.RoomID,
+			RoomCode:          m.RoomCode,
+			RoomName:          m.RoomName,
+			IsDirectChat:      m.RoomIsDirectChat,
+			Role:              m.MemberRole,
+			JoinedAt:          m.RoomMemberCreatedAt,
+			NotificationLevel: m.MemberNotificationLevel,
+			MutedUntil:        m.MemberMutedUntil,
+		})
+	}
+	if err := writeZipJSON(zw, "memberships.json", rooms); err != nil {
+		return err
+	}
+
+	attachments, err := ds.writeMessages(ctx, zw, user.UserUuid)
+	if err != nil {
+		return err
+	}
+
+	// Chỉ mở được một entry mỗi lúc nên file đính kèm được ghi sau messages.json
+	for _, attachment := range attachments {
+		if err := ds.writeAttachment(ctx, zw, attachment); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// writeMessages ghi messages.json theo từng trang để không giữ toàn bộ tin nhắn trong bộ nhớ,
+// trả về danh sách file đính kèm cần ghi vào ZIP
+func (ds *dataExportService) writeMessages(ctx context.Context, zw *zip.Writer, userUUID uuid.UUID) ([]exportAttachment, error) {
+	w, err := zw.Create("messages.json")
+	if err != nil {
+		return nil, err
+	}
+	if _, err := io.WriteString(w, "["); err != nil {
+		return nil, err
+	}
+
+	var files []exportAttachment
+	enc := json.NewEncoder(w)
+	first := true
+	afterID := int64(0)
+	for {
+		messages, err := ds.messageRepo.ListUserMessages(ctx, sqlc.ListUserMessagesParams{
+			UserUuid: userUUID,
+			AfterID:  afterID,
+			Limit:    dataExportPageSize,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		messageIDs := make([]int64, len(messages))
+		for i, message := range messages {
+			messageIDs[i] = message.MessageID
+		}
+		byMessage := make(map[int64][]exportAttachment)
+		if len(messageIDs) > 0 {
+			attachments, err := ds.attachmentRepo.ListAttachmentsByMessageIDs(ctx, messageIDs)
+			if err != nil {
+				return nil, err
+			}
+			for _, a := range attachments {
+				file := exportAttachment{
+					AttachmentID: a.AttachmentID,
+					FileName:     a.FileName,
+					ContentType:  a.ContentType,
+					SizeBytes:    a.SizeBytes,
+					Path:         fmt.Sprintf("attachments/%d_%s", a.AttachmentID, a.FileName),
+					storageKey:   a.StorageKey,
+				}
+				byMessage[a.MessageID] = append(byMessage[a.MessageID], file)
+				files = append(files, file)
+			}
+		}
+
+		for _, message := range messages {
+			if !first {
+				if _, err := io.WriteString(w, ","); err != nil {
+					return nil, err
+				}
+			}
+			first = false
+
+			item := exportMessage{
+				MessageID:   message.MessageID,
+				RoomID:      message.RoomID,
+				Content:     message.Content,
+				CreatedAt:   message.MessageCreatedAt,
+				Attachments: byMessage[message.MessageID],
+			}
+			if item.Attachments == nil {
+				item.Attachments = []exportAttachment{}
+			}
+			if err := enc.Encode(item); err != nil {
+				return nil, err
+			}
+		}
+
+		if len(messages) < dataExportPageSize {
+			break
+		}
+		afterID = messages[len(messages)-1].MessageID
+	}
+
+	if _, err := io.WriteString(w, "]\n"); err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// writeAttachment chép nội dung file từ storage vào ZIP, file đã mất khỏi storage thì bỏ qua
+func (ds *dataExportService) writeAttachment(ctx context.Context, zw *zip.Writer, attachment exportAttachment) error {
+	reader, err := ds.storage.Get(ctx, attachment.storageKey)
+	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			log.Printf("⚠️ Attachment %d missing from storage, skipped in data export", attachment.AttachmentID)
+			return nil
+		}
+		return err
+	}
+	defer reader.Close()
+
+	w, err := zw.CreateHeader(&zip.FileHeader{
+		Name:     attachment.Path,
+		Method:   zip.Store, // ảnh, video, zip đã được nén sẵn
+		Modified: time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	_, err = io.Copy(w, reader)
+	return err
+}
+
+// cleanup xóa file ZIP đã hết hạn khỏi storage
+func (ds *dataExportService) cleanup() {
+	ctx := context.Background()
+
+	for {
+		exports, err := ds.exportRepo.ListExpiredDataExports(ctx, dataExportCleanupBatch)
+		if err != nil {
+			log.Printf("❌ Error loading expired data exports: %v", err)
+			return
+		}
+
+		for _, export := range exports {
+			if export.StorageKey != nil {
+				if err := ds.storage.Delete(ctx, *export.StorageKey); err != nil {
+					log.Printf("❌ Error deleting data export %d: %v", export.ExportID, err)
+					return
+				}
+			}
+			if err := ds.exportRepo.ExpireDataExport(ctx, export.ExportID); err != nil {
+				log.Printf("❌ Error expiring data export %d: %v", export.ExportID, err)
+				return
+			}
+		}
+
+		if len(exports) < dataExportCleanupBatch {
+			return
+		}
+	}
+}
+
+func (ds *dataExportService) fail(export sqlc.DataExport, reason string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	failed, err := ds.exportRepo.FailDataExport(ctx, export.ExportID, reason)
+	if err != nil {
+		log.Printf("❌ Error failing data export %d: %v", export.ExportID, err)
+		return
+	}
+	ds.notifyFinished(failed)
+}
+
+func (ds *dataExportService) notifyFinished(export sqlc.DataExport) {
+	if ds.finishedCallback == nil {
+		return
+	}
+	recipient := export.UserUuid
+	if export.RequestedByUuid != nil {
+		recipient = *export.RequestedByUuid
+	}
+	ds.finishedCallback(recipient, ds.toDTO(export))
+}
+
+// deleteObject dọn file ZIP khi không cập nhật được trạng thái job
+func (ds *dataExportService) deleteObject(storageKey string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	if err := ds.storage.Delete(ctx, storageKey); err != nil {
+		log.Printf("❌ Error deleting orphaned object %s: %v", storageKey, err)
+	}
+}
+
+func (ds *dataExportService) getExport(ctx context.Context, exportID int64) (sqlc.DataExport, error) {
+	export, err := ds.exportRepo.GetDataExport(ctx, exportID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.DataExport{}, utils.NewError("data export not found", utils.ErrorCodeNotFound)
+		}
+		return sqlc.DataExport{}, utils.WrapError(err, "could not get data export", utils.ErrorCodeInternalServer)
+	}
+	return export, nil
+}
+
+// toDTO tạo DTO, job đã xong và chưa hết hạn thì kèm link tải có chữ ký
+func (ds *dataExportService) toDTO(export sqlc.DataExport) v1Dto.DataExportDTO {
+	dto := v1Dto.DataExportDTO{
+		ExportID:    export.ExportID,
+		UserUUID:    export.UserUuid,
+		RequestedBy: export.RequestedByUuid,
+		Status:      export.ExportStatus,
+		SizeBytes:   export.SizeBytes,
+		Error:       export.ExportError,
+		CreatedAt:   export.ExportCreatedAt,
+		StartedAt:   export.ExportStartedAt,
+		CompletedAt: export.ExportCompletedAt,
+		ExpiresAt:   export.ExportExpiresAt,
+	}
+
+	if isDownloadable(export) {
+		expires, signature := ds.urlSigner.Sign(dataExportResource(export.ExportID))
+		downloadURL := fmt.Sprintf("/api/v1/exports/%d/download?expires=%d&signature=%s", export.ExportID, expires, signature)
+		// Link không sống lâu hơn chính file ZIP
+		urlExpiresAt := time.Unix(expires, 0).UTC()
+		if export.ExportExpiresAt.Before(urlExpiresAt) {
+			urlExpiresAt = export.ExportExpiresAt.UTC()
+		}
+		dto.DownloadURL = &downloadURL
+		dto.DownloadURLExpiresAt = &urlExpiresAt
+	}
+
+	return dto
+}
+
+func isDownloadable(export sqlc.DataExport) bool {
+	return export.ExportStatus == v1Dto.DataExportStatusCompleted &&
+		export.StorageKey != nil &&
+		export.ExportExpiresAt != nil &&
+		export.ExportExpiresAt.After(time.Now())
+}
+
+func dataExportResource(exportID int64) string {
+	return fmt.Sprintf("exports/%d", exportID)
+}
+
+func writeZipJSON(zw *zip.Writer, name string, value any) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(value)
+}
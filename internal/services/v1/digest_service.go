@@ -0,0 +1,317 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/templates"
+	"chat-app/internal/utils"
+	"chat-app/pkg/mailer"
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Tần suất email digest (khớp với CHECK của user_digest_frequency)
+const (
+	DigestFrequencyNever  = "never"
+	DigestFrequencyHourly = "hourly"
+	DigestFrequencyDaily  = "daily"
+	DigestFrequencyWeekly = "weekly"
+)
+
+const (
+	digestBatchSize     = 100 // số user xử lý mỗi lượt truy vấn
+	digestItemLimit     = 20  // số mention/tin nhắn riêng tối đa mỗi mục trong email
+	digestSnippetLength = 300
+	digestSendTimeout   = 30 * time.Second
+)
+
+var digestFrequencyText = map[string]string{
+	DigestFrequencyHourly: "at most once an hour",
+	DigestFrequencyDaily:  "at most once a day",
+	DigestFrequencyWeekly: "at most once a week",
+}
+
+// digestItem là một dòng trong email digest
+type digestItem struct {
+	RoomName  string
+	Sender    string
+	Content   string
+	URL       string
+	CreatedAt time.Time
+}
+
+// digestEmailData là dữ liệu cho template email/digest
+type digestEmailData struct {
+	Name               string
+	Mentions           []digestItem
+	DirectMessages     []digestItem
+	MoreMentions       bool
+	MoreDirectMessages bool
+	Frequency          string
+	AppURL             string
+	SettingsURL        string
+}
+
+type digestService struct {
+	digestRepo repository.DigestRepository
+	userRepo   repository.UserRepository
+	mailer     mailer.Mailer
+
+	baseURL      string
+	offlineAfter time.Duration
+
+	// User đang có kết nối websocket không nhận digest
+	presenceCallback PresenceCheckFunc
+}
+
+func NewDigestService(digestRepo repository.DigestRepository, userRepo repository.UserRepository, mailer mailer.Mailer, baseURL string, offlineAfter time.Duration) DigestService {
+	return &digestService{
+		digestRepo:   digestRepo,
+		userRepo:     userRepo,
+		mailer:       mailer,
+		baseURL:      baseURL,
+		offlineAfter: offlineAfter,
+	}
+}
+
+// SetPresenceCallback đăng ký hàm kiểm tra user online
+func (ds *digestService) SetPresenceCallback(callback PresenceCheckFunc) {
+	ds.presenceCallback = callback
+}
+
+func (ds *digestService) GetSettings(ctx *gin.Context, userUUID uuid.UUID) (v1Dto.DigestSettingsDTO, error) {
+	context := ctx.Request.Context()
+
+	user, err := ds.userRepo.GetUserByUUID(context, userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v1Dto.DigestSettingsDTO{}, utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return v1Dto.DigestSettingsDTO{}, utils.WrapError(err, "could not get digest settings", utils.ErrorCodeInternalServer)
+	}
+
+	return toDigestSettingsDTO(user), nil
+}
+
+func (ds *digestService) UpdateSettings(ctx *gin.Context, userUUID uuid.UUID, input v1Dto.DigestSettingsInput) (v1Dto.DigestSettingsDTO, error) {
+	context := ctx.Request.Context()
+
+	user, err := ds.digestRepo.UpdateUserDigestFrequency(context, userUUID, input.Frequency)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v1Dto.DigestSettingsDTO{}, utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return v1Dto.DigestSettingsDTO{}, utils.WrapError(err, "could not update digest settings", utils.ErrorCodeInternalServer)
+	}
+
+	return toDigestSettingsDTO(user), nil
+}
+
+// TrackPresence lưu thời điểm user kết nối/ngắt kết nối websocket, dùng để biết user đã offline bao lâu
+func (ds *digestService) TrackPresence(userUUID uuid.UUID, online bool) {
+	if err := ds.userRepo.UpdateUserLastSeen(context.Background(), userUUID); err != nil {
+		log.Printf("❌ Error updating last seen of user %s: %v", userUUID, err)
+	}
+}
+
+// Start chạy job gửi digest định kỳ
+func (ds *digestService) Start(interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			ds.run()
+		}
+	}()
+	log.Printf("📧 Email digest job started (every %s)", interval)
+}
+
+// run quét các user đến hạn, mỗi user một email. User không có gì mới được bỏ qua và xét lại ở lượt sau.
+func (ds *digestService) run() {
+	ctx := context.Background()
+	offlineBefore := time.Now().Add(-ds.offlineAfter)
+
+	sent := 0
+	afterUUID := uuid.Nil
+	for {
+		users, err := ds.digestRepo.ListDigestRecipients(ctx, sqlc.ListDigestRecipientsParams{
+			OfflineBefore: offlineBefore,
+			AfterUuid:     afterUUID,
+			Limit:         digestBatchSize,
+		})
+		if err != nil {
+			log.Printf("❌ Error loading digest recipients: %v", err)
+			return
+		}
+
+		for _, user := range users {
+			if ds.sendDigest(ctx, user) {
+				sent++
+			}
+		}
+
+		if len(users) < digestBatchSize {
+			break
+		}
+		afterUUID = users[len(users)-1].UserUuid
+	}
+
+	if sent > 0 {
+		log.Printf("📧 Sent %d email digest(s)", sent)
+	}
+}
+
+// sendDigest gửi digest cho một user, trả về true nếu đã gửi
+func (ds *digestService) sendDigest(ctx context.Context, user sqlc.User) bool {
+	if ds.presenceCallback != nil && ds.presenceCallback(user.UserUuid) {
+		return false
+	}
+
+	// Chỉ lấy những gì mới từ lúc user offline và chưa có trong digest trước
+	since := user.UserCreatedAt
+	if user.UserLastSeenAt != nil && user.UserLastSeenAt.After(since) {
+		since = *user.UserLastSeenAt
+	}
+	if user.UserDigestLastSentAt != nil && user.UserDigestLastSentAt.After(since) {
+		since = *user.UserDigestLastSentAt
+	}
+
+	mentions, err := ds.digestRepo.ListDigestMentions(ctx, sqlc.ListDigestMentionsParams{
+		UserUuid: user.UserUuid,
+		Since:    since,
+		Limit:    digestItemLimit + 1,
+	})
+	if err != nil {
+		log.Printf("❌ Error loading digest mentions for user %s: %v", user.UserUuid, err)
+		return false
+	}
+	directMessages, err := ds.digestRepo.ListDigestDirectMessages(ctx, sqlc.ListDigestDirectMessagesParams{
+		UserUuid: user.UserUuid,
+		Since:    since,
+		Limit:    digestItemLimit + 1,
+	})
+	if err != nil {
+		log.Printf("❌ Error loading digest direct messages for user %s: %v", user.UserUuid, err)
+		return false
+	}
+
+	data := digestEmailData{
+		Name:        user.UserFullname,
+		Frequency:   digestFrequencyText[user.UserDigestFrequency],
+		AppURL:      ds.baseURL,
+		SettingsURL: ds.baseURL + "/settings/notifications",
+	}
+
+	if len(mentions) > digestItemLimit {
+		mentions = mentions[:digestItemLimit]
+		data.MoreMentions = true
+	}
+	mentioned := make(map[int64]bool, len(mentions))
+	for _, mention := range mentions {
+		mentioned[mention.MessageID] = true
+
+		roomName := "a direct message"
+		if mention.RoomName != nil && !mention.RoomIsDirectChat {
+			roomName = *mention.RoomName
+		}
+		data.Mentions = append(data.Mentions, digestItem{
+			RoomName:  roomName,
+			Sender:    mention.SenderFullname,
+			Content:   truncateRunes(mention.Content, digestSnippetLength),
+			URL:       ds.messageURL(mention.RoomID, mention.MessageID),
+			CreatedAt: mention.MessageCreatedAt,
+		})
+	}
+
+	if len(directMessages) > digestItemLimit {
+		directMessages = directMessages[:digestItemLimit]
+		data.MoreDirectMessages = true
+	}
+	for _, message := range directMessages {
+		// Tin nhắn riêng có nhắc user đã nằm trong mục mention
+		if mentioned[message.MessageID] {
+			continue
+		}
+		data.DirectMessages = append(data.DirectMessages, digestItem{
+			Sender:    message.SenderFullname,
+			Content:   truncateRunes(message.Content, digestSnippetLength),
+			URL:       ds.messageURL(message.RoomID, message.MessageID),
+			CreatedAt: message.MessageCreatedAt,
+		})
+	}
+
+	if len(data.Mentions) == 0 && len(data.DirectMessages) == 0 {
+		return false
+	}
+
+	text, html, err := templates.RenderEmail("digest", data)
+	if err != nil {
+		log.Printf("❌ Error rendering digest for user %s: %v", user.UserUuid, err)
+		return false
+	}
+
+	sendCtx, cancel := context.WithTimeout(ctx, digestSendTimeout)
+	defer cancel()
+
+	// Lỗi gửi mail không đánh dấu đã gửi, lượt sau sẽ thử lại
+	if err := ds.mailer.Send(sendCtx, mailer.Message{
+		To:      user.UserEmail,
+		Subject: digestSubject(data),
+		Text:    text,
+		HTML:    html,
+	}); err != nil {
+		log.Printf("❌ Error sending digest to user %s: %v", user.UserUuid, err)
+		return false
+	}
+
+	if err := ds.digestRepo.MarkDigestSent(ctx, user.UserUuid); err != nil {
+		log.Printf("❌ Error marking digest sent for user %s: %v", user.UserUuid, err)
+	}
+	return true
+}
+
+func (ds *digestService) messageURL(roomID, messageID int64) string {
+	return fmt.Sprintf("%s/rooms/%d?message=%d", ds.baseURL, roomID, messageID)
+}
+
+// digestSubject vd: "3 mentions and 1 direct message while you were away"
+func digestSubject(data digestEmailData) string {
+	count := func(items []digestItem, more bool, word string) string {
+		switch {
+		case more:
+			return fmt.Sprintf("%d+ %ss", len(items), word)
+		case len(items) == 1:
+			return "1 " + word
+		default:
+			return fmt.Sprintf("%d %ss", len(items), word)
+		}
+	}
+
+	mentions := count(data.Mentions, data.MoreMentions, "mention")
+	directMessages := count(data.DirectMessages, data.MoreDirectMessages, "direct message")
+	switch {
+	case len(data.Mentions) > 0 && len(data.DirectMessages) > 0:
+		return mentions + " and " + directMessages + " while you were away"
+	case len(data.Mentions) > 0:
+		return mentions + " while you were away"
+	default:
+		return directMessages + " while you were away"
+	}
+}
+
+func toDigestSettingsDTO(user sqlc.User) v1Dto.DigestSettingsDTO {
+	return v1Dto.DigestSettingsDTO{
+		Frequency:  user.UserDigestFrequency,
+		Enabled:    user.UserDigestFrequency != DigestFrequencyNever,
+		LastSentAt: user.UserDigestLastSentAt,
+	}
+}
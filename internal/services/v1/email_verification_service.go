@@ -0,0 +1,238 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	"chat-app/internal/repository"
+	"chat-app/internal/templates"
+	"chat-app/internal/utils"
+	"chat-app/pkg/mailer"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// EmailVerificationPolicy quyết định hành động nào yêu cầu email đã xác minh (zero value = không yêu cầu gì)
+type EmailVerificationPolicy struct {
+	RequireForLogin        bool
+	RequireForJoinRooms    bool
+	RequireForSendMessages bool
+}
+
+// EmailVerificationOptions cấu hình token và giới hạn gửi lại email xác minh
+type EmailVerificationOptions struct {
+	Secret         string
+	TTL            time.Duration
+	ResendInterval time.Duration
+	DailyLimit     int
+	BaseURL        string
+}
+
+// verificationEmailData là dữ liệu cho template email/verify_email
+type verificationEmailData struct {
+	Name      string
+	URL       string
+	ExpiresIn string
+}
+
+type emailVerificationService struct {
+	verificationRepo repository.EmailVerificationRepository
+	userRepo         repository.UserRepository
+	mailer           mailer.Mailer
+	options          EmailVerificationOptions
+}
+
+func NewEmailVerificationService(verificationRepo repository.EmailVerificationRepository, userRepo repository.UserRepository, mailer mailer.Mailer, options EmailVerificationOptions) EmailVerificationService {
+	return &emailVerificationService{
+		verificationRepo: verificationRepo,
+		userRepo:         userRepo,
+		mailer:           mailer,
+		options:          options,
+	}
+}
+
+// QueueVerification gửi email xác minh ở background (sau khi đăng ký), lỗi chỉ được log
+func (vs *emailVerificationService) QueueVerification(user sqlc.User) {
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+		defer cancel()
+
+		if err := vs.SendVerification(ctx, user); err != nil {
+			log.Printf("❌ Error sending verification email to user %s: %v", user.UserUuid, err)
+		}
+	}()
+}
+
+// SendVerification tạo token mới và gửi link xác minh, giới hạn theo ResendInterval và DailyLimit
+func (vs *emailVerificationService) SendVerification(ctx context.Context, user sqlc.User) error {
+	if user.UserEmailVerifiedAt != nil {
+		return nil
+	}
+
+	now := time.Now()
+	recent, err := vs.verificationRepo.CountEmailVerificationTokensSince(ctx, user.UserUuid, now.Add(-vs.options.ResendInterval))
+	if err != nil {
+		return utils.WrapError(err, "could not check verification emails", utils.ErrorCodeInternalServer)
+	}
+	if recent > 0 {
+		return utils.NewError("a verification email was sent recently, please wait before requesting another one", utils.ErrorCodeTooManyRequests)
+	}
+	daily, err := vs.verificationRepo.CountEmailVerificationTokensSince(ctx, user.UserUuid, now.Add(-24*time.Hour))
+	if err != nil {
+		return utils.WrapError(err, "could not check verification emails", utils.ErrorCodeInternalServer)
+	}
+	if daily >= int64(vs.options.DailyLimit) {
+		return utils.NewError("too many verification emails requested today", utils.ErrorCodeTooManyRequests)
+	}
+
+	token, err := vs.verificationRepo.CreateEmailVerificationToken(ctx, sqlc.CreateEmailVerificationTokenParams{
+		UserUuid:       user.UserUuid,
+		TokenEmail:     user.UserEmail,
+		TokenExpiresAt: now.Add(vs.options.TTL),
+	})
+	if err != nil {
+		return utils.WrapError(err, "could not create verification token", utils.ErrorCodeInternalServer)
+	}
+
+	text, html, err := templates.RenderEmail("verify_email", verificationEmailData{
+		Name:      user.UserFullname,
+		URL:       vs.options.BaseURL + "/verify-email?token=" + url.QueryEscape(vs.signToken(token)),
+		ExpiresIn: humanizeDuration(vs.options.TTL),
+	})
+	if err != nil {
+		return utils.WrapError(err, "could not render verification email", utils.ErrorCodeInternalServer)
+	}
+
+	if err := vs.mailer.Send(ctx, mailer.Message{
+		To:      user.UserEmail,
+		Subject: "Verify your email address",
+		Text:    text,
+		HTML:    html,
+	}); err != nil {
+		return utils.WrapError(err, "could not send verification email", utils.ErrorCodeInternalServer)
+	}
+	return nil
+}
+
+// ResendVerification gửi lại email xác minh ở background. Luôn trả về thành công (trừ lỗi hệ thống)
+// để không lộ email nào đã đăng ký, kể cả khi email đã xác minh hoặc vượt giới hạn gửi (chỉ ghi log).
+func (vs *emailVerificationService) ResendVerification(ctx *gin.Context, email string) error {
+	context := ctx.Request.Context()
+
+	user, err := vs.userRepo.GetUserByEmail(context, utils.NormalizeString(email))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	vs.QueueVerification(user)
+	return nil
+}
+
+// VerifyEmail kiểm tra chữ ký, thời hạn của token, đánh dấu token đã dùng và email đã xác minh
+func (vs *emailVerificationService) VerifyEmail(ctx *gin.Context, tokenString string) (sqlc.User, error) {
+	context := ctx.Request.Context()
+	invalid := utils.NewError("invalid verification token", utils.ErrorCodeBadRequest)
+
+	tokenIDString, signature, found := strings.Cut(tokenString, ".")
+	if !found {
+		return sqlc.User{}, invalid
+	}
+	tokenID, err := uuid.Parse(tokenIDString)
+	if err != nil {
+		return sqlc.User{}, invalid
+	}
+
+	token, err := vs.verificationRepo.GetEmailVerificationToken(context, tokenID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, invalid
+		}
+		return sqlc.User{}, utils.WrapError(err, "could not get verification token", utils.ErrorCodeInternalServer)
+	}
+	if !hmac.Equal([]byte(vs.sign(token)), []byte(signature)) {
+		return sqlc.User{}, invalid
+	}
+	if token.TokenUsedAt != nil {
+		return sqlc.User{}, utils.NewError("verification token has already been used", utils.ErrorCodeBadRequest)
+	}
+	if time.Now().After(token.TokenExpiresAt) {
+		return sqlc.User{}, utils.NewError("verification token has expired", utils.ErrorCodeBadRequest)
+	}
+
+	// Token chỉ được đánh dấu đã dùng khi email được xác minh, request đồng thời với cùng token sẽ không cập nhật được
+	user, used, err := vs.verificationRepo.VerifyEmailWithToken(context, tokenID, token.UserUuid, token.TokenEmail)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			// User đã đổi email sau khi token được gửi
+			return sqlc.User{}, utils.NewError("verification token is no longer valid", utils.ErrorCodeBadRequest)
+		}
+		return sqlc.User{}, utils.WrapError(err, "could not verify email", utils.ErrorCodeInternalServer)
+	}
+	if !used {
+		return sqlc.User{}, utils.NewError("verification token has already been used", utils.ErrorCodeBadRequest)
+	}
+
+	return user, nil
+}
+
+// signToken trả về token gửi cho user: "<token_id>.<chữ ký>"
+func (vs *emailVerificationService) signToken(token sqlc.EmailVerificationToken) string {
+	return token.TokenID.String() + "." + vs.sign(token)
+}
+
+// sign ký token_id cùng user, email và thời hạn, token bị sửa trong DB cũng không dùng được
+func (vs *emailVerificationService) sign(token sqlc.EmailVerificationToken) string {
+	mac := hmac.New(sha256.New, []byte(vs.options.Secret))
+	mac.Write([]byte(strings.Join([]string{
+		"email-verification",
+		token.TokenID.String(),
+		token.UserUuid.String(),
+		token.TokenEmail,
+		strconv.FormatInt(token.TokenExpiresAt.Unix(), 10),
+	}, "\n")))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// ensureEmailVerified trả về FORBIDDEN nếu user chưa xác minh email
+func ensureEmailVerified(ctx context.Context, userRepo repository.UserRepository, userUUID uuid.UUID, action string) error {
+	user, err := userRepo.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+	if user.UserEmailVerifiedAt == nil {
+		return utils.NewError("please verify your email address before "+action, utils.ErrorCodeForbidden)
+	}
+	return nil
+}
+
+// humanizeDuration vd: 24h -> "24 hours", 30m -> "30 minutes"
+func humanizeDuration(d time.Duration) string {
+	if d >= time.Hour && d%time.Hour == 0 {
+		hours := int(d / time.Hour)
+		if hours == 1 {
+			return "1 hour"
+		}
+		return strconv.Itoa(hours) + " hours"
+	}
+	minutes := int(d / time.Minute)
+	if minutes == 1 {
+		return "1 minute"
+	}
+	return strconv.Itoa(minutes) + " minutes"
+}
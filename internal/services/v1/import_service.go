@@ -0,0 +1,359 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/utils"
+	"chat-app/pkg/chatimport"
+	"context"
+	"errors"
+	"log"
+	"mime/multipart"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const maxRoomNameLength = 255 // khớp với rooms.room_name VARCHAR(255)
+
+type importService struct {
+	importRepo   repository.ImportRepository
+	userRepo     repository.UserRepository
+	roomRepo     repository.RoomRepository
+	auditService AuditService
+	batchSize    int
+	running      sync.Mutex // mỗi lúc chỉ chạy một lần import
+}
+
+func NewImportService(importRepo repository.ImportRepository, userRepo repository.UserRepository, roomRepo repository.RoomRepository, auditService AuditService, batchSize int) ImportService {
+	if batchSize <= 0 {
+		batchSize = 5000
+	}
+	return &importService{
+		importRepo:   importRepo,
+		userRepo:     userRepo,
+		roomRepo:     roomRepo,
+		auditService: auditService,
+		batchSize:    batchSize,
+	}
+}
+
+// importRun giữ trạng thái của một lần import
+type importRun struct {
+	source    string
+	adminUUID uuid.UUID
+	archive   chatimport.Archive
+	users     map[string]uuid.UUID // id gốc -> user của app (ghép theo email)
+	unmapped  map[string]int       // id gốc -> số tin nhắn bị bỏ qua
+	result    v1Dto.ImportResultDTO
+}
+
+// ImportArchive đọc file ZIP export của Slack hoặc Discord, tạo phòng và thành viên rồi ghi tin nhắn bằng COPY
+// với thời gian gửi gốc. Phòng và tin nhắn được đánh dấu bằng id gốc nên chạy lại cùng file không tạo bản trùng.
+// Tin nhắn import không tạo mention, thông báo hay sự kiện websocket.
+func (is *importService) ImportArchive(ctx *gin.Context, adminUUID uuid.UUID, source string, file *multipart.FileHeader) (v1Dto.ImportResultDTO, error) {
+	context := ctx.Request.Context()
+
+	if !is.running.TryLock() {
+		return v1Dto.ImportResultDTO{}, utils.NewError("another import is already running", utils.ErrorCodeConflict)
+	}
+	defer is.running.Unlock()
+
+	src, err := file.Open()
+	if err != nil {
+		return v1Dto.ImportResultDTO{}, utils.WrapError(err, "could not read uploaded file", utils.ErrorCodeInternalServer)
+	}
+	defer src.Close()
+
+	archive, err := chatimport.Open(source, src, file.Size)
+	if err != nil {
+		return v1Dto.ImportResultDTO{}, importError(err)
+	}
+
+	run := &importRun{
+		source:    source,
+		adminUUID: adminUUID,
+		archive:   archive,
+		unmapped:  make(map[string]int),
+		result: v1Dto.ImportResultDTO{
+			Source:        source,
+			UnmappedUsers: []v1Dto.ImportUnmappedUserDTO{},
+		},
+	}
+
+	if run.users, err = is.mapUsers(context, archive.Users()); err != nil {
+		return v1Dto.ImportResultDTO{}, err
+	}
+
+	for _, channel := range archive.Channels() {
+		if err := is.importChannel(context, run, channel); err != nil {
+			return v1Dto.ImportResultDTO{}, importError(err)
+		}
+	}
+
+	run.result.UnmappedUsers = unmappedUsers(archive.Users(), run.unmapped)
+
+	is.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionImportCompleted,
+		ActorUUID:  &adminUUID,
+		TargetType: v1Dto.AuditTargetImport,
+		TargetID:   source,
+		Metadata: map[string]any{
+			"file_name":          file.Filename,
+			"size_bytes":         file.Size,
+			"rooms_created":      run.result.RoomsCreated,
+			"rooms_existing":     run.result.RoomsExisting,
+			"messages_imported":  run.result.MessagesImported,
+			"messages_skipped":   run.result.MessagesSkipped,
+			"messages_unmapped":  run.result.MessagesUnmapped,
+			"messages_truncated": run.result.MessagesTruncated,
+		},
+	})
+
+	log.Printf("📥 Imported %s archive: %d rooms created, %d messages imported, %d skipped, %d unmapped",
+		source, run.result.RoomsCreated, run.result.MessagesImported, run.result.MessagesSkipped, run.result.MessagesUnmapped)
+	return run.result, nil
+}
+
+// mapUsers ghép user trong file export với user của app có cùng email
+func (is *importService) mapUsers(ctx context.Context, users []chatimport.User) (map[string]uuid.UUID, error) {
+	mapped := make(map[string]uuid.UUID)
+	for _, u := range users {
+		email := utils.NormalizeString(u.Email)
+		if email == "" {
+			continue
+		}
+		user, err := is.userRepo.GetUserByEmail(ctx, email)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				continue
+			}
+			return nil, utils.WrapError(err, "could not map users by email", utils.ErrorCodeInternalServer)
+		}
+		mapped[u.ExternalID] = user.UserUuid
+	}
+	return mapped, nil
+}
+
+func (is *importService) importChannel(ctx context.Context, run *importRun, channel chatimport.Channel) error {
+	room, ok, err := is.ensureRoom(ctx, run, channel)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		// Không tạo phòng: vẫn đếm tin nhắn để báo cáo người gửi chưa ghép được
+		return run.archive.Messages(channel, func(m chatimport.Message) error {
+			run.unmapped[m.AuthorID]++
+			run.result.MessagesUnmapped++
+			return nil
+		})
+	}
+
+	// Thành viên đã thêm trong lần chạy này, tránh gọi DB lại cho mỗi tin nhắn
+	members := make(map[uuid.UUID]bool)
+	addMember := func(userUUID uuid.UUID) error {
+		if members[userUUID] {
+			return nil
+		}
+		if err := is.importRepo.AddImportedRoomMember(ctx, userUUID, room.RoomID, RoomRoleMember); err != nil {
+			return utils.WrapError(err, "could not add room member", utils.ErrorCodeInternalServer)
+		}
+		members[userUUID] = true
+		return nil
+	}
+	for _, id := range channel.MemberIDs {
+		if userUUID, ok := run.users[id]; ok {
+			if err := addMember(userUUID); err != nil {
+				return err
+			}
+		}
+	}
+
+	batch := make([]sqlc.CopyImportedMessagesParams, 0, is.batchSize)
+	flush := func() error {
+		if len(batch) == 0 {
+			return nil
+		}
+		if err := is.copyBatch(ctx, run, room.RoomID, batch); err != nil {
+			return err
+		}
+		batch = batch[:0]
+		return nil
+	}
+
+	err = run.archive.Messages(channel, func(m chatimport.Message) error {
+		userUUID, ok := run.users[m.AuthorID]
+		if !ok {
+			run.unmapped[m.AuthorID]++
+			run.result.MessagesUnmapped++
+			return nil
+		}
+		if err := addMember(userUUID); err != nil {
+			return err
+		}
+
+		content := strings.ReplaceAll(m.Text, "\x00", "") // Postgres không lưu được ký tự NUL
+		if runes := []rune(content); len(runes) > maxMessageContentLength {
+			content = string(runes[:maxMessageContentLength])
+			run.result.MessagesTruncated++
+		}
+
+		externalID := m.ExternalID
+		batch = append(batch, sqlc.CopyImportedMessagesParams{
+			RoomID:            room.RoomID,
+			UserUuid:          userUUID,
+			Content:           content,
+			MessageType:       "text",
+			MessageCreatedAt:  m.CreatedAt,
+			MessageExternalID: &externalID,
+		})
+		if len(batch) >= is.batchSize {
+			return flush()
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	return flush()
+}
+
+// ensureRoom trả về phòng đã import từ channel ở lần trước, hoặc tạo phòng mới.
+// Chủ phòng là người tạo kênh nếu ghép được, nếu không thì thành viên đầu tiên ghép được, cuối cùng là admin đang import.
+// Chat 1-1 không có ai ghép được thì bỏ qua (ok = false), không đưa admin vào cuộc trò chuyện riêng.
+func (is *importService) ensureRoom(ctx context.Context, run *importRun, channel chatimport.Channel) (sqlc.Room, bool, error) {
+	imported, err := is.importRepo.GetImportedRoom(ctx, run.source, channel.ExternalID)
+	if err == nil {
+		room, err := is.roomRepo.GetRoomByID(ctx, imported.RoomID)
+		if err != nil {
+			return sqlc.Room{}, false, utils.WrapError(err, "could not get imported room", utils.ErrorCodeInternalServer)
+		}
+		run.result.RoomsExisting++
+		return room, true, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return sqlc.Room{}, false, utils.WrapError(err, "could not get imported room", utils.ErrorCodeInternalServer)
+	}
+
+	owner, ok := run.users[channel.CreatorID]
+	if !ok {
+		for _, id := range channel.MemberIDs {
+			if owner, ok = run.users[id]; ok {
+				break
+			}
+		}
+	}
+	if !ok {
+		if channel.IsDirect {
+			return sqlc.Room{}, false, nil
+		}
+		owner = run.adminUUID
+	}
+
+	roomCode, err := generateRoomCode()
+	if err != nil {
+		return sqlc.Room{}, false, utils.WrapError(err, "could not generate room code", utils.ErrorCodeInternalServer)
+	}
+
+	var name *string
+	if !channel.IsDirect {
+		n := channel.Name
+		if runes := []rune(n); len(runes) > maxRoomNameLength {
+			n = string(runes[:maxRoomNameLength])
+		}
+		name = &n
+	}
+
+	room, err := is.importRepo.CreateImportedRoomWithOwner(ctx, sqlc.CreateRoomParams{
+		RoomCode:         roomCode,
+		RoomName:         name,
+		RoomIsDirectChat: channel.IsDirect,
+		RoomCreatedBy:    owner,
+	}, RoomRoleOwner, run.source, channel.ExternalID)
+	if err != nil {
+		return sqlc.Room{}, false, utils.WrapError(err, "could not create imported room", utils.ErrorCodeInternalServer)
+	}
+
+	run.result.RoomsCreated++
+	return room, true, nil
+}
+
+// copyBatch bỏ các tin nhắn đã import ở lần trước (hoặc trùng id trong cùng lô) rồi ghi phần còn lại bằng COPY
+func (is *importService) copyBatch(ctx context.Context, run *importRun, roomID int64, batch []sqlc.CopyImportedMessagesParams) error {
+	externalIDs := make([]string, len(batch))
+	for i, m := range batch {
+		externalIDs[i] = *m.MessageExternalID
+	}
+
+	existing, err := is.importRepo.ListExistingExternalMessageIDs(ctx, roomID, externalIDs)
+	if err != nil {
+		return utils.WrapError(err, "could not check imported messages", utils.ErrorCodeInternalServer)
+	}
+	seen := make(map[string]bool, len(batch))
+	for _, id := range existing {
+		seen[id] = true
+	}
+
+	rows := make([]sqlc.CopyImportedMessagesParams, 0, len(batch))
+	for _, m := range batch {
+		if seen[*m.MessageExternalID] {
+			run.result.MessagesSkipped++
+			continue
+		}
+		seen[*m.MessageExternalID] = true
+		rows = append(rows, m)
+	}
+	if len(rows) == 0 {
+		return nil
+	}
+
+	count, err := is.importRepo.CopyImportedMessages(ctx, rows)
+	if err != nil {
+		return utils.WrapError(err, "could not import messages", utils.ErrorCodeInternalServer)
+	}
+	run.result.MessagesImported += count
+	return nil
+}
+
+// unmappedUsers liệt kê người gửi không ghép được, nhiều tin nhắn bị bỏ qua nhất trước
+func unmappedUsers(users []chatimport.User, unmapped map[string]int) []v1Dto.ImportUnmappedUserDTO {
+	byID := make(map[string]chatimport.User, len(users))
+	for _, u := range users {
+		byID[u.ExternalID] = u
+	}
+
+	result := make([]v1Dto.ImportUnmappedUserDTO, 0, len(unmapped))
+	for id, count := range unmapped {
+		result = append(result, v1Dto.ImportUnmappedUserDTO{
+			ExternalID: id,
+			Name:       byID[id].Name,
+			Email:      byID[id].Email,
+			Messages:   count,
+		})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Messages != result[j].Messages {
+			return result[i].Messages > result[j].Messages
+		}
+		return result[i].ExternalID < result[j].ExternalID
+	})
+	return result
+}
+
+// importError đổi lỗi đọc file export thành lỗi 400, giữ nguyên lỗi đã được service xử lý
+func importError(err error) error {
+	var appErr *utils.AppError
+	switch {
+	case errors.As(err, &appErr):
+		return err
+	case errors.Is(err, chatimport.ErrInvalidArchive), errors.Is(err, chatimport.ErrUnsupportedSource):
+		return utils.WrapError(err, "invalid export archive", utils.ErrorCodeBadRequest)
+	default:
+		return utils.WrapError(err, "could not read export archive", utils.ErrorCodeInternalServer)
+	}
+}
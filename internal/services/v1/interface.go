@@ -4,6 +4,9 @@ import (
 	"chat-app/internal/db/sqlc"
 	v1Dto "chat-app/internal/dto/v1"
 	"context"
+	"io"
+	"mime/multipart"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
@@ -14,7 +17,10 @@ type UserService interface {
 	GetUserByUUID(ctx *gin.Context, userUUID string) (sqlc.User, error)
 	GetUserByUUIDWithContext(ctx context.Context, userUUID string) (sqlc.User, error)
 	GetAllUsers(ctx *gin.Context, limit, offset int32) ([]sqlc.User, error)
-	DeleteUser(ctx *gin.Context, userUUID string) error
+	UpdateProfile(ctx *gin.Context, userUUID uuid.UUID, input v1Dto.UpdateProfileInput) (sqlc.User, error)
+	ListSharedRoomIDs(ctx context.Context, userUUID uuid.UUID) ([]int64, error)
+	SearchDirectory(ctx *gin.Context, requesterUUID uuid.UUID, query v1Dto.UserDirectoryQuery) ([]v1Dto.UserProfileDTO, v1Dto.DirectoryPagination, error)
+	EnsureAccountActive(ctx context.Context, userUUID uuid.UUID) error
 }
 type AuthService interface {
 	Login(ctx *gin.Context, email, password string) (string, sqlc.User, error)
@@ -26,9 +32,15 @@ type RoomService interface {
 	JoinRoomByID(ctx *gin.Context, roomID int64, userUUID uuid.UUID) (sqlc.Room, error)
 	LeaveRoom(ctx *gin.Context, roomID int64, userUUID uuid.UUID) error
 	GetUserRooms(ctx *gin.Context, userUUID uuid.UUID) ([]sqlc.Room, error)
-	GetUserRoomsWithLastMessage(ctx *gin.Context, userUUID uuid.UUID) ([]sqlc.ListUserRoomsWithLastMessageRow, error)
+	GetUserRoomsWithLastMessage(ctx *gin.Context, userUUID uuid.UUID, includeArchived bool) ([]sqlc.ListUserRoomsWithLastMessageRow, error)
 	GetRoomMembers(ctx *gin.Context, roomID int64) ([]sqlc.User, error)
 	IsUserMemberOfRoom(ctx context.Context, userUUID uuid.UUID, roomID int64) (bool, error)
+	GetRoomDetails(ctx *gin.Context, roomID int64, userUUID uuid.UUID) (sqlc.Room, error)
+	UpdateRoomSettings(ctx *gin.Context, roomID int64, actorUUID uuid.UUID, input v1Dto.UpdateRoomInput) (sqlc.Room, []sqlc.Message, error)
+	TransferOwnership(ctx *gin.Context, roomID int64, ownerUUID, newOwnerUUID uuid.UUID) (sqlc.Message, error)
+	DeleteOwnedRoom(ctx *gin.Context, roomID int64, ownerUUID uuid.UUID) error
+	SetRoomArchived(ctx *gin.Context, roomID int64, ownerUUID uuid.UUID, archived bool) (sqlc.Room, sqlc.Message, error)
+	UpdateNotificationSettings(ctx *gin.Context, roomID int64, userUUID uuid.UUID, input v1Dto.UpdateNotificationSettingsInput) (sqlc.RoomMember, error)
 
 	// Admin methods
 	GetAllRooms(ctx *gin.Context, limit, offset int32) ([]sqlc.GetAllRoomsWithMemberCountRow, error)
@@ -40,5 +52,95 @@ type MessageService interface {
 	SaveMessage(ctx *gin.Context, roomID int64, userUUID uuid.UUID, content string) (sqlc.Message, error)
 	GetRoomMessages(ctx *gin.Context, roomID int64, limit, offset int32) ([]sqlc.Message, error)
 	GetRoomMessagesWithUsers(ctx *gin.Context, roomID int64, userUUID uuid.UUID, limit, offset int32) ([]v1Dto.MessageWithUser, error)
+	GetMessageContext(ctx *gin.Context, roomID, messageID int64, userUUID uuid.UUID, before, after int32) ([]v1Dto.MessageWithUser, v1Dto.MessageContextPagination, error)
+	GetRoomMessagesPage(ctx *gin.Context, roomID int64, userUUID uuid.UUID, page v1Dto.MessagePageQuery) ([]v1Dto.MessageWithUser, v1Dto.CursorPagination, error)
 	CreateMessage(ctx context.Context, params sqlc.CreateMessageParams) (sqlc.Message, error)
+	CreateMessageWithAttachment(ctx context.Context, params sqlc.CreateMessageParams, slowMode time.Duration, attachment sqlc.CreateAttachmentParams) (sqlc.Message, sqlc.MessageAttachment, error)
+	EnsureCanPost(ctx context.Context, roomID int64, userUUID uuid.UUID) (time.Duration, error)
+	SearchMessages(ctx *gin.Context, userUUID uuid.UUID, query v1Dto.SearchMessagesQuery) ([]v1Dto.MessageSearchResult, v1Dto.CursorPagination, error)
+	MarkMentionsRead(ctx *gin.Context, roomID int64, userUUID uuid.UUID) (int64, error)
+	SetMentionCallback(callback MentionCallback)
+	SetPresenceCallback(callback PresenceCheckFunc)
+	SetNewMessageCallback(callback MentionCallback)
+}
+
+type AttachmentService interface {
+	UploadAttachment(ctx *gin.Context, roomID int64, userUUID uuid.UUID, file *multipart.FileHeader, caption string) (sqlc.Message, v1Dto.AttachmentDTO, error)
+	GetAttachment(ctx *gin.Context, attachmentID int64, userUUID uuid.UUID) (v1Dto.AttachmentDTO, error)
+	OpenSignedAttachment(ctx *gin.Context, attachmentID int64, thumbnail bool, expires int64, signature string) (sqlc.MessageAttachment, io.ReadCloser, error)
+}
+
+type NotificationService interface {
+	NotifyMentions(ctx context.Context, message sqlc.Message, mentions []sqlc.MessageMention)
+	ListNotifications(ctx *gin.Context, userUUID uuid.UUID, query v1Dto.NotificationQuery) ([]v1Dto.NotificationDTO, v1Dto.CursorPagination, int64, error)
+	MarkRead(ctx *gin.Context, userUUID uuid.UUID, notificationID int64) (v1Dto.NotificationDTO, error)
+	MarkAllRead(ctx *gin.Context, userUUID uuid.UUID) (int64, error)
+	SetNotificationCallback(callback NotificationCallback)
+}
+
+type PushService interface {
+	RegisterDevice(ctx *gin.Context, userUUID uuid.UUID, input v1Dto.RegisterPushDeviceInput) (v1Dto.PushDeviceDTO, error)
+	ListDevices(ctx *gin.Context, userUUID uuid.UUID) ([]v1Dto.PushDeviceDTO, error)
+	DeleteDevice(ctx *gin.Context, userUUID uuid.UUID, deviceID int64) error
+	NotifyNewMessage(message sqlc.Message, mentions []sqlc.MessageMention)
+	SetPresenceCallback(callback PresenceCheckFunc)
+}
+
+type DigestService interface {
+	GetSettings(ctx *gin.Context, userUUID uuid.UUID) (v1Dto.DigestSettingsDTO, error)
+	UpdateSettings(ctx *gin.Context, userUUID uuid.UUID, input v1Dto.DigestSettingsInput) (v1Dto.DigestSettingsDTO, error)
+	TrackPresence(userUUID uuid.UUID, online bool)
+	SetPresenceCallback(callback PresenceCheckFunc)
+	Start(interval time.Duration)
+}
+
+type EmailVerificationService interface {
+	QueueVerification(user sqlc.User)
+	SendVerification(ctx context.Context, user sqlc.User) error
+	ResendVerification(ctx *gin.Context, email string) error
+	VerifyEmail(ctx *gin.Context, token string) (sqlc.User, error)
+}
+
+type PasswordService interface {
+	ChangePassword(ctx *gin.Context, userUUID uuid.UUID, currentPassword, newPassword string) error
+	RequestPasswordReset(ctx *gin.Context, email string) error
+	ResetPassword(ctx *gin.Context, token, newPassword string) error
+	SetSessionsRevokedCallback(callback SessionsRevokedCallback)
+}
+
+type BlockService interface {
+	BlockUser(ctx *gin.Context, blockerUUID, blockedUUID uuid.UUID) (v1Dto.BlockedUserDTO, error)
+	UnblockUser(ctx *gin.Context, blockerUUID, blockedUUID uuid.UUID) error
+	ListBlockedUsers(ctx *gin.Context, blockerUUID uuid.UUID) ([]v1Dto.BlockedUserDTO, error)
+	ListBlockers(ctx context.Context, userUUID uuid.UUID) (map[uuid.UUID]bool, error)
+}
+
+type AccountService interface {
+	SuspendUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID, input v1Dto.SuspendUserInput) (sqlc.User, error)
+	UnsuspendUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID) (sqlc.User, error)
+	DeactivateUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID) (sqlc.User, error)
+	ReactivateUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID) (sqlc.User, error)
+	DeleteUser(ctx *gin.Context, actorUUID, userUUID uuid.UUID) error
+	DeleteOwnAccount(ctx *gin.Context, userUUID uuid.UUID, password string) error
+	SetAccountStatusCallback(callback AccountStatusCallback)
+}
+type RoomExportService interface {
+	ExportRoomHistory(ctx *gin.Context, roomID int64, userUUID uuid.UUID, format string) (v1Dto.RoomExportFile, RoomExportStream, error)
+}
+type DataExportService interface {
+	RequestExport(ctx *gin.Context, userUUID uuid.UUID, requestedBy *uuid.UUID) (v1Dto.DataExportDTO, error)
+	GetExport(ctx *gin.Context, exportID int64, userUUID uuid.UUID) (v1Dto.DataExportDTO, error)
+	GetExportByID(ctx *gin.Context, exportID int64) (v1Dto.DataExportDTO, error)
+	ListExports(ctx *gin.Context, userUUID uuid.UUID) ([]v1Dto.DataExportDTO, error)
+	OpenSignedExport(ctx *gin.Context, exportID int64, expires int64, signature string) (sqlc.DataExport, io.ReadCloser, error)
+	SetFinishedCallback(callback DataExportCallback)
+	Start(numWorkers int, cleanupInterval time.Duration)
+}
+type ImportService interface {
+	ImportArchive(ctx *gin.Context, adminUUID uuid.UUID, source string, file *multipart.FileHeader) (v1Dto.ImportResultDTO, error)
+}
+type AuditService interface {
+	Record(ctx *gin.Context, event AuditEvent)
+	ListEvents(ctx *gin.Context, query v1Dto.AuditQuery) ([]v1Dto.AuditEventDTO, v1Dto.CursorPagination, error)
+	ExportEvents(ctx *gin.Context, query v1Dto.AuditExportQuery) (v1Dto.AuditExportFile, AuditExportStream, error)
 }
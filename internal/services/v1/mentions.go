@@ -0,0 +1,84 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Loại mention, khớp với chk_mention_type
+const (
+	MentionTypeUser = "user"
+	MentionTypeRoom = "room"
+	MentionTypeHere = "here"
+)
+
+// MentionCallback được gọi sau khi lưu lượt nhắc (vd: gửi sự kiện "mention" qua websocket)
+type MentionCallback func(message sqlc.Message, mentions []sqlc.MessageMention)
+
+// PresenceCheckFunc cho biết user có đang online (có kết nối websocket) không, dùng cho @here
+type PresenceCheckFunc func(userUUID uuid.UUID) bool
+
+// @ phải đứng đầu chuỗi hoặc sau ký tự không thuộc tên/email (tránh bắt nhầm "a@b.com").
+// Tên có thể là phần trước @ của email, cả email, hoặc tên hiển thị viết liền.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\p{L}\p{N}_.@])@([\p{L}\p{N}_.+\-]+(?:@[\p{L}\p{N}\-]+(?:\.[\p{L}\p{N}\-]+)+)?)`)
+
+type parsedMentions struct {
+	names []string // đã chuyển về chữ thường
+	room  bool     // @room
+	here  bool     // @here
+}
+
+func parseMentions(content string) parsedMentions {
+	var parsed parsedMentions
+	for _, match := range mentionPattern.FindAllStringSubmatch(content, -1) {
+		name := strings.ToLower(strings.TrimRight(match[1], ".-"))
+		switch name {
+		case "":
+			continue
+		case MentionTypeRoom:
+			parsed.room = true
+		case MentionTypeHere:
+			parsed.here = true
+		default:
+			parsed.names = append(parsed.names, name)
+		}
+	}
+	return parsed
+}
+
+func (p parsedMentions) empty() bool {
+	return len(p.names) == 0 && !p.room && !p.here
+}
+
+// resolveMentions đối chiếu mention với thành viên phòng, mỗi người chỉ được nhắc một lần
+// (ưu tiên user > here > room). Người gửi không tự nhắc chính mình.
+func resolveMentions(parsed parsedMentions, members []sqlc.User, senderUUID uuid.UUID, isOnline PresenceCheckFunc) map[uuid.UUID]string {
+	names := make(map[string]bool, len(parsed.names))
+	for _, name := range parsed.names {
+		names[name] = true
+	}
+
+	targets := make(map[uuid.UUID]string)
+	for _, member := range members {
+		if member.UserUuid == senderUUID {
+			continue
+		}
+
+		email := strings.ToLower(member.UserEmail)
+		localPart, _, _ := strings.Cut(email, "@")
+		displayName := strings.ToLower(strings.Join(strings.Fields(member.UserFullname), ""))
+
+		switch {
+		case names[email] || names[localPart] || names[displayName]:
+			targets[member.UserUuid] = MentionTypeUser
+		case parsed.here && (isOnline == nil || isOnline(member.UserUuid)):
+			targets[member.UserUuid] = MentionTypeHere
+		case parsed.room:
+			targets[member.UserUuid] = MentionTypeRoom
+		}
+	}
+	return targets
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	"maps"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParseMentions(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		want    parsedMentions
+	}{
+		{"empty", "", parsedMentions{}},
+		{"no mention", "hello everyone", parsedMentions{}},
+		{"single", "hi @alice", parsedMentions{names: []string{"alice"}}},
+		{"lowercased", "hi @Alice", parsedMentions{names: []string{"alice"}}},
+		{"start of message", "@bob look", parsedMentions{names: []string{"bob"}}},
+		{"punctuation around", "(@alice), @bob! @carol?", parsedMentions{names: []string{"alice", "bob", "carol"}}},
+		{"trailing dot and dash", "thanks @alice. and @bob-", parsedMentions{names: []string{"alice", "bob"}}},
+		{"full email", "ping @john.doe@example.com please", parsedMentions{names: []string{"john.doe@example.com"}}},
+		{"email at end of sentence", "ask @john.doe@example.com.", parsedMentions{names: []string{"john.doe@example.com"}}},
+		{"plain email is not a mention", "mail me at alice@example.com", parsedMentions{}},
+		{"double at", "@@alice", parsedMentions{}},
+		{"only punctuation", "@- @.", parsedMentions{}},
+		{"unicode name", "chào @Trần", parsedMentions{names: []string{"trần"}}},
+		{"room", "@room meeting now", parsedMentions{room: true}},
+		{"here", "anyone @here?", parsedMentions{here: true}},
+		{"here and room case insensitive", "@HERE @Room", parsedMentions{room: true, here: true}},
+		{"mixed", "@here @alice @room", parsedMentions{names: []string{"alice"}, room: true, here: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := parseMentions(tt.content)
+			if !slices.Equal(got.names, tt.want.names) || got.room != tt.want.room || got.here != tt.want.here {
+				t.Errorf("parseMentions(%q) = %+v, want %+v", tt.content, got, tt.want)
+			}
+			if got.empty() != (len(tt.want.names) == 0 && !tt.want.room && !tt.want.here) {
+				t.Errorf("parseMentions(%q).empty() = %v", tt.content, got.empty())
+			}
+		})
+	}
+}
+
+func TestResolveMentions(t *testing.T) {
+	sender := sqlc.User{UserUuid: uuid.New(), UserEmail: "carol@example.com", UserFullname: "Carol"}
+	alice := sqlc.User{UserUuid: uuid.New(), UserEmail: "Alice@Example.com", UserFullname: "Alice Nguyen"}
+	bob := sqlc.User{UserUuid: uuid.New(), UserEmail: "bob.tran@example.com", UserFullname: "Bob Tran"}
+	members := []sqlc.User{sender, alice, bob}
+
+	bobOnline := func(userUUID uuid.UUID) bool { return userUUID == bob.UserUuid }
+	nobodyOnline := func(uuid.UUID) bool { return false }
+
+	tests := []struct {
+		name     string
+		parsed   parsedMentions
+		isOnline PresenceCheckFunc
+		want     map[uuid.UUID]string
+	}{
+		{"nothing", parsedMentions{}, bobOnline, map[uuid.UUID]string{}},
+		{"email local part", parsedMentions{names: []string{"alice"}}, nil,
+			map[uuid.UUID]string{alice.UserUuid: MentionTypeUser}},
+		{"full email", parsedMentions{names: []string{"alice@example.com"}}, nil,
+			map[uuid.UUID]string{alice.UserUuid: MentionTypeUser}},
+		{"email local part with dot", parsedMentions{names: []string{"bob.tran"}}, nil,
+			map[uuid.UUID]string{bob.UserUuid: MentionTypeUser}},
+		{"display name without spaces", parsedMentions{names: []string{"bobtran"}}, nil,
+			map[uuid.UUID]string{bob.UserUuid: MentionTypeUser}},
+		{"unknown name", parsedMentions{names: []string{"dave"}}, nil, map[uuid.UUID]string{}},
+		{"self mention", parsedMentions{names: []string{"carol"}}, nil, map[uuid.UUID]string{}},
+		{"self mention by email", parsedMentions{names: []string{"carol@example.com", "alice"}}, nil,
+			map[uuid.UUID]string{alice.UserUuid: MentionTypeUser}},
+		{"here notifies online members only", parsedMentions{here: true}, bobOnline,
+			map[uuid.UUID]string{bob.UserUuid: MentionTypeHere}},
+		{"here with nobody online", parsedMentions{here: true}, nobodyOnline, map[uuid.UUID]string{}},
+		{"here without presence check", parsedMentions{here: true}, nil,
+			map[uuid.UUID]string{alice.UserUuid: MentionTypeHere, bob.UserUuid: MentionTypeHere}},
+		{"room skips sender", parsedMentions{room: true}, nil,
+			map[uuid.UUID]string{alice.UserUuid: MentionTypeRoom, bob.UserUuid: MentionTypeRoom}},
+		{"user beats room", parsedMentions{names: []string{"alice"}, room: true}, nil,
+			map[uuid.UUID]string{alice.UserUuid: MentionTypeUser, bob.UserUuid: MentionTypeRoom}},
+		{"user beats here", parsedMentions{names: []string{"bob.tran"}, here: true}, bobOnline,
+			map[uuid.UUID]string{bob.UserUuid: MentionTypeUser}},
+		{"here beats room for online members", parsedMentions{here: true, room: true}, bobOnline,
+			map[uuid.UUID]string{alice.UserUuid: MentionTypeRoom, bob.UserUuid: MentionTypeHere}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := resolveMentions(tt.parsed, members, sender.UserUuid, tt.isOnline)
+			if !maps.Equal(got, tt.want) {
+				t.Errorf("resolveMentions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseAndResolveMentions(t *testing.T) {
+	sender := sqlc.User{UserUuid: uuid.New(), UserEmail: "carol@example.com", UserFullname: "Carol"}
+	alice := sqlc.User{UserUuid: uuid.New(), UserEmail: "alice@example.com", UserFullname: "Alice"}
+	members := []sqlc.User{sender, alice}
+
+	// Email trong nội dung không phải mention, "@alice." cuối câu vẫn là mention
+	got := resolveMentions(parseMentions("write to carol@example.com, cc @alice."), members, sender.UserUuid, nil)
+	want := map[uuid.UUID]string{alice.UserUuid: MentionTypeUser}
+	if !maps.Equal(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
@@ -5,57 +5,197 @@ import (
 	v1Dto "chat-app/internal/dto/v1"
 	"chat-app/internal/repository"
 	"chat-app/internal/utils"
+	"chat-app/pkg/storage"
 	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 )
 
 type messageService struct {
-	messageRepo repository.MessageRepository
-	roomRepo    repository.RoomRepository
-	userRepo    repository.UserRepository
+	messageRepo    repository.MessageRepository
+	roomRepo       repository.RoomRepository
+	userRepo       repository.UserRepository
+	attachmentRepo repository.AttachmentRepository
+	blockRepo      repository.BlockRepository
+	urlSigner      *storage.URLSigner
+	policy         EmailVerificationPolicy
+
+	// Callback cho mention (gửi sự kiện realtime, kiểm tra online cho @here)
+	mentionCallback  MentionCallback
+	presenceCallback PresenceCheckFunc
+
+	// Callback cho mọi tin nhắn mới (vd: gửi push cho thành viên offline)
+	newMessageCallback MentionCallback
 }
 
-func NewMessageService(messageRepo repository.MessageRepository, roomRepo repository.RoomRepository, userRepo repository.UserRepository) MessageService {
+func NewMessageService(messageRepo repository.MessageRepository, roomRepo repository.RoomRepository, userRepo repository.UserRepository, attachmentRepo repository.AttachmentRepository, blockRepo repository.BlockRepository, urlSigner *storage.URLSigner, policy EmailVerificationPolicy) MessageService {
 	return &messageService{
-		messageRepo: messageRepo,
-		roomRepo:    roomRepo,
-		userRepo:    userRepo,
+		messageRepo:    messageRepo,
+		roomRepo:       roomRepo,
+		userRepo:       userRepo,
+		attachmentRepo: attachmentRepo,
+		blockRepo:      blockRepo,
+		urlSigner:      urlSigner,
+		policy:         policy,
 	}
 }
 
 func (ms *messageService) SaveMessage(ctx *gin.Context, roomID int64, userUUID uuid.UUID, content string) (sqlc.Message, error) {
 	context := ctx.Request.Context()
 
-	// TODO: Implement room membership check
-	// isMember, err := ms.roomRepo.IsRoomMember(context, sqlc.IsRoomMemberParams{
-	// 	UserUuid: userUUID,
-	// 	RoomID:   roomID,
-	// })
-
-	// if err != nil {
-	// 	return sqlc.Message{}, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
-	// }
-
-	// if !isMember {
-	// 	return sqlc.Message{}, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
-	// }
+	slowMode, err := ms.ensureCanPost(context, roomID, userUUID)
+	if err != nil {
+		return sqlc.Message{}, err
+	}
 
 	// Lưu tin nhắn
 	message, err := ms.messageRepo.CreateMessage(context, sqlc.CreateMessageParams{
 		RoomID:   roomID,
 		UserUuid: userUUID,
 		Content:  content,
-	})
+	}, slowMode)
 
 	if err != nil {
-		return sqlc.Message{}, utils.WrapError(err, "could not save message", utils.ErrorCodeInternalServer)
+		return sqlc.Message{}, messageSaveError(err, "could not save message")
 	}
 
+	ms.handleNewMessage(context, message)
+
 	return message, nil
 }
 
+// SetMentionCallback đăng ký hàm được gọi sau khi lưu lượt nhắc trong tin nhắn mới
+func (ms *messageService) SetMentionCallback(callback MentionCallback) {
+	ms.mentionCallback = callback
+}
+
+// SetPresenceCallback đăng ký hàm kiểm tra user online, dùng để xác định người nhận @here
+func (ms *messageService) SetPresenceCallback(callback PresenceCheckFunc) {
+	ms.presenceCallback = callback
+}
+
+// SetNewMessageCallback đăng ký hàm được gọi sau mỗi tin nhắn mới, kèm mọi lượt nhắc đã lưu
+func (ms *messageService) SetNewMessageCallback(callback MentionCallback) {
+	ms.newMessageCallback = callback
+}
+
+// handleNewMessage xử lý sau khi lưu tin nhắn: mention, thông báo, push
+func (ms *messageService) handleNewMessage(ctx context.Context, message sqlc.Message) {
+	mentions := ms.saveMentions(ctx, message)
+	ms.notifyMentions(ctx, message, mentions)
+
+	if ms.newMessageCallback != nil {
+		ms.newMessageCallback(message, mentions)
+	}
+}
+
+// saveMentions lưu các lượt nhắc @user/@room/@here trong tin nhắn, bỏ qua người đã chặn người gửi.
+// Lỗi chỉ được log, tin nhắn vẫn được gửi bình thường.
+func (ms *messageService) saveMentions(ctx context.Context, message sqlc.Message) []sqlc.MessageMention {
+	parsed := parseMentions(message.Content)
+	if parsed.empty() {
+		return nil
+	}
+
+	members, err := ms.roomRepo.GetRoomMembers(ctx, message.RoomID)
+	if err != nil {
+		log.Printf("❌ Error loading room members for mentions: %v", err)
+		return nil
+	}
+
+	targets := resolveMentions(parsed, members, message.UserUuid, ms.presenceCallback)
+	if len(targets) == 0 {
+		return nil
+	}
+
+	blockers, err := loadUserSet(ms.blockRepo.ListBlockerIDs(ctx, message.UserUuid))
+	if err != nil {
+		log.Printf("❌ Error loading blockers of user %s: %v", message.UserUuid, err)
+		return nil
+	}
+	for userUUID := range blockers {
+		delete(targets, userUUID)
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+
+	params := sqlc.CreateMessageMentionsParams{
+		MessageID: message.MessageID,
+		RoomID:    message.RoomID,
+	}
+	for userUUID, mentionType := range targets {
+		params.UserUuids = append(params.UserUuids, userUUID)
+		params.MentionTypes = append(params.MentionTypes, mentionType)
+	}
+
+	mentions, err := ms.messageRepo.CreateMessageMentions(ctx, params)
+	if err != nil {
+		log.Printf("❌ Error saving mentions for message %d: %v", message.MessageID, err)
+		return nil
+	}
+
+	return mentions
+}
+
+// notifyMentions gọi mention callback cho những người được nhắc không tắt thông báo phòng
+func (ms *messageService) notifyMentions(ctx context.Context, message sqlc.Message, mentions []sqlc.MessageMention) {
+	if ms.mentionCallback == nil || len(mentions) == 0 {
+		return
+	}
+
+	// Lượt nhắc vẫn được lưu (mention_count), nhưng chỉ gửi thông báo cho người không tắt thông báo phòng
+	settings, err := ms.roomRepo.ListRoomMemberNotificationSettings(ctx, message.RoomID)
+	if err != nil {
+		log.Printf("❌ Error loading notification settings for room %d: %v", message.RoomID, err)
+		return
+	}
+	notifiable := make(map[uuid.UUID]bool, len(settings))
+	for _, setting := range settings {
+		notifiable[setting.UserUuid] = shouldNotify(setting.MemberNotificationLevel, setting.MemberMutedUntil, true)
+	}
+
+	filtered := make([]sqlc.MessageMention, 0, len(mentions))
+	for _, mention := range mentions {
+		if notifiable[mention.MentionedUserUuid] {
+			filtered = append(filtered, mention)
+		}
+	}
+
+	if len(filtered) > 0 {
+		ms.mentionCallback(message, filtered)
+	}
+}
+
+// MarkMentionsRead đánh dấu đã đọc mọi lượt nhắc user trong phòng, trả về số lượt được cập nhật
+func (ms *messageService) MarkMentionsRead(ctx *gin.Context, roomID int64, userUUID uuid.UUID) (int64, error) {
+	context := ctx.Request.Context()
+
+	isMember, err := ms.roomRepo.IsUserMemberOfRoom(context, userUUID, roomID)
+	if err != nil {
+		return 0, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+
+	if !isMember {
+		return 0, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+	}
+
+	updated, err := ms.messageRepo.MarkRoomMentionsRead(context, userUUID, roomID)
+	if err != nil {
+		return 0, utils.WrapError(err, "could not mark mentions as read", utils.ErrorCodeInternalServer)
+	}
+
+	return updated, nil
+}
+
 func (ms *messageService) GetRoomMessages(ctx *gin.Context, roomID int64, limit, offset int32) ([]sqlc.Message, error) {
 	context := ctx.Request.Context()
 
@@ -87,46 +227,346 @@ func (ms *messageService) GetRoomMessagesWithUsers(ctx *gin.Context, roomID int6
 
 	// Get messages
 	messages, err := ms.messageRepo.GetRoomMessages(context, sqlc.GetRoomMessagesParams{
-		RoomID: roomID,
-		Limit:  limit,
-		Offset: offset,
+		RoomID:     roomID,
+		ViewerUuid: &userUUID,
+		Limit:      limit,
+		Offset:     offset,
 	})
 
 	if err != nil {
 		return nil, utils.WrapError(err, "could not get room messages", utils.ErrorCodeInternalServer)
 	}
 
-	// Get user info for each message
-	var result []v1Dto.MessageWithUser
+	return ms.withUsers(context, messages, userUUID), nil
+}
+
+// GetRoomMessagesPage lấy lịch sử tin nhắn theo cursor, luôn trả về mới nhất trước.
+// next_cursor là message_id cũ nhất của trang khi dùng before (hoặc mặc định), mới nhất khi dùng after.
+func (ms *messageService) GetRoomMessagesPage(ctx *gin.Context, roomID int64, userUUID uuid.UUID, page v1Dto.MessagePageQuery) ([]v1Dto.MessageWithUser, v1Dto.CursorPagination, error) {
+	context := ctx.Request.Context()
+
+	if page.Before != nil && page.After != nil {
+		return nil, v1Dto.CursorPagination{}, utils.NewError("before and after cannot be used together", utils.ErrorCodeBadRequest)
+	}
+
+	isMember, err := ms.roomRepo.IsUserMemberOfRoom(context, userUUID, roomID)
+	if err != nil {
+		return nil, v1Dto.CursorPagination{}, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+
+	if !isMember {
+		return nil, v1Dto.CursorPagination{}, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+	}
+
+	// Lấy thêm 1 bản ghi để biết còn trang sau hay không
+	var messages []sqlc.Message
+	if page.After != nil {
+		messages, err = ms.messageRepo.GetRoomMessagesAfter(context, sqlc.GetRoomMessagesAfterParams{
+			RoomID:     roomID,
+			MessageID:  *page.After,
+			ViewerUuid: &userUUID,
+			Limit:      page.Limit + 1,
+		})
+	} else {
+		messages, err = ms.messageRepo.GetRoomMessagesBefore(context, sqlc.GetRoomMessagesBeforeParams{
+			RoomID:     roomID,
+			BeforeID:   page.Before,
+			ViewerUuid: &userUUID,
+			Limit:      page.Limit + 1,
+		})
+	}
+	if err != nil {
+		return nil, v1Dto.CursorPagination{}, utils.WrapError(err, "could not get room messages", utils.ErrorCodeInternalServer)
+	}
+
+	pagination := v1Dto.CursorPagination{Limit: page.Limit}
+	if len(messages) > int(page.Limit) {
+		messages = messages[:page.Limit]
+		pagination.HasMore = true
+		nextCursor := messages[len(messages)-1].MessageID
+		pagination.NextCursor = &nextCursor
+	}
+
+	// Trang "after" được lấy theo thứ tự tăng dần, đảo lại cho thống nhất
+	if page.After != nil {
+		for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+			messages[i], messages[j] = messages[j], messages[i]
+		}
+	}
+
+	return ms.withUsers(context, messages, userUUID), pagination, nil
+}
+
+// GetMessageContext lấy tối đa before tin nhắn cũ hơn và after tin nhắn mới hơn quanh messageID (mới nhất trước)
+func (ms *messageService) GetMessageContext(ctx *gin.Context, roomID, messageID int64, userUUID uuid.UUID, before, after int32) ([]v1Dto.MessageWithUser, v1Dto.MessageContextPagination, error) {
+	context := ctx.Request.Context()
+
+	// Check if user is member of room
+	isMember, err := ms.roomRepo.IsUserMemberOfRoom(context, userUUID, roomID)
+	if err != nil {
+		return nil, v1Dto.MessageContextPagination{}, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+
+	if !isMember {
+		return nil, v1Dto.MessageContextPagination{}, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+	}
+
+	target, err := ms.messageRepo.GetRoomMessage(context, roomID, messageID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, v1Dto.MessageContextPagination{}, utils.NewError("message not found", utils.ErrorCodeNotFound)
+		}
+		return nil, v1Dto.MessageContextPagination{}, utils.WrapError(err, "could not get message", utils.ErrorCodeInternalServer)
+	}
+
+	// Tin nhắn của người đã bị chặn coi như không tồn tại
+	blocked, err := loadUserSet(ms.blockRepo.ListBlockedUserIDs(context, userUUID))
+	if err != nil {
+		return nil, v1Dto.MessageContextPagination{}, utils.WrapError(err, "could not check blocked users", utils.ErrorCodeInternalServer)
+	}
+	if blocked[target.UserUuid] {
+		return nil, v1Dto.MessageContextPagination{}, utils.NewError("message not found", utils.ErrorCodeNotFound)
+	}
+
+	// Lấy thêm 1 bản ghi mỗi chiều để biết còn dữ liệu hay không
+	older, err := ms.messageRepo.GetRoomMessagesBefore(context, sqlc.GetRoomMessagesBeforeParams{
+		RoomID:     roomID,
+		BeforeID:   &messageID,
+		ViewerUuid: &userUUID,
+		Limit:      before + 1,
+	})
+	if err != nil {
+		return nil, v1Dto.MessageContextPagination{}, utils.WrapError(err, "could not get room messages", utils.ErrorCodeInternalServer)
+	}
+
+	newer, err := ms.messageRepo.GetRoomMessagesAfter(context, sqlc.GetRoomMessagesAfterParams{
+		RoomID:     roomID,
+		MessageID:  messageID,
+		ViewerUuid: &userUUID,
+		Limit:      after + 1,
+	})
+	if err != nil {
+		return nil, v1Dto.MessageContextPagination{}, utils.WrapError(err, "could not get room messages", utils.ErrorCodeInternalServer)
+	}
+
+	pagination := v1Dto.MessageContextPagination{TargetMessageID: messageID}
+	if len(older) > int(before) {
+		older = older[:before]
+		pagination.HasMoreBefore = true
+	}
+	if len(newer) > int(after) {
+		newer = newer[:after]
+		pagination.HasMoreAfter = true
+	}
+
+	// Ghép lại theo thứ tự mới nhất trước: newer (đảo ngược) + target + older
+	messages := make([]sqlc.Message, 0, len(newer)+1+len(older))
+	for i := len(newer) - 1; i >= 0; i-- {
+		messages = append(messages, newer[i])
+	}
+	messages = append(messages, target)
+	messages = append(messages, older...)
+
+	newestID := messages[0].MessageID
+	oldestID := messages[len(messages)-1].MessageID
+	pagination.AfterCursor = &newestID
+	pagination.BeforeCursor = &oldestID
+
+	return ms.withUsers(context, messages, userUUID), pagination, nil
+}
+
+// withUsers gắn thông tin người gửi và file đính kèm vào từng tin nhắn.
+// Tin nhắn của người bị userUUID chặn đã được lọc trong query để trang và cursor không bị lệch
+func (ms *messageService) withUsers(ctx context.Context, messages []sqlc.Message, userUUID uuid.UUID) []v1Dto.MessageWithUser {
+	messageIDs := make([]int64, 0, len(messages))
+	for _, msg := range messages {
+		messageIDs = append(messageIDs, msg.MessageID)
+	}
+
+	attachmentsByMessage := make(map[int64][]v1Dto.AttachmentDTO)
+	attachments, err := ms.attachmentRepo.ListAttachmentsByMessageIDs(ctx, messageIDs)
+	if err != nil {
+		// Vẫn trả về tin nhắn nếu không lấy được file đính kèm
+		log.Printf("❌ Error loading attachments: %v", err)
+	}
+	for _, attachment := range attachments {
+		attachmentsByMessage[attachment.MessageID] = append(attachmentsByMessage[attachment.MessageID], toAttachmentDTO(attachment, ms.urlSigner))
+	}
+
+	result := make([]v1Dto.MessageWithUser, 0, len(messages))
 	for _, msg := range messages {
-		user, err := ms.userRepo.GetUserByUUID(context, msg.UserUuid)
+		user, err := ms.userRepo.GetUserByUUID(ctx, msg.UserUuid)
 		if err != nil {
 			// Skip message if user not found
 			continue
 		}
 
-		messageWithUser := v1Dto.MessageWithUser{
+		result = append(result, v1Dto.MessageWithUser{
 			MessageID:        msg.MessageID,
 			RoomID:           msg.RoomID,
 			UserUUID:         msg.UserUuid.String(),
 			UserFullname:     user.UserFullname,
 			UserEmail:        user.UserEmail,
 			Content:          msg.Content,
+			MessageType:      msg.MessageType,
 			MessageCreatedAt: msg.MessageCreatedAt,
 			IsOwn:            msg.UserUuid == userUUID,
-		}
-
-		result = append(result, messageWithUser)
+			Attachments:      attachmentsByMessage[msg.MessageID],
+		})
 	}
 
-	return result, nil
+	return result
 }
 
 // CreateMessage implements MessageService interface for websocket
 func (ms *messageService) CreateMessage(ctx context.Context, params sqlc.CreateMessageParams) (sqlc.Message, error) {
-	message, err := ms.messageRepo.CreateMessage(ctx, params)
+	slowMode, err := ms.ensureCanPost(ctx, params.RoomID, params.UserUuid)
+	if err != nil {
+		return sqlc.Message{}, err
+	}
+
+	message, err := ms.messageRepo.CreateMessage(ctx, params, slowMode)
 	if err != nil {
-		return sqlc.Message{}, utils.WrapError(err, "could not create message", utils.ErrorCodeInternalServer)
+		return sqlc.Message{}, messageSaveError(err, "could not create message")
 	}
+
+	ms.handleNewMessage(ctx, message)
+
 	return message, nil
 }
+
+// CreateMessageWithAttachment lưu tin nhắn kèm file đính kèm trong một transaction, mention/thông báo/push chỉ chạy sau khi lưu xong.
+// Quyền gửi đã được kiểm tra bằng EnsureCanPost trước khi đẩy file lên storage, slowMode là giá trị EnsureCanPost trả về.
+func (ms *messageService) CreateMessageWithAttachment(ctx context.Context, params sqlc.CreateMessageParams, slowMode time.Duration, attachment sqlc.CreateAttachmentParams) (sqlc.Message, sqlc.MessageAttachment, error) {
+	message, saved, err := ms.messageRepo.CreateMessageWithAttachment(ctx, params, slowMode, attachment)
+	if err != nil {
+		return sqlc.Message{}, sqlc.MessageAttachment{}, messageSaveError(err, "could not save attachment")
+	}
+
+	ms.handleNewMessage(ctx, message)
+
+	return message, saved, nil
+}
+
+// SearchMessages tìm kiếm full-text trong các phòng mà user là thành viên, mới nhất trước
+func (ms *messageService) SearchMessages(ctx *gin.Context, userUUID uuid.UUID, query v1Dto.SearchMessagesQuery) ([]v1Dto.MessageSearchResult, v1Dto.CursorPagination, error) {
+	context := ctx.Request.Context()
+
+	query.Query = strings.TrimSpace(query.Query)
+	if query.Query == "" {
+		return nil, v1Dto.CursorPagination{}, utils.NewError("search query cannot be empty", utils.ErrorCodeBadRequest)
+	}
+	if query.From != nil && query.To != nil && !query.From.Before(*query.To) {
+		return nil, v1Dto.CursorPagination{}, utils.NewError("from must be before to", utils.ErrorCodeBadRequest)
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	// Lấy thêm 1 bản ghi để biết còn trang sau hay không
+	rows, err := ms.messageRepo.SearchMessages(context, sqlc.SearchMessagesParams{
+		UserUuid:   userUUID,
+		Query:      query.Query,
+		RoomID:     query.RoomID,
+		SenderUuid: query.SenderUUID,
+		FromTime:   query.From,
+		ToTime:     query.To,
+		BeforeID:   query.Cursor,
+		Limit:      query.Limit + 1,
+	})
+	if err != nil {
+		return nil, v1Dto.CursorPagination{}, utils.WrapError(err, "could not search messages", utils.ErrorCodeInternalServer)
+	}
+
+	pagination := v1Dto.CursorPagination{Limit: query.Limit}
+	if len(rows) > int(query.Limit) {
+		rows = rows[:query.Limit]
+		pagination.HasMore = true
+		nextCursor := rows[len(rows)-1].MessageID
+		pagination.NextCursor = &nextCursor
+	}
+
+	results := make([]v1Dto.MessageSearchResult, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, v1Dto.MessageSearchResult{
+			MessageID:        row.MessageID,
+			RoomID:           row.RoomID,
+			RoomName:         row.RoomName,
+			UserUUID:         row.UserUuid.String(),
+			UserFullname:     row.UserFullname,
+			Content:          row.Content,
+			Snippet:          row.Snippet,
+			MessageCreatedAt: row.MessageCreatedAt,
+		})
+	}
+
+	return results, pagination, nil
+}
+
+// EnsureCanPost cho phép các luồng khác (upload file...) kiểm tra quyền gửi trước khi xử lý nặng
+func (ms *messageService) EnsureCanPost(ctx context.Context, roomID int64, userUUID uuid.UUID) (time.Duration, error) {
+	return ms.ensureCanPost(ctx, roomID, userUUID)
+}
+
+// ensureCanPost kiểm tra user có được phép gửi tin nhắn vào phòng không (dùng chung cho REST và websocket).
+// Trả về khoảng slow mode áp dụng cho user (0 nếu không giới hạn), được kiểm tra khi lưu tin nhắn.
+func (ms *messageService) ensureCanPost(ctx context.Context, roomID int64, userUUID uuid.UUID) (time.Duration, error) {
+	room, err := ms.roomRepo.GetRoomByID(ctx, roomID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, utils.NewError("room not found", utils.ErrorCodeNotFound)
+		}
+		return 0, utils.WrapError(err, "could not get room", utils.ErrorCodeInternalServer)
+	}
+
+	member, err := ms.roomRepo.GetRoomMember(ctx, userUUID, roomID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+		}
+		return 0, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+
+	if room.RoomArchivedAt != nil {
+		return 0, utils.NewError("room is archived and read-only", utils.ErrorCodeForbidden)
+	}
+
+	if ms.policy.RequireForSendMessages {
+		if err := ensureEmailVerified(ctx, ms.userRepo, userUUID, "sending messages"); err != nil {
+			return 0, err
+		}
+	}
+
+	// Không nhắn tin riêng được khi một trong hai người đã chặn người kia
+	if room.RoomIsDirectChat {
+		blocked, err := ms.blockRepo.HasBlockWithRoomMembers(ctx, userUUID, roomID)
+		if err != nil {
+			return 0, utils.WrapError(err, "could not check blocked users", utils.ErrorCodeInternalServer)
+		}
+		if blocked {
+			return 0, utils.NewError("you cannot send direct messages to this user", utils.ErrorCodeForbidden)
+		}
+	}
+
+	isRoomAdmin := member.MemberRole == RoomRoleOwner || member.MemberRole == RoomRoleAdmin
+	if room.RoomPostingPermission == RoomPostingAdmins && !isRoomAdmin {
+		return 0, utils.NewError("only room owner or admin can post in this announcement room", utils.ErrorCodeForbidden)
+	}
+
+	// Slow mode: Owner/Admin không bị giới hạn
+	if room.RoomSlowModeSeconds > 0 && !isRoomAdmin {
+		return time.Duration(room.RoomSlowModeSeconds) * time.Second, nil
+	}
+
+	return 0, nil
+}
+
+// messageSaveError trả về 429 kèm thời gian chờ khi tin nhắn bị từ chối vì slow mode
+func messageSaveError(err error, message string) error {
+	var slowMode *repository.SlowModeError
+	if errors.As(err, &slowMode) {
+		seconds := int(math.Ceil(slowMode.RetryAfter.Seconds()))
+		return utils.NewRetryAfterError(fmt.Sprintf("slow mode is enabled, please wait %d seconds before sending another message", seconds), slowMode.RetryAfter)
+	}
+	return utils.WrapError(err, message, utils.ErrorCodeInternalServer)
+}
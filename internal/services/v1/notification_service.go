@@ -0,0 +1,223 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Loại thông báo, khớp với chk_notification_type
+const (
+	NotificationTypeMention = "mention"
+)
+
+// Mức thông báo của thành viên theo phòng (khớp với CHECK của member_notification_level)
+const (
+	NotificationLevelAll      = "all"
+	NotificationLevelMentions = "mentions" // chỉ khi được nhắc (@user, @room, @here)
+	NotificationLevelNone     = "none"
+)
+
+const notificationSnippetLength = 200 // số ký tự nội dung tin nhắn lưu trong thông báo
+
+// NotificationCallback được gọi cho mỗi thông báo mới (vd: gửi sự kiện "notification" qua websocket)
+type NotificationCallback func(userUUID uuid.UUID, notification v1Dto.NotificationDTO)
+
+type notificationService struct {
+	notificationRepo repository.NotificationRepository
+	roomRepo         repository.RoomRepository
+	userRepo         repository.UserRepository
+
+	onCreated NotificationCallback
+}
+
+func NewNotificationService(notificationRepo repository.NotificationRepository, roomRepo repository.RoomRepository, userRepo repository.UserRepository) NotificationService {
+	return &notificationService{
+		notificationRepo: notificationRepo,
+		roomRepo:         roomRepo,
+		userRepo:         userRepo,
+	}
+}
+
+// SetNotificationCallback đăng ký hàm được gọi sau khi tạo thông báo
+func (ns *notificationService) SetNotificationCallback(callback NotificationCallback) {
+	ns.onCreated = callback
+}
+
+// NotifyMentions tạo thông báo "mention" cho những người được nhắc trong tin nhắn
+func (ns *notificationService) NotifyMentions(ctx context.Context, message sqlc.Message, mentions []sqlc.MessageMention) {
+	if len(mentions) == 0 {
+		return
+	}
+
+	sender, err := ns.userRepo.GetUserByUUID(ctx, message.UserUuid)
+	if err != nil {
+		log.Printf("❌ Error loading mention sender: %v", err)
+		return
+	}
+
+	var roomName *string
+	if room, err := ns.roomRepo.GetRoomByID(ctx, message.RoomID); err == nil {
+		roomName = room.RoomName
+	}
+
+	content := truncateRunes(message.Content, notificationSnippetLength)
+
+	// Gom theo loại mention để lưu data (mention_type) đúng cho từng nhóm người nhận
+	recipients := make(map[string][]uuid.UUID)
+	for _, mention := range mentions {
+		recipients[mention.MentionType] = append(recipients[mention.MentionType], mention.MentionedUserUuid)
+	}
+
+	actorUUID := message.UserUuid.String()
+	for mentionType, userUUIDs := range recipients {
+		data, _ := json.Marshal(map[string]interface{}{
+			"mention_type":   mentionType,
+			"content":        content,
+			"actor_fullname": sender.UserFullname,
+			"room_name":      roomName,
+		})
+
+		ns.create(ctx, sqlc.CreateNotificationsParams{
+			NotificationType: NotificationTypeMention,
+			RoomID:           &message.RoomID,
+			MessageID:        &message.MessageID,
+			ActorUuid:        &actorUUID,
+			NotificationData: data,
+			UserUuids:        userUUIDs,
+		})
+	}
+}
+
+// create lưu thông báo cho nhiều người nhận rồi gửi realtime. Lỗi chỉ được log.
+func (ns *notificationService) create(ctx context.Context, params sqlc.CreateNotificationsParams) {
+	notifications, err := ns.notificationRepo.CreateNotifications(ctx, params)
+	if err != nil {
+		log.Printf("❌ Error creating %s notifications: %v", params.NotificationType, err)
+		return
+	}
+
+	if ns.onCreated == nil {
+		return
+	}
+	for _, notification := range notifications {
+		ns.onCreated(notification.UserUuid, toNotificationDTO(notification))
+	}
+}
+
+// ListNotifications lấy thông báo của user, mới nhất trước, kèm tổng số chưa đọc
+func (ns *notificationService) ListNotifications(ctx *gin.Context, userUUID uuid.UUID, query v1Dto.NotificationQuery) ([]v1Dto.NotificationDTO, v1Dto.CursorPagination, int64, error) {
+	context := ctx.Request.Context()
+
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	// Lấy thêm 1 bản ghi để biết còn trang sau hay không
+	rows, err := ns.notificationRepo.ListNotifications(context, sqlc.ListNotificationsParams{
+		UserUuid:   userUUID,
+		UnreadOnly: query.UnreadOnly,
+		BeforeID:   query.Cursor,
+		Limit:      query.Limit + 1,
+	})
+	if err != nil {
+		return nil, v1Dto.CursorPagination{}, 0, utils.WrapError(err, "could not get notifications", utils.ErrorCodeInternalServer)
+	}
+
+	pagination := v1Dto.CursorPagination{Limit: query.Limit}
+	if len(rows) > int(query.Limit) {
+		rows = rows[:query.Limit]
+		pagination.HasMore = true
+		nextCursor := rows[len(rows)-1].NotificationID
+		pagination.NextCursor = &nextCursor
+	}
+
+	unreadCount, err := ns.notificationRepo.CountUnreadNotifications(context, userUUID)
+	if err != nil {
+		return nil, v1Dto.CursorPagination{}, 0, utils.WrapError(err, "could not count unread notifications", utils.ErrorCodeInternalServer)
+	}
+
+	notifications := make([]v1Dto.NotificationDTO, 0, len(rows))
+	for _, row := range rows {
+		notifications = append(notifications, toNotificationDTO(row))
+	}
+
+	return notifications, pagination, unreadCount, nil
+}
+
+func (ns *notificationService) MarkRead(ctx *gin.Context, userUUID uuid.UUID, notificationID int64) (v1Dto.NotificationDTO, error) {
+	context := ctx.Request.Context()
+
+	notification, err := ns.notificationRepo.MarkNotificationRead(context, notificationID, userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v1Dto.NotificationDTO{}, utils.NewError("notification not found", utils.ErrorCodeNotFound)
+		}
+		return v1Dto.NotificationDTO{}, utils.WrapError(err, "could not mark notification as read", utils.ErrorCodeInternalServer)
+	}
+
+	return toNotificationDTO(notification), nil
+}
+
+func (ns *notificationService) MarkAllRead(ctx *gin.Context, userUUID uuid.UUID) (int64, error) {
+	context := ctx.Request.Context()
+
+	updated, err := ns.notificationRepo.MarkAllNotificationsRead(context, userUUID)
+	if err != nil {
+		return 0, utils.WrapError(err, "could not mark notifications as read", utils.ErrorCodeInternalServer)
+	}
+
+	return updated, nil
+}
+
+// truncateRunes cắt chuỗi còn tối đa length ký tự (thêm "…" nếu bị cắt)
+func truncateRunes(text string, length int) string {
+	runes := []rune(text)
+	if len(runes) <= length {
+		return text
+	}
+	return string(runes[:length]) + "…"
+}
+
+// shouldNotify cho biết thành viên có nhận thông báo về tin nhắn không, theo mức thông báo và thời gian tắt của phòng
+func shouldNotify(level string, mutedUntil *time.Time, isMention bool) bool {
+	if mutedUntil != nil && mutedUntil.After(time.Now()) {
+		return false
+	}
+
+	switch level {
+	case NotificationLevelNone:
+		return false
+	case NotificationLevelMentions:
+		return isMention
+	}
+	return true
+}
+
+func toNotificationDTO(notification sqlc.Notification) v1Dto.NotificationDTO {
+	dto := v1Dto.NotificationDTO{
+		NotificationID:   notification.NotificationID,
+		NotificationType: notification.NotificationType,
+		RoomID:           notification.RoomID,
+		MessageID:        notification.MessageID,
+		Data:             notification.NotificationData,
+		IsRead:           notification.NotificationReadAt != nil,
+		ReadAt:           notification.NotificationReadAt,
+		CreatedAt:        notification.NotificationCreatedAt,
+	}
+	if notification.ActorUuid != uuid.Nil {
+		actorUUID := notification.ActorUuid.String()
+		dto.ActorUUID = &actorUUID
+	}
+	return dto
+}
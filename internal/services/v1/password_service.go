@@ -0,0 +1,275 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/templates"
+	"chat-app/internal/utils"
+	"chat-app/pkg/auth"
+	"chat-app/pkg/mailer"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// SessionsRevokedCallback được gọi sau khi mọi phiên đăng nhập của user bị thu hồi (vd: đóng kết nối websocket)
+type SessionsRevokedCallback func(userUUID uuid.UUID)
+
+// PasswordResetOptions cấu hình token và giới hạn gửi email đặt lại mật khẩu
+type PasswordResetOptions struct {
+	TTL             time.Duration
+	RequestInterval time.Duration
+	DailyLimit      int
+	BaseURL         string
+}
+
+// passwordResetEmailData là dữ liệu cho template email/password_reset
+type passwordResetEmailData struct {
+	Name      string
+	URL       string
+	ExpiresIn string
+}
+
+type passwordService struct {
+	userRepo     repository.UserRepository
+	resetRepo    repository.PasswordResetRepository
+	tokenService auth.TokenService
+	mailer       mailer.Mailer
+	auditService AuditService
+	options      PasswordResetOptions
+
+	sessionsRevokedCallback SessionsRevokedCallback
+}
+
+func NewPasswordService(userRepo repository.UserRepository, resetRepo repository.PasswordResetRepository, tokenService auth.TokenService, mailer mailer.Mailer, auditService AuditService, options PasswordResetOptions) PasswordService {
+	return &passwordService{
+		userRepo:     userRepo,
+		resetRepo:    resetRepo,
+		tokenService: tokenService,
+		mailer:       mailer,
+		auditService: auditService,
+		options:      options,
+	}
+}
+
+// SetSessionsRevokedCallback đăng ký hàm xử lý khi phiên đăng nhập của user bị thu hồi
+func (ps *passwordService) SetSessionsRevokedCallback(callback SessionsRevokedCallback) {
+	ps.sessionsRevokedCallback = callback
+}
+
+// ChangePassword đổi mật khẩu cho user đang đăng nhập, yêu cầu mật khẩu hiện tại
+func (ps *passwordService) ChangePassword(ctx *gin.Context, userUUID uuid.UUID, currentPassword, newPassword string) error {
+	context := ctx.Request.Context()
+
+	user, err := ps.userRepo.GetUserByUUID(context, userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	if err := bcrypt.CompareHashAndPassword([]byte(user.UserPassword), []byte(currentPassword)); err != nil {
+		return utils.NewError("current password is incorrect", utils.ErrorCodeBadRequest)
+	}
+	if currentPassword == newPassword {
+		return utils.NewError("new password must be different from the current password", utils.ErrorCodeBadRequest)
+	}
+
+	if err := ps.setPassword(context, userUUID, newPassword); err != nil {
+		return err
+	}
+
+	ps.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionPasswordChanged,
+		ActorUUID:  &userUUID,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   userUUID.String(),
+	})
+	return nil
+}
+
+// RequestPasswordReset gửi link đặt lại mật khẩu ở background. Luôn trả về thành công (trừ lỗi hệ thống)
+// để không lộ email nào đã đăng ký, kể cả khi vượt giới hạn gửi.
+func (ps *passwordService) RequestPasswordReset(ctx *gin.Context, email string) error {
+	context := ctx.Request.Context()
+
+	user, err := ps.userRepo.GetUserByEmail(context, utils.NormalizeString(email))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil
+		}
+		return utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	go ps.sendResetEmail(user)
+	return nil
+}
+
+// ResetPassword đặt mật khẩu mới bằng token trong email đặt lại mật khẩu
+func (ps *passwordService) ResetPassword(ctx *gin.Context, token, newPassword string) error {
+	context := ctx.Request.Context()
+	invalid := utils.NewError("invalid or expired password reset token", utils.ErrorCodeBadRequest)
+
+	resetToken, err := ps.resetRepo.GetPasswordResetTokenByHash(context, hashResetToken(token))
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return invalid
+		}
+		return utils.WrapError(err, "could not get password reset token", utils.ErrorCodeInternalServer)
+	}
+	if resetToken.TokenUsedAt != nil || time.Now().After(resetToken.TokenExpiresAt) {
+		return invalid
+	}
+
+	hashedPassword, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	// Token chỉ bị đánh dấu đã dùng khi mật khẩu được lưu, request đồng thời với cùng token sẽ không cập nhật được
+	used, err := ps.resetRepo.ResetPasswordWithToken(context, resetToken.TokenID, resetToken.UserUuid, hashedPassword)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return utils.WrapError(err, "could not reset password", utils.ErrorCodeInternalServer)
+	}
+	if !used {
+		return invalid
+	}
+
+	if err := ps.revokeSessions(resetToken.UserUuid); err != nil {
+		return err
+	}
+
+	// Người đặt lại là chủ email nhận link, không có phiên đăng nhập
+	ps.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionPasswordReset,
+		ActorUUID:  &resetToken.UserUuid,
+		TargetType: v1Dto.AuditTargetUser,
+		TargetID:   resetToken.UserUuid.String(),
+	})
+	return nil
+}
+
+// setPassword lưu mật khẩu mới, hủy các token đặt lại mật khẩu còn lại và thu hồi mọi phiên đăng nhập
+func (ps *passwordService) setPassword(ctx context.Context, userUUID uuid.UUID, newPassword string) error {
+	hashedPassword, err := hashPassword(newPassword)
+	if err != nil {
+		return err
+	}
+
+	if _, err := ps.userRepo.UpdateUserPassword(ctx, userUUID, hashedPassword); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return utils.WrapError(err, "could not update password", utils.ErrorCodeInternalServer)
+	}
+
+	if err := ps.resetRepo.InvalidateUserPasswordResetTokens(ctx, userUUID); err != nil {
+		log.Printf("❌ Error invalidating password reset tokens of user %s: %v", userUUID, err)
+	}
+
+	return ps.revokeSessions(userUUID)
+}
+
+// revokeSessions thu hồi mọi phiên đăng nhập của user sau khi đổi mật khẩu
+func (ps *passwordService) revokeSessions(userUUID uuid.UUID) error {
+	if err := ps.tokenService.RevokeUserTokens(userUUID); err != nil {
+		return err
+	}
+	if ps.sessionsRevokedCallback != nil {
+		ps.sessionsRevokedCallback(userUUID)
+	}
+	return nil
+}
+
+func hashPassword(password string) (string, error) {
+	hashedPassword, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", utils.WrapError(err, "failed to hash password", utils.ErrorCodeInternalServer)
+	}
+	return string(hashedPassword), nil
+}
+
+func (ps *passwordService) sendResetEmail(user sqlc.User) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if err := ps.deliverResetEmail(ctx, user); err != nil {
+		log.Printf("❌ Error sending password reset email to user %s: %v", user.UserUuid, err)
+	}
+}
+
+// deliverResetEmail tạo token mới và gửi link đặt lại mật khẩu, giới hạn theo RequestInterval và DailyLimit
+func (ps *passwordService) deliverResetEmail(ctx context.Context, user sqlc.User) error {
+	now := time.Now()
+	recent, err := ps.resetRepo.CountPasswordResetTokensSince(ctx, user.UserUuid, now.Add(-ps.options.RequestInterval))
+	if err != nil {
+		return err
+	}
+	daily, err := ps.resetRepo.CountPasswordResetTokensSince(ctx, user.UserUuid, now.Add(-24*time.Hour))
+	if err != nil {
+		return err
+	}
+	if recent > 0 || daily >= int64(ps.options.DailyLimit) {
+		log.Printf("⚠️ Password reset for user %s throttled", user.UserUuid)
+		return nil
+	}
+
+	token, err := generateResetToken()
+	if err != nil {
+		return err
+	}
+	if _, err := ps.resetRepo.CreatePasswordResetToken(ctx, sqlc.CreatePasswordResetTokenParams{
+		UserUuid:       user.UserUuid,
+		TokenHash:      hashResetToken(token),
+		TokenExpiresAt: now.Add(ps.options.TTL),
+	}); err != nil {
+		return err
+	}
+
+	text, html, err := templates.RenderEmail("password_reset", passwordResetEmailData{
+		Name:      user.UserFullname,
+		URL:       ps.options.BaseURL + "/reset-password?token=" + url.QueryEscape(token),
+		ExpiresIn: humanizeDuration(ps.options.TTL),
+	})
+	if err != nil {
+		return err
+	}
+
+	return ps.mailer.Send(ctx, mailer.Message{
+		To:      user.UserEmail,
+		Subject: "Reset your password",
+		Text:    text,
+		HTML:    html,
+	})
+}
+
+// generateResetToken tạo token ngẫu nhiên 256 bit, chỉ gửi qua email và không lưu trong DB
+func generateResetToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(buf), nil
+}
+
+// hashResetToken trả về SHA-256 (hex) của token, giá trị được lưu và tra cứu trong DB
+func hashResetToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
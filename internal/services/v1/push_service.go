@@ -0,0 +1,238 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/utils"
+	"chat-app/pkg/push"
+	"context"
+	"log"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+)
+
+const pushBodyLength = 120 // số ký tự nội dung tin nhắn hiển thị trong push
+
+type pushService struct {
+	pushRepo   repository.PushDeviceRepository
+	roomRepo   repository.RoomRepository
+	blockRepo  repository.BlockRepository
+	userRepo   repository.UserRepository
+	dispatcher *push.Dispatcher
+
+	// Kiểm tra user online, user đang có kết nối websocket không nhận push
+	presenceCallback PresenceCheckFunc
+}
+
+func NewPushService(pushRepo repository.PushDeviceRepository, roomRepo repository.RoomRepository, blockRepo repository.BlockRepository, userRepo repository.UserRepository, dispatcher *push.Dispatcher) PushService {
+	ps := &pushService{
+		pushRepo:   pushRepo,
+		roomRepo:   roomRepo,
+		blockRepo:  blockRepo,
+		userRepo:   userRepo,
+		dispatcher: dispatcher,
+	}
+
+	// Nhà cung cấp báo token hết hạn (app bị gỡ, subscription bị hủy) thì xóa thiết bị
+	dispatcher.SetInvalidTokenCallback(func(device push.Device) {
+		if err := pushRepo.DeletePushDeviceByID(context.Background(), device.ID); err != nil {
+			log.Printf("❌ Error deleting push device %d: %v", device.ID, err)
+		}
+	})
+
+	return ps
+}
+
+// SetPresenceCallback đăng ký hàm kiểm tra user online
+func (ps *pushService) SetPresenceCallback(callback PresenceCheckFunc) {
+	ps.presenceCallback = callback
+}
+
+// RegisterDevice lưu thiết bị nhận push, đăng ký lại cùng token chỉ cập nhật thông tin
+func (ps *pushService) RegisterDevice(ctx *gin.Context, userUUID uuid.UUID, input v1Dto.RegisterPushDeviceInput) (v1Dto.PushDeviceDTO, error) {
+	context := ctx.Request.Context()
+
+	if !ps.dispatcher.Supports(input.Platform) {
+		return v1Dto.PushDeviceDTO{}, utils.NewError("push platform "+input.Platform+" is not enabled on this server", utils.ErrorCodeBadRequest)
+	}
+
+	params := sqlc.UpsertPushDeviceParams{
+		UserUuid:       userUUID,
+		DevicePlatform: input.Platform,
+		DeviceToken:    input.Token,
+	}
+	if input.Platform == push.PlatformWebPush {
+		endpoint, err := url.Parse(input.Token)
+		if err != nil || endpoint.Scheme != "https" || endpoint.Host == "" {
+			return v1Dto.PushDeviceDTO{}, utils.NewError("web push token must be the subscription's https endpoint", utils.ErrorCodeBadRequest)
+		}
+		params.DeviceP256dh = &input.P256dh
+		params.DeviceAuth = &input.Auth
+	}
+
+	device, err := ps.pushRepo.UpsertPushDevice(context, params)
+	if err != nil {
+		return v1Dto.PushDeviceDTO{}, utils.WrapError(err, "could not register device", utils.ErrorCodeInternalServer)
+	}
+
+	return toPushDeviceDTO(device), nil
+}
+
+func (ps *pushService) ListDevices(ctx *gin.Context, userUUID uuid.UUID) ([]v1Dto.PushDeviceDTO, error) {
+	context := ctx.Request.Context()
+
+	devices, err := ps.pushRepo.ListUserPushDevices(context, userUUID)
+	if err != nil {
+		return nil, utils.WrapError(err, "could not get devices", utils.ErrorCodeInternalServer)
+	}
+
+	result := make([]v1Dto.PushDeviceDTO, 0, len(devices))
+	for _, device := range devices {
+		result = append(result, toPushDeviceDTO(device))
+	}
+	return result, nil
+}
+
+func (ps *pushService) DeleteDevice(ctx *gin.Context, userUUID uuid.UUID, deviceID int64) error {
+	context := ctx.Request.Context()
+
+	deleted, err := ps.pushRepo.DeletePushDevice(context, deviceID, userUUID)
+	if err != nil {
+		return utils.WrapError(err, "could not delete device", utils.ErrorCodeInternalServer)
+	}
+	if deleted == 0 {
+		return utils.NewError("device not found", utils.ErrorCodeNotFound)
+	}
+	return nil
+}
+
+// NotifyNewMessage gửi push về tin nhắn mới cho thành viên offline không tắt thông báo phòng và không chặn người gửi.
+// Chạy nền để không làm chậm việc gửi tin nhắn.
+func (ps *pushService) NotifyNewMessage(message sqlc.Message, mentions []sqlc.MessageMention) {
+	go ps.notifyNewMessage(message, mentions)
+}
+
+func (ps *pushService) notifyNewMessage(message sqlc.Message, mentions []sqlc.MessageMention) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	settings, err := ps.roomRepo.ListRoomMemberNotificationSettings(ctx, message.RoomID)
+	if err != nil {
+		log.Printf("❌ Error loading notification settings for room %d: %v", message.RoomID, err)
+		return
+	}
+
+	// Người đã chặn người gửi không nhận push về tin nhắn của họ
+	blockers, err := loadUserSet(ps.blockRepo.ListBlockerIDs(ctx, message.UserUuid))
+	if err != nil {
+		log.Printf("❌ Error loading blockers of user %s: %v", message.UserUuid, err)
+		return
+	}
+
+	mentioned := make(map[uuid.UUID]bool, len(mentions))
+	for _, mention := range mentions {
+		mentioned[mention.MentionedUserUuid] = true
+	}
+
+	var recipients []uuid.UUID
+	for _, setting := range settings {
+		if setting.UserUuid == message.UserUuid || blockers[setting.UserUuid] {
+			continue
+		}
+		// User đang online đã nhận tin qua websocket
+		if ps.presenceCallback != nil && ps.presenceCallback(setting.UserUuid) {
+			continue
+		}
+		if !shouldNotify(setting.MemberNotificationLevel, setting.MemberMutedUntil, mentioned[setting.UserUuid]) {
+			continue
+		}
+		recipients = append(recipients, setting.UserUuid)
+	}
+	if len(recipients) == 0 {
+		return
+	}
+
+	devices, err := ps.pushRepo.ListPushDevicesByUsers(ctx, recipients)
+	if err != nil {
+		log.Printf("❌ Error loading push devices: %v", err)
+		return
+	}
+	if len(devices) == 0 {
+		return
+	}
+
+	notification, err := ps.buildNotification(ctx, message)
+	if err != nil {
+		log.Printf("❌ Error building push for message %d: %v", message.MessageID, err)
+		return
+	}
+
+	for _, device := range devices {
+		if !ps.dispatcher.Enqueue(toPushTarget(device), notification) {
+			log.Printf("⚠️ Push to device %d (%s) was not queued", device.DeviceID, device.DevicePlatform)
+		}
+	}
+}
+
+// buildNotification tạo nội dung push: phòng chat hiển thị "tên phòng" + "người gửi: nội dung", chat riêng hiển thị tên người gửi
+func (ps *pushService) buildNotification(ctx context.Context, message sqlc.Message) (push.Notification, error) {
+	sender, err := ps.userRepo.GetUserByUUID(ctx, message.UserUuid)
+	if err != nil {
+		return push.Notification{}, err
+	}
+	room, err := ps.roomRepo.GetRoomByID(ctx, message.RoomID)
+	if err != nil {
+		return push.Notification{}, err
+	}
+
+	body := truncateRunes(message.Content, pushBodyLength)
+	if body == "" {
+		body = "📎 Attachment"
+	}
+
+	notification := push.Notification{
+		Title: sender.UserFullname,
+		Body:  body,
+		Data: map[string]string{
+			"type":       "message",
+			"room_id":    strconv.FormatInt(message.RoomID, 10),
+			"message_id": strconv.FormatInt(message.MessageID, 10),
+		},
+		CollapseKey: "room-" + strconv.FormatInt(message.RoomID, 10),
+	}
+	if !room.RoomIsDirectChat && room.RoomName != nil {
+		notification.Title = *room.RoomName
+		notification.Body = sender.UserFullname + ": " + notification.Body
+	}
+	return notification, nil
+}
+
+func toPushTarget(device sqlc.PushDevice) push.Device {
+	target := push.Device{
+		ID:       device.DeviceID,
+		Platform: device.DevicePlatform,
+		Token:    device.DeviceToken,
+	}
+	if device.DeviceP256dh != nil {
+		target.P256dh = *device.DeviceP256dh
+	}
+	if device.DeviceAuth != nil {
+		target.Auth = *device.DeviceAuth
+	}
+	return target
+}
+
+func toPushDeviceDTO(device sqlc.PushDevice) v1Dto.PushDeviceDTO {
+	return v1Dto.PushDeviceDTO{
+		DeviceID:   device.DeviceID,
+		Platform:   device.DevicePlatform,
+		Token:      device.DeviceToken,
+		CreatedAt:  device.DeviceCreatedAt,
+		LastUsedAt: device.DeviceLastUsedAt,
+	}
+}
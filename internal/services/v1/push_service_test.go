@@ -0,0 +1,144 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	"chat-app/internal/repository"
+	"chat-app/pkg/push"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Các repository giả chỉ cài những method pushService dùng, gọi method khác sẽ panic
+type fakePushRoomRepo struct {
+	repository.RoomRepository
+	settings []sqlc.ListRoomMemberNotificationSettingsRow
+}
+
+func (r *fakePushRoomRepo) ListRoomMemberNotificationSettings(ctx context.Context, roomID int64) ([]sqlc.ListRoomMemberNotificationSettingsRow, error) {
+	return r.settings, nil
+}
+
+func (r *fakePushRoomRepo) GetRoomByID(ctx context.Context, roomID int64) (sqlc.Room, error) {
+	name := "general"
+	return sqlc.Room{RoomID: roomID, RoomName: &name}, nil
+}
+
+type fakePushBlockRepo struct {
+	repository.BlockRepository
+	blockers []uuid.UUID
+}
+
+func (r *fakePushBlockRepo) ListBlockerIDs(ctx context.Context, blockedUUID uuid.UUID) ([]uuid.UUID, error) {
+	return r.blockers, nil
+}
+
+type fakePushUserRepo struct {
+	repository.UserRepository
+}
+
+func (r *fakePushUserRepo) GetUserByUUID(ctx context.Context, userUUID uuid.UUID) (sqlc.User, error) {
+	return sqlc.User{UserUuid: userUUID, UserFullname: "Sender"}, nil
+}
+
+type fakePushDeviceRepo struct {
+	repository.PushDeviceRepository
+	devices map[uuid.UUID]sqlc.PushDevice
+}
+
+func (r *fakePushDeviceRepo) ListPushDevicesByUsers(ctx context.Context, userUUIDs []uuid.UUID) ([]sqlc.PushDevice, error) {
+	var devices []sqlc.PushDevice
+	for _, userUUID := range userUUIDs {
+		if device, ok := r.devices[userUUID]; ok {
+			devices = append(devices, device)
+		}
+	}
+	return devices, nil
+}
+
+func (r *fakePushDeviceRepo) DeletePushDeviceByID(ctx context.Context, deviceID int64) error {
+	return nil
+}
+
+func TestNotifyNewMessageFiltersRecipients(t *testing.T) {
+	var mu sync.Mutex
+	var tokens []string
+	received := make(chan struct{}, 20)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body struct {
+			Token string `json:"token"`
+		}
+		json.NewDecoder(r.Body).Decode(&body)
+		mu.Lock()
+		tokens = append(tokens, body.Token)
+		mu.Unlock()
+		received <- struct{}{}
+	}))
+	defer server.Close()
+
+	dispatcher := push.NewDispatcher(push.DispatcherConfig{Workers: 1, QueueSize: 20, MaxAttempts: 1, BaseBackoff: time.Millisecond, SendTimeout: time.Second})
+	dispatcher.Register(push.PlatformFCM, push.NewHTTPProvider(server.URL))
+	dispatcher.Start()
+
+	future := time.Now().Add(time.Hour)
+	past := time.Now().Add(-time.Hour)
+	users := map[string]uuid.UUID{}
+	for _, name := range []string{"sender", "offline", "online", "muted", "mute_expired", "level_none", "mentions_only", "mentioned", "blocker"} {
+		users[name] = uuid.New()
+	}
+
+	settings := []sqlc.ListRoomMemberNotificationSettingsRow{
+		{UserUuid: users["sender"], MemberNotificationLevel: NotificationLevelAll},
+		{UserUuid: users["offline"], MemberNotificationLevel: NotificationLevelAll},
+		{UserUuid: users["online"], MemberNotificationLevel: NotificationLevelAll},
+		{UserUuid: users["muted"], MemberNotificationLevel: NotificationLevelAll, MemberMutedUntil: &future},
+		{UserUuid: users["mute_expired"], MemberNotificationLevel: NotificationLevelAll, MemberMutedUntil: &past},
+		{UserUuid: users["level_none"], MemberNotificationLevel: NotificationLevelNone},
+		{UserUuid: users["mentions_only"], MemberNotificationLevel: NotificationLevelMentions},
+		{UserUuid: users["mentioned"], MemberNotificationLevel: NotificationLevelMentions},
+		{UserUuid: users["blocker"], MemberNotificationLevel: NotificationLevelAll},
+	}
+	devices := make(map[uuid.UUID]sqlc.PushDevice)
+	id := int64(0)
+	for name, userUUID := range users {
+		id++
+		devices[userUUID] = sqlc.PushDevice{DeviceID: id, UserUuid: userUUID, DevicePlatform: push.PlatformFCM, DeviceToken: name}
+	}
+
+	ps := NewPushService(
+		&fakePushDeviceRepo{devices: devices},
+		&fakePushRoomRepo{settings: settings},
+		&fakePushBlockRepo{blockers: []uuid.UUID{users["blocker"]}},
+		&fakePushUserRepo{},
+		dispatcher,
+	).(*pushService)
+	ps.SetPresenceCallback(func(userUUID uuid.UUID) bool { return userUUID == users["online"] })
+
+	message := sqlc.Message{MessageID: 1, RoomID: 1, UserUuid: users["sender"], Content: "hello"}
+	mentions := []sqlc.MessageMention{{MessageID: 1, MentionedUserUuid: users["mentioned"]}}
+	ps.notifyNewMessage(message, mentions)
+
+	want := []string{"mentioned", "mute_expired", "offline"}
+	for range want {
+		select {
+		case <-received:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for pushes, got %v", tokens)
+		}
+	}
+	time.Sleep(50 * time.Millisecond)
+
+	mu.Lock()
+	defer mu.Unlock()
+	slices.Sort(tokens)
+	if !slices.Equal(tokens, want) {
+		t.Errorf("pushed to %v, want %v", tokens, want)
+	}
+}
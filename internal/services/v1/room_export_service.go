@@ -0,0 +1,296 @@
+package services
+
+import (
+	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
+	"chat-app/internal/repository"
+	"chat-app/internal/templates"
+	"chat-app/internal/utils"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+const roomExportPageSize = 1000 // số tin nhắn đọc mỗi lượt, bộ nhớ không tăng theo độ dài lịch sử
+
+// RoomExportStream ghi toàn bộ lịch sử phòng vào w theo từng trang
+type RoomExportStream func(w io.Writer) error
+
+// roomExportMessage là một dòng của file xuất (một dòng NDJSON, một hàng CSV, một <article> HTML)
+type roomExportMessage struct {
+	MessageID   int64                  `json:"message_id"`
+	CreatedAt   time.Time              `json:"created_at"`
+	SenderUUID  uuid.UUID              `json:"sender_uuid"`
+	SenderName  string                 `json:"sender_name"`
+	Type        string                 `json:"type"`
+	Content     string                 `json:"content"`
+	Attachments []roomExportAttachment `json:"attachments"`
+}
+
+type roomExportAttachment struct {
+	AttachmentID int64  `json:"attachment_id"`
+	FileName     string `json:"file_name"`
+	ContentType  string `json:"content_type"`
+	SizeBytes    int64  `json:"size_bytes"`
+}
+
+// roomExportWriter ghi file xuất theo một định dạng
+type roomExportWriter interface {
+	begin(room sqlc.Room) error
+	write(message roomExportMessage) error
+	flush() error // gọi sau mỗi trang
+	end(count int) error
+}
+
+type roomExportService struct {
+	roomRepo       repository.RoomRepository
+	messageRepo    repository.MessageRepository
+	attachmentRepo repository.AttachmentRepository
+}
+
+func NewRoomExportService(roomRepo repository.RoomRepository, messageRepo repository.MessageRepository, attachmentRepo repository.AttachmentRepository) RoomExportService {
+	return &roomExportService{
+		roomRepo:       roomRepo,
+		messageRepo:    messageRepo,
+		attachmentRepo: attachmentRepo,
+	}
+}
+
+// ExportRoomHistory kiểm tra quyền (chỉ chủ phòng) rồi trả về thông tin file và hàm stream nội dung.
+// Lỗi trả về trước khi stream để handler còn trả được JSON lỗi.
+func (rs *roomExportService) ExportRoomHistory(ctx *gin.Context, roomID int64, userUUID uuid.UUID, format string) (v1Dto.RoomExportFile, RoomExportStream, error) {
+	context := ctx.Request.Context()
+
+	room, err := rs.roomRepo.GetRoomByID(context, roomID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v1Dto.RoomExportFile{}, nil, utils.NewError("room not found", utils.ErrorCodeNotFound)
+		}
+		return v1Dto.RoomExportFile{}, nil, utils.WrapError(err, "could not get room", utils.ErrorCodeInternalServer)
+	}
+
+	member, err := rs.roomRepo.GetRoomMember(context, userUUID, roomID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return v1Dto.RoomExportFile{}, nil, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+		}
+		return v1Dto.RoomExportFile{}, nil, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+	if member.MemberRole != RoomRoleOwner {
+		return v1Dto.RoomExportFile{}, nil, utils.NewError("only room owner can export the room history", utils.ErrorCodeForbidden)
+	}
+
+	if format == "" {
+		format = v1Dto.RoomExportFormatNDJSON
+	}
+
+	file := v1Dto.RoomExportFile{
+		FileName: fmt.Sprintf("room-%s-%s.%s", room.RoomCode, time.Now().UTC().Format("20060102"), format),
+	}
+	var newWriter func(w io.Writer) roomExportWriter
+	switch format {
+	case v1Dto.RoomExportFormatNDJSON:
+		file.ContentType = "application/x-ndjson"
+		newWriter = func(w io.Writer) roomExportWriter { return &ndjsonRoomExport{enc: json.NewEncoder(w)} }
+	case v1Dto.RoomExportFormatCSV:
+		file.ContentType = "text/csv; charset=utf-8"
+		newWriter = func(w io.Writer) roomExportWriter { return &csvRoomExport{w: csv.NewWriter(w)} }
+	case v1Dto.RoomExportFormatHTML:
+		file.ContentType = "text/html; charset=utf-8"
+		newWriter = func(w io.Writer) roomExportWriter { return &htmlRoomExport{w: w} }
+	default:
+		return v1Dto.RoomExportFile{}, nil, utils.NewError("format must be one of ndjson, csv, html", utils.ErrorCodeBadRequest)
+	}
+
+	stream := func(w io.Writer) error {
+		return rs.writeHistory(context, room, newWriter(w), w)
+	}
+	return file, stream, nil
+}
+
+// writeHistory đọc lịch sử theo thời điểm gửi (cùng thời điểm thì theo message_id), mỗi lượt một trang, và flush sau mỗi trang
+func (rs *roomExportService) writeHistory(ctx context.Context, room sqlc.Room, writer roomExportWriter, w io.Writer) error {
+	if err := writer.begin(room); err != nil {
+		return err
+	}
+
+	count := 0
+	var afterCreatedAt *time.Time
+	afterID := int64(0)
+	for {
+		rows, err := rs.messageRepo.ListRoomMessagesForExport(ctx, sqlc.ListRoomMessagesForExportParams{
+			RoomID:         room.RoomID,
+			AfterCreatedAt: afterCreatedAt,
+			AfterID:        afterID,
+			Limit:          roomExportPageSize,
+		})
+		if err != nil {
+			return err
+		}
+
+		attachments, err := rs.attachmentsByMessage(ctx, rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range rows {
+			message := roomExportMessage{
+				MessageID:   row.MessageID,
+				CreatedAt:   row.MessageCreatedAt.UTC(),
+				SenderUUID:  row.UserUuid,
+				SenderName:  row.UserFullname,
+				Type:        row.MessageType,
+				Content:     row.Content,
+				Attachments: attachments[row.MessageID],
+			}
+			if message.Attachments == nil {
+				message.Attachments = []roomExportAttachment{}
+			}
+			if err := writer.write(message); err != nil {
+				return err
+			}
+		}
+		count += len(rows)
+
+		if err := writer.flush(); err != nil {
+			return err
+		}
+		if flusher, ok := w.(http.Flusher); ok {
+			flusher.Flush()
+		}
+
+		if len(rows) < roomExportPageSize {
+			break
+		}
+		last := rows[len(rows)-1]
+		afterCreatedAt, afterID = &last.MessageCreatedAt, last.MessageID
+	}
+
+	return writer.end(count)
+}
+
+func (rs *roomExportService) attachmentsByMessage(ctx context.Context, rows []sqlc.ListRoomMessagesForExportRow) (map[int64][]roomExportAttachment, error) {
+	result := make(map[int64][]roomExportAttachment)
+	if len(rows) == 0 {
+		return result, nil
+	}
+
+	messageIDs := make([]int64, len(rows))
+	for i, row := range rows {
+		messageIDs[i] = row.MessageID
+	}
+
+	attachments, err := rs.attachmentRepo.ListAttachmentsByMessageIDs(ctx, messageIDs)
+	if err != nil {
+		return nil, err
+	}
+	for _, a := range attachments {
+		result[a.MessageID] = append(result[a.MessageID], roomExportAttachment{
+			AttachmentID: a.AttachmentID,
+			FileName:     a.FileName,
+			ContentType:  a.ContentType,
+			SizeBytes:    a.SizeBytes,
+		})
+	}
+	return result, nil
+}
+
+// ndjsonRoomExport: mỗi tin nhắn một dòng JSON
+type ndjsonRoomExport struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonRoomExport) begin(room sqlc.Room) error { return nil }
+
+func (e *ndjsonRoomExport) write(message roomExportMessage) error {
+	return e.enc.Encode(message)
+}
+
+func (e *ndjsonRoomExport) flush() error { return nil }
+
+func (e *ndjsonRoomExport) end(count int) error { return nil }
+
+// csvRoomExport: file đính kèm được gộp vào một cột, ngăn cách bằng "; "
+type csvRoomExport struct {
+	w *csv.Writer
+}
+
+func (e *csvRoomExport) begin(room sqlc.Room) error {
+	return e.w.Write([]string{"message_id", "created_at", "sender_uuid", "sender_name", "type", "content", "attachments"})
+}
+
+func (e *csvRoomExport) write(message roomExportMessage) error {
+	files := make([]string, len(message.Attachments))
+	for i, a := range message.Attachments {
+		files[i] = a.FileName
+	}
+	return e.w.Write([]string{
+		strconv.FormatInt(message.MessageID, 10),
+		message.CreatedAt.Format(time.RFC3339),
+		message.SenderUUID.String(),
+		csvSafe(message.SenderName),
+		message.Type,
+		csvSafe(message.Content),
+		csvSafe(strings.Join(files, "; ")),
+	})
+}
+
+func (e *csvRoomExport) flush() error {
+	e.w.Flush()
+	return e.w.Error()
+}
+
+func (e *csvRoomExport) end(count int) error { return e.flush() }
+
+// htmlRoomExport: một file HTML tự chứa (CSS inline, không tải tài nguyên ngoài)
+type htmlRoomExport struct {
+	w io.Writer
+}
+
+func (e *htmlRoomExport) begin(room sqlc.Room) error {
+	name := room.RoomCode
+	if room.RoomName != nil && *room.RoomName != "" {
+		name = *room.RoomName
+	}
+	return templates.RenderExport(e.w, "room_header", map[string]any{
+		"RoomName":   name,
+		"RoomCode":   room.RoomCode,
+		"ExportedAt": time.Now().UTC().Format("2006-01-02 15:04"),
+	})
+}
+
+func (e *htmlRoomExport) write(message roomExportMessage) error {
+	return templates.RenderExport(e.w, "room_message", map[string]any{
+		"MessageID":   message.MessageID,
+		"Sender":      message.SenderName,
+		"CreatedAt":   message.CreatedAt.Format("2006-01-02 15:04:05"),
+		"Type":        message.Type,
+		"Content":     message.Content,
+		"Attachments": message.Attachments,
+	})
+}
+
+func (e *htmlRoomExport) flush() error { return nil }
+
+func (e *htmlRoomExport) end(count int) error {
+	return templates.RenderExport(e.w, "room_footer", map[string]any{"Count": count})
+}
+
+// csvSafe chặn CSV injection: ô bắt đầu bằng = + - @ bị bảng tính hiểu là công thức
+func csvSafe(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
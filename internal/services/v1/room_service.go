@@ -2,26 +2,53 @@ package services
 
 import (
 	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
 	"chat-app/internal/repository"
 	"chat-app/internal/utils"
 	"context"
 	"crypto/rand"
+	"errors"
+	"fmt"
 	"math/big"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
+)
+
+// Vai trò của thành viên trong phòng (khớp với chk_member_role)
+const (
+	RoomRoleOwner  = "Owner"
+	RoomRoleAdmin  = "Admin"
+	RoomRoleMember = "Member"
+)
+
+// Quyền gửi tin nhắn trong phòng (khớp với CHECK của room_posting_permission)
+const (
+	RoomPostingEveryone = "everyone"
+	RoomPostingAdmins   = "admins" // kênh thông báo: chỉ Owner/Admin được gửi
 )
 
 type roomService struct {
-	roomRepo repository.RoomRepository
-	userRepo repository.UserRepository
+	roomRepo     repository.RoomRepository
+	userRepo     repository.UserRepository
+	messageRepo  repository.MessageRepository
+	blockRepo    repository.BlockRepository
+	auditService AuditService
+	policy       EmailVerificationPolicy
 }
 
-func NewRoomService(roomRepo repository.RoomRepository, userRepo repository.UserRepository) RoomService {
+func NewRoomService(roomRepo repository.RoomRepository, userRepo repository.UserRepository, messageRepo repository.MessageRepository, blockRepo repository.BlockRepository, auditService AuditService, policy EmailVerificationPolicy) RoomService {
 	return &roomService{
-		roomRepo: roomRepo,
-		userRepo: userRepo,
+		roomRepo:     roomRepo,
+		userRepo:     userRepo,
+		messageRepo:  messageRepo,
+		blockRepo:    blockRepo,
+		auditService: auditService,
+		policy:       policy,
 	}
 }
 
@@ -60,8 +87,8 @@ func (rs *roomService) CreateRoom(ctx *gin.Context, name string, isDirectChat bo
 		return sqlc.Room{}, utils.WrapError(err, "could not create room", utils.ErrorCodeInternalServer)
 	}
 
-	// Thêm người tạo vào phòng
-	_, err = rs.roomRepo.JoinRoom(context, creatorUUID, room.RoomID)
+	// Thêm người tạo vào phòng với vai trò Owner
+	_, err = rs.roomRepo.AddRoomMember(context, creatorUUID, room.RoomID, RoomRoleOwner)
 
 	if err != nil {
 		return sqlc.Room{}, utils.WrapError(err, "could not add creator to room", utils.ErrorCodeInternalServer)
@@ -90,6 +117,16 @@ func (rs *roomService) JoinRoom(ctx *gin.Context, roomCode string, userUUID uuid
 		return room, nil // Người dùng đã trong phòng
 	}
 
+	if rs.policy.RequireForJoinRooms {
+		if err := ensureEmailVerified(context, rs.userRepo, userUUID, "joining rooms"); err != nil {
+			return sqlc.Room{}, err
+		}
+	}
+
+	if err := rs.ensureNotBlocked(context, room, userUUID); err != nil {
+		return sqlc.Room{}, err
+	}
+
 	// Thêm người dùng vào phòng
 	_, err = rs.roomRepo.JoinRoom(context, userUUID, room.RoomID)
 
@@ -119,6 +156,16 @@ func (rs *roomService) JoinRoomByID(ctx *gin.Context, roomID int64, userUUID uui
 		return room, nil // Người dùng đã trong phòng
 	}
 
+	if rs.policy.RequireForJoinRooms {
+		if err := ensureEmailVerified(context, rs.userRepo, userUUID, "joining rooms"); err != nil {
+			return sqlc.Room{}, err
+		}
+	}
+
+	if err := rs.ensureNotBlocked(context, room, userUUID); err != nil {
+		return sqlc.Room{}, err
+	}
+
 	// Thêm người dùng vào phòng
 	_, err = rs.roomRepo.JoinRoom(context, userUUID, roomID)
 	if err != nil {
@@ -142,11 +189,26 @@ func (rs *roomService) LeaveRoom(ctx *gin.Context, roomID int64, userUUID uuid.U
 	}
 
 	// Xóa người dùng khỏi phòng
-	err = rs.roomRepo.LeaveRoom(context, userUUID, roomID)
+	newOwner, err := rs.roomRepo.LeaveRoom(context, userUUID, roomID)
 	if err != nil {
 		return utils.WrapError(err, "could not remove user from room", utils.ErrorCodeInternalServer)
 	}
 
+	// Owner cuối cùng rời phòng, quyền Owner đã được trigger chuyển cho người kế nhiệm
+	if newOwner != nil {
+		rs.auditService.Record(ctx, AuditEvent{
+			Action:     v1Dto.AuditActionRoomOwnershipChanged,
+			ActorUUID:  &userUUID,
+			TargetType: v1Dto.AuditTargetRoom,
+			TargetID:   strconv.FormatInt(roomID, 10),
+			Metadata: map[string]any{
+				"previous_owner_uuid": userUUID,
+				"new_owner_uuid":      newOwner.UserUuid,
+				"reason":              "owner_left",
+			},
+		})
+	}
+
 	return nil
 }
 
@@ -161,10 +223,10 @@ func (rs *roomService) GetUserRooms(ctx *gin.Context, userUUID uuid.UUID) ([]sql
 	return rooms, nil
 }
 
-func (rs *roomService) GetUserRoomsWithLastMessage(ctx *gin.Context, userUUID uuid.UUID) ([]sqlc.ListUserRoomsWithLastMessageRow, error) {
+func (rs *roomService) GetUserRoomsWithLastMessage(ctx *gin.Context, userUUID uuid.UUID, includeArchived bool) ([]sqlc.ListUserRoomsWithLastMessageRow, error) {
 	context := ctx.Request.Context()
 
-	rooms, err := rs.roomRepo.ListUserRoomsWithLastMessage(context, userUUID)
+	rooms, err := rs.roomRepo.ListUserRoomsWithLastMessage(context, userUUID, includeArchived)
 	if err != nil {
 		return nil, utils.WrapError(err, "could not get user rooms with last message", utils.ErrorCodeInternalServer)
 	}
@@ -172,6 +234,326 @@ func (rs *roomService) GetUserRoomsWithLastMessage(ctx *gin.Context, userUUID uu
 	return rooms, nil
 }
 
+// GetRoomDetails trả về thông tin phòng cho thành viên của phòng
+func (rs *roomService) GetRoomDetails(ctx *gin.Context, roomID int64, userUUID uuid.UUID) (sqlc.Room, error) {
+	context := ctx.Request.Context()
+
+	isMember, err := rs.roomRepo.IsUserMemberOfRoom(context, userUUID, roomID)
+	if err != nil {
+		return sqlc.Room{}, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+
+	if !isMember {
+		return sqlc.Room{}, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+	}
+
+	room, err := rs.roomRepo.GetRoomByID(context, roomID)
+	if err != nil {
+		return sqlc.Room{}, utils.NewError("room not found", utils.ErrorCodeNotFound)
+	}
+
+	return room, nil
+}
+
+// UpdateRoomSettings cập nhật tên, chủ đề, mô tả, ảnh đại diện của phòng (chỉ Owner/Admin).
+// Mỗi thay đổi sinh ra một tin nhắn hệ thống trong phòng.
+func (rs *roomService) UpdateRoomSettings(ctx *gin.Context, roomID int64, actorUUID uuid.UUID, input v1Dto.UpdateRoomInput) (sqlc.Room, []sqlc.Message, error) {
+	context := ctx.Request.Context()
+
+	if _, err := rs.requireRoomRole(context, roomID, actorUUID, RoomRoleOwner, RoomRoleAdmin); err != nil {
+		return sqlc.Room{}, nil, err
+	}
+
+	if input.RoomName != nil {
+		name := strings.TrimSpace(*input.RoomName)
+		if name == "" {
+			return sqlc.Room{}, nil, utils.NewError("room name cannot be empty", utils.ErrorCodeBadRequest)
+		}
+		input.RoomName = &name
+	}
+
+	current, err := rs.roomRepo.GetRoomByID(context, roomID)
+	if err != nil {
+		return sqlc.Room{}, nil, utils.NewError("room not found", utils.ErrorCodeNotFound)
+	}
+
+	if current.RoomArchivedAt != nil {
+		return sqlc.Room{}, nil, utils.NewError("room is archived and read-only", utils.ErrorCodeForbidden)
+	}
+
+	actor, err := rs.userRepo.GetUserByUUID(context, actorUUID)
+	if err != nil {
+		return sqlc.Room{}, nil, utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	// Chỉ giữ lại các trường thực sự thay đổi
+	var changes []string
+	if changed(input.RoomName, current.RoomName) {
+		changes = append(changes, fmt.Sprintf("%s renamed the room to \"%s\"", actor.UserFullname, *input.RoomName))
+	} else {
+		input.RoomName = nil
+	}
+	if changed(input.RoomTopic, current.RoomTopic) {
+		if *input.RoomTopic == "" {
+			changes = append(changes, fmt.Sprintf("%s cleared the room topic", actor.UserFullname))
+		} else {
+			changes = append(changes, fmt.Sprintf("%s changed the topic to \"%s\"", actor.UserFullname, *input.RoomTopic))
+		}
+	} else {
+		input.RoomTopic = nil
+	}
+	if changed(input.RoomDescription, current.RoomDescription) {
+		changes = append(changes, fmt.Sprintf("%s updated the room description", actor.UserFullname))
+	} else {
+		input.RoomDescription = nil
+	}
+	if changed(input.RoomAvatarURL, current.RoomAvatarUrl) {
+		changes = append(changes, fmt.Sprintf("%s changed the room avatar", actor.UserFullname))
+	} else {
+		input.RoomAvatarURL = nil
+	}
+	if input.RoomPostingPermission != nil && *input.RoomPostingPermission != current.RoomPostingPermission {
+		if *input.RoomPostingPermission == RoomPostingAdmins {
+			changes = append(changes, fmt.Sprintf("%s made the room announcement-only", actor.UserFullname))
+		} else {
+			changes = append(changes, fmt.Sprintf("%s allowed everyone to post", actor.UserFullname))
+		}
+	} else {
+		input.RoomPostingPermission = nil
+	}
+	if input.RoomSlowModeSeconds != nil && *input.RoomSlowModeSeconds != current.RoomSlowModeSeconds {
+		if *input.RoomSlowModeSeconds == 0 {
+			changes = append(changes, fmt.Sprintf("%s turned off slow mode", actor.UserFullname))
+		} else {
+			changes = append(changes, fmt.Sprintf("%s set slow mode to %d seconds", actor.UserFullname, *input.RoomSlowModeSeconds))
+		}
+	} else {
+		input.RoomSlowModeSeconds = nil
+	}
+
+	if len(changes) == 0 {
+		return current, []sqlc.Message{}, nil
+	}
+
+	systemMessages := make([]sqlc.CreateSystemMessageParams, 0, len(changes))
+	for _, content := range changes {
+		systemMessages = append(systemMessages, sqlc.CreateSystemMessageParams{
+			RoomID:   roomID,
+			UserUuid: actorUUID,
+			Content:  content,
+		})
+	}
+
+	room, messages, err := rs.roomRepo.UpdateRoomSettings(context, sqlc.UpdateRoomSettingsParams{
+		RoomName:              input.RoomName,
+		RoomTopic:             input.RoomTopic,
+		RoomDescription:       input.RoomDescription,
+		RoomAvatarUrl:         input.RoomAvatarURL,
+		RoomPostingPermission: input.RoomPostingPermission,
+		RoomSlowModeSeconds:   input.RoomSlowModeSeconds,
+		RoomID:                roomID,
+	}, systemMessages)
+	if err != nil {
+		return sqlc.Room{}, nil, utils.WrapError(err, "could not update room", utils.ErrorCodeInternalServer)
+	}
+
+	return room, messages, nil
+}
+
+// TransferOwnership chuyển quyền Owner cho một thành viên khác, Owner cũ trở thành Admin
+func (rs *roomService) TransferOwnership(ctx *gin.Context, roomID int64, ownerUUID, newOwnerUUID uuid.UUID) (sqlc.Message, error) {
+	context := ctx.Request.Context()
+
+	if ownerUUID == newOwnerUUID {
+		return sqlc.Message{}, utils.NewError("you already own this room", utils.ErrorCodeBadRequest)
+	}
+
+	if _, err := rs.requireRoomRole(context, roomID, ownerUUID, RoomRoleOwner); err != nil {
+		return sqlc.Message{}, err
+	}
+
+	isMember, err := rs.roomRepo.IsUserMemberOfRoom(context, newOwnerUUID, roomID)
+	if err != nil {
+		return sqlc.Message{}, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+
+	if !isMember {
+		return sqlc.Message{}, utils.NewError("new owner must be a member of this room", utils.ErrorCodeBadRequest)
+	}
+
+	if err := rs.roomRepo.TransferRoomOwnership(context, roomID, ownerUUID, newOwnerUUID); err != nil {
+		return sqlc.Message{}, utils.WrapError(err, "could not transfer room ownership", utils.ErrorCodeInternalServer)
+	}
+
+	owner, err := rs.userRepo.GetUserByUUID(context, ownerUUID)
+	if err != nil {
+		return sqlc.Message{}, utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	newOwner, err := rs.userRepo.GetUserByUUID(context, newOwnerUUID)
+	if err != nil {
+		return sqlc.Message{}, utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	message, err := rs.messageRepo.CreateSystemMessage(context, sqlc.CreateSystemMessageParams{
+		RoomID:   roomID,
+		UserUuid: ownerUUID,
+		Content:  fmt.Sprintf("%s transferred room ownership to %s", owner.UserFullname, newOwner.UserFullname),
+	})
+	if err != nil {
+		return sqlc.Message{}, utils.WrapError(err, "could not create system message", utils.ErrorCodeInternalServer)
+	}
+
+	rs.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionRoomOwnershipChanged,
+		ActorUUID:  &ownerUUID,
+		TargetType: v1Dto.AuditTargetRoom,
+		TargetID:   strconv.FormatInt(roomID, 10),
+		Metadata: map[string]any{
+			"previous_owner_uuid":       ownerUUID,
+			"previous_owner_demoted_to": RoomRoleAdmin,
+			"new_owner_uuid":            newOwnerUUID,
+		},
+	})
+	return message, nil
+}
+
+// DeleteOwnedRoom cho phép Owner xóa phòng của mình (tin nhắn bị xóa theo ON DELETE CASCADE)
+func (rs *roomService) DeleteOwnedRoom(ctx *gin.Context, roomID int64, ownerUUID uuid.UUID) error {
+	context := ctx.Request.Context()
+
+	if _, err := rs.requireRoomRole(context, roomID, ownerUUID, RoomRoleOwner); err != nil {
+		return err
+	}
+
+	return rs.deleteRoom(ctx, roomID, &ownerUUID)
+}
+
+// SetRoomArchived lưu trữ (chỉ đọc) hoặc mở lại phòng (chỉ Owner)
+func (rs *roomService) SetRoomArchived(ctx *gin.Context, roomID int64, ownerUUID uuid.UUID, archived bool) (sqlc.Room, sqlc.Message, error) {
+	context := ctx.Request.Context()
+
+	if _, err := rs.requireRoomRole(context, roomID, ownerUUID, RoomRoleOwner); err != nil {
+		return sqlc.Room{}, sqlc.Message{}, err
+	}
+
+	current, err := rs.roomRepo.GetRoomByID(context, roomID)
+	if err != nil {
+		return sqlc.Room{}, sqlc.Message{}, utils.NewError("room not found", utils.ErrorCodeNotFound)
+	}
+
+	if archived == (current.RoomArchivedAt != nil) {
+		if archived {
+			return sqlc.Room{}, sqlc.Message{}, utils.NewError("room is already archived", utils.ErrorCodeConflict)
+		}
+		return sqlc.Room{}, sqlc.Message{}, utils.NewError("room is not archived", utils.ErrorCodeConflict)
+	}
+
+	var room sqlc.Room
+	if archived {
+		room, err = rs.roomRepo.ArchiveRoom(context, roomID)
+	} else {
+		room, err = rs.roomRepo.UnarchiveRoom(context, roomID)
+	}
+	if err != nil {
+		return sqlc.Room{}, sqlc.Message{}, utils.WrapError(err, "could not update room archive state", utils.ErrorCodeInternalServer)
+	}
+
+	owner, err := rs.userRepo.GetUserByUUID(context, ownerUUID)
+	if err != nil {
+		return sqlc.Room{}, sqlc.Message{}, utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+
+	content := fmt.Sprintf("%s archived the room", owner.UserFullname)
+	if !archived {
+		content = fmt.Sprintf("%s unarchived the room", owner.UserFullname)
+	}
+
+	message, err := rs.messageRepo.CreateSystemMessage(context, sqlc.CreateSystemMessageParams{
+		RoomID:   roomID,
+		UserUuid: ownerUUID,
+		Content:  content,
+	})
+	if err != nil {
+		return sqlc.Room{}, sqlc.Message{}, utils.WrapError(err, "could not create system message", utils.ErrorCodeInternalServer)
+	}
+
+	return room, message, nil
+}
+
+// UpdateNotificationSettings đổi mức thông báo và thời gian tắt thông báo của user với phòng
+func (rs *roomService) UpdateNotificationSettings(ctx *gin.Context, roomID int64, userUUID uuid.UUID, input v1Dto.UpdateNotificationSettingsInput) (sqlc.RoomMember, error) {
+	context := ctx.Request.Context()
+
+	if input.MutedUntil != nil && !input.MutedUntil.After(time.Now()) {
+		return sqlc.RoomMember{}, utils.NewError("muted_until must be in the future", utils.ErrorCodeBadRequest)
+	}
+
+	member, err := rs.roomRepo.UpdateRoomMemberNotificationSettings(context, sqlc.UpdateRoomMemberNotificationSettingsParams{
+		MemberNotificationLevel: input.NotificationLevel,
+		MemberMutedUntil:        input.MutedUntil,
+		UserUuid:                userUUID,
+		RoomID:                  roomID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.RoomMember{}, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+		}
+		return sqlc.RoomMember{}, utils.WrapError(err, "could not update notification settings", utils.ErrorCodeInternalServer)
+	}
+
+	return member, nil
+}
+
+// requireRoomRole kiểm tra user là thành viên phòng và có một trong các vai trò cho phép
+func (rs *roomService) requireRoomRole(ctx context.Context, roomID int64, userUUID uuid.UUID, roles ...string) (sqlc.RoomMember, error) {
+	member, err := rs.roomRepo.GetRoomMember(ctx, userUUID, roomID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.RoomMember{}, utils.NewError("user is not a member of this room", utils.ErrorCodeForbidden)
+		}
+		return sqlc.RoomMember{}, utils.WrapError(err, "could not check room membership", utils.ErrorCodeInternalServer)
+	}
+
+	for _, role := range roles {
+		if member.MemberRole == role {
+			return member, nil
+		}
+	}
+
+	if len(roles) == 1 && roles[0] == RoomRoleOwner {
+		return sqlc.RoomMember{}, utils.NewError("only room owner can perform this action", utils.ErrorCodeForbidden)
+	}
+	return sqlc.RoomMember{}, utils.NewError("only room owner or admin can perform this action", utils.ErrorCodeForbidden)
+}
+
+// ensureNotBlocked không cho user vào phòng chat riêng với người đã chặn mình hoặc bị mình chặn
+func (rs *roomService) ensureNotBlocked(ctx context.Context, room sqlc.Room, userUUID uuid.UUID) error {
+	if !room.RoomIsDirectChat {
+		return nil
+	}
+
+	blocked, err := rs.blockRepo.HasBlockWithRoomMembers(ctx, userUUID, room.RoomID)
+	if err != nil {
+		return utils.WrapError(err, "could not check blocked users", utils.ErrorCodeInternalServer)
+	}
+	if blocked {
+		return utils.NewError("you cannot start a direct chat with this user", utils.ErrorCodeForbidden)
+	}
+	return nil
+}
+
+// changed trả về true nếu giá trị mới được gửi lên và khác giá trị hiện tại
+func changed(next, current *string) bool {
+	if next == nil {
+		return false
+	}
+	if current == nil {
+		return *next != ""
+	}
+	return *next != *current
+}
+
 func (rs *roomService) GetRoomMembers(ctx *gin.Context, roomID int64) ([]sqlc.User, error) {
 	context := ctx.Request.Context()
 
@@ -209,13 +591,33 @@ func (rs *roomService) GetRoomByID(ctx *gin.Context, roomID int64) (sqlc.Room, e
 	return room, nil
 }
 
+// DeleteRoom xóa phòng bất kỳ, dùng cho admin (người thực hiện lấy từ request)
 func (rs *roomService) DeleteRoom(ctx *gin.Context, roomID int64) error {
+	return rs.deleteRoom(ctx, roomID, nil)
+}
+
+// deleteRoom xóa phòng và ghi audit log kèm mã, tên phòng (đọc trước khi xóa)
+func (rs *roomService) deleteRoom(ctx *gin.Context, roomID int64, actorUUID *uuid.UUID) error {
 	context := ctx.Request.Context()
 
+	metadata := map[string]any{}
+	if room, err := rs.roomRepo.GetRoomByID(context, roomID); err == nil {
+		metadata["room_code"] = room.RoomCode
+		metadata["room_name"] = room.RoomName
+		metadata["is_direct_chat"] = room.RoomIsDirectChat
+	}
+
 	err := rs.roomRepo.DeleteRoom(context, roomID)
 	if err != nil {
 		return utils.WrapError(err, "could not delete room", utils.ErrorCodeInternalServer)
 	}
 
+	rs.auditService.Record(ctx, AuditEvent{
+		Action:     v1Dto.AuditActionRoomDeleted,
+		ActorUUID:  actorUUID,
+		TargetType: v1Dto.AuditTargetRoom,
+		TargetID:   strconv.FormatInt(roomID, 10),
+		Metadata:   metadata,
+	})
 	return nil
 }
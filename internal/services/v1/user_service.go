@@ -2,13 +2,18 @@ package services
 
 import (
 	"chat-app/internal/db/sqlc"
+	v1Dto "chat-app/internal/dto/v1"
 	"chat-app/internal/repository"
 	"chat-app/internal/utils"
 	"context"
+	"encoding/base64"
+	"encoding/json"
 	"errors"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
+	"github.com/jackc/pgx/v5"
 	"github.com/jackc/pgx/v5/pgconn"
 	"golang.org/x/crypto/bcrypt"
 )
@@ -92,20 +97,148 @@ func (us *userService) GetAllUsers(ctx *gin.Context, limit, offset int32) ([]sql
 	return users, nil
 }
 
-func (us *userService) DeleteUser(ctx *gin.Context, userUUID string) error {
+// EnsureAccountActive trả về lỗi nếu tài khoản không tồn tại, đã bị xóa, vô hiệu hóa hoặc đang bị tạm khóa
+func (us *userService) EnsureAccountActive(ctx context.Context, userUUID uuid.UUID) error {
+	user, err := us.userRepo.GetUserByUUID(ctx, userUUID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return utils.NewError("account not found", utils.ErrorCodeUnauthorized)
+		}
+		return utils.WrapError(err, "could not get user", utils.ErrorCodeInternalServer)
+	}
+	return accountStatusError(user)
+}
+
+// UpdateProfile cập nhật hồ sơ của user, chỉ các trường được gửi lên
+func (us *userService) UpdateProfile(ctx *gin.Context, userUUID uuid.UUID, input v1Dto.UpdateProfileInput) (sqlc.User, error) {
 	context := ctx.Request.Context()
 
-	// Parse UUID
-	uuid, err := parseUUID(userUUID)
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return sqlc.User{}, utils.NewError("full name cannot be empty", utils.ErrorCodeBadRequest)
+		}
+		input.Name = &name
+	}
+	if input.Bio != nil {
+		bio := strings.TrimSpace(*input.Bio)
+		input.Bio = &bio
+	}
+
+	user, err := us.userRepo.UpdateUserProfile(context, sqlc.UpdateUserProfileParams{
+		UserFullname:     input.Name,
+		UserAvatarUrl:    input.AvatarURL,
+		UserBio:          input.Bio,
+		UserTimezone:     input.Timezone,
+		UserLocale:       input.Locale,
+		UserDiscoverable: input.Discoverable,
+		UserUuid:         userUUID,
+	})
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return sqlc.User{}, utils.NewError("user not found", utils.ErrorCodeNotFound)
+		}
+		return sqlc.User{}, utils.WrapError(err, "could not update profile", utils.ErrorCodeInternalServer)
+	}
+
+	return user, nil
+}
+
+// ListSharedRoomIDs trả về các phòng mà user đang ở cùng ít nhất một người khác
+func (us *userService) ListSharedRoomIDs(ctx context.Context, userUUID uuid.UUID) ([]int64, error) {
+	roomIDs, err := us.userRepo.ListSharedRoomIDs(ctx, userUUID)
+	if err != nil {
+		return nil, utils.WrapError(err, "could not get shared rooms", utils.ErrorCodeInternalServer)
+	}
+	return roomIDs, nil
+}
+
+// directoryCursor là vị trí của bản ghi cuối trang trước, được mã hóa base64 trong next_cursor
+type directoryCursor struct {
+	Rank int32     `json:"r"`
+	Name string    `json:"n"`
+	UUID uuid.UUID `json:"u"`
+}
+
+// SearchDirectory tìm người dùng theo tên/email để bắt đầu chat riêng hoặc mời vào phòng.
+// Bỏ qua user đã ngừng hoạt động, user tắt discoverable và chính người tìm.
+func (us *userService) SearchDirectory(ctx *gin.Context, requesterUUID uuid.UUID, query v1Dto.UserDirectoryQuery) ([]v1Dto.UserProfileDTO, v1Dto.DirectoryPagination, error) {
+	context := ctx.Request.Context()
+
+	term := utils.NormalizeString(query.Query)
+	if len([]rune(term)) < 2 {
+		return nil, v1Dto.DirectoryPagination{}, utils.NewError("search query must have at least 2 characters", utils.ErrorCodeBadRequest)
+	}
+	if query.Limit == 0 {
+		query.Limit = 20
+	}
+
+	params := sqlc.SearchUserDirectoryParams{
+		Prefix:        escapeLike(term) + "%",
+		RequesterUuid: requesterUUID,
+		Query:         term,
+		Limit:         query.Limit + 1, // Lấy thêm 1 bản ghi để biết còn trang sau hay không
+	}
+	if query.Cursor != "" {
+		cursor, err := decodeDirectoryCursor(query.Cursor)
+		if err != nil {
+			return nil, v1Dto.DirectoryPagination{}, utils.NewError("invalid cursor", utils.ErrorCodeBadRequest)
+		}
+		afterUUID := cursor.UUID.String()
+		params.AfterRank = &cursor.Rank
+		params.AfterName = &cursor.Name
+		params.AfterUuid = &afterUUID
+	}
+
+	rows, err := us.userRepo.SearchUserDirectory(context, params)
 	if err != nil {
-		return utils.NewError("invalid user ID", utils.ErrorCodeBadRequest)
+		return nil, v1Dto.DirectoryPagination{}, utils.WrapError(err, "could not search users", utils.ErrorCodeInternalServer)
+	}
+
+	pagination := v1Dto.DirectoryPagination{Limit: query.Limit}
+	if len(rows) > int(query.Limit) {
+		rows = rows[:query.Limit]
+		last := rows[len(rows)-1]
+		nextCursor := encodeDirectoryCursor(directoryCursor{
+			Rank: last.MatchRank,
+			Name: last.SortName,
+			UUID: last.UserUuid,
+		})
+		pagination.HasMore = true
+		pagination.NextCursor = &nextCursor
+	}
+
+	results := make([]v1Dto.UserProfileDTO, 0, len(rows))
+	for _, row := range rows {
+		results = append(results, v1Dto.UserProfileDTO{
+			UUID:      row.UserUuid.String(),
+			Name:      row.UserFullname,
+			AvatarURL: row.UserAvatarUrl,
+			Bio:       row.UserBio,
+			Timezone:  row.UserTimezone,
+			Locale:    row.UserLocale,
+		})
 	}
 
-	// Delete user
-	err = us.userRepo.DeleteUser(context, uuid)
+	return results, pagination, nil
+}
+
+func encodeDirectoryCursor(cursor directoryCursor) string {
+	data, _ := json.Marshal(cursor)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+func decodeDirectoryCursor(value string) (directoryCursor, error) {
+	var cursor directoryCursor
+	data, err := base64.RawURLEncoding.DecodeString(value)
 	if err != nil {
-		return utils.WrapError(err, "could not delete user", utils.ErrorCodeInternalServer)
+		return cursor, err
 	}
+	err = json.Unmarshal(data, &cursor)
+	return cursor, err
+}
 
-	return nil
+// escapeLike escape các ký tự đặc biệt của LIKE để tìm đúng chuỗi người dùng nhập
+func escapeLike(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
 }
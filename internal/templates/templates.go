@@ -0,0 +1,39 @@
+package templates
+
+import (
+	"bytes"
+	"embed"
+	htmltemplate "html/template"
+	"io"
+	texttemplate "text/template"
+)
+
+//go:embed email/*.tmpl
+var emailFS embed.FS
+
+//go:embed export/*.tmpl
+var exportFS embed.FS
+
+var (
+	emailText  = texttemplate.Must(texttemplate.ParseFS(emailFS, "email/*.txt.tmpl"))
+	emailHTML  = htmltemplate.Must(htmltemplate.ParseFS(emailFS, "email/*.html.tmpl"))
+	exportHTML = htmltemplate.Must(htmltemplate.ParseFS(exportFS, "export/*.html.tmpl"))
+)
+
+// RenderEmail render bản text (email/<name>.txt.tmpl) và HTML (email/<name>.html.tmpl) của một email
+func RenderEmail(name string, data any) (text string, html string, err error) {
+	var textBuf, htmlBuf bytes.Buffer
+	if err := emailText.ExecuteTemplate(&textBuf, name+".txt.tmpl", data); err != nil {
+		return "", "", err
+	}
+	if err := emailHTML.ExecuteTemplate(&htmlBuf, name+".html.tmpl", data); err != nil {
+		return "", "", err
+	}
+	return textBuf.String(), htmlBuf.String(), nil
+}
+
+// RenderExport ghi một phần của file HTML xuất dữ liệu (vd: "room_message" trong export/room.html.tmpl) thẳng vào w,
+// để file lớn được stream theo từng đoạn
+func RenderExport(w io.Writer, name string, data any) error {
+	return exportHTML.ExecuteTemplate(w, name, data)
+}
@@ -2,6 +2,8 @@ package utils
 
 import (
 	"net/http"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -20,9 +22,10 @@ const (
 )
 
 type AppError struct {
-	Message string
-	Code    ErrorCode
-	Err     error
+	Message    string
+	Code       ErrorCode
+	Err        error
+	RetryAfter time.Duration // > 0: client được thử lại sau khoảng thời gian này (header Retry-After)
 }
 type APIResponse struct {
 	Status     string `json:"status"`
@@ -52,6 +55,20 @@ func WrapError(err error, message string, code ErrorCode) error {
 	}
 }
 
+// sử dụng khi : bị giới hạn tần suất và biết thời gian phải chờ (slow mode...)
+func NewRetryAfterError(message string, retryAfter time.Duration) error {
+	return &AppError{
+		Message:    message,
+		Code:       ErrorCodeTooManyRequests,
+		RetryAfter: retryAfter,
+	}
+}
+
+// RetryAfterSeconds làm tròn lên số giây phải chờ, dùng cho header Retry-After và trường retry_after
+func (ae *AppError) RetryAfterSeconds() int {
+	return int((ae.RetryAfter + time.Second - 1) / time.Second)
+}
+
 // ResponseError phân tích lỗi và trả về mã trạng thái HTTP tương ứng (trong handler)
 func ResponseError(c *gin.Context, err error) {
 	if appErr, ok := err.(*AppError); ok {
@@ -63,6 +80,9 @@ func ResponseError(c *gin.Context, err error) {
 		if appErr.Err != nil {
 			response["details"] = appErr.Err.Error() // thêm thông tin chi tiết nếu có
 		}
+		if appErr.RetryAfter > 0 {
+			c.Header("Retry-After", strconv.Itoa(appErr.RetryAfterSeconds()))
+		}
 		c.JSON(status, response)
 		return
 	}
@@ -1,12 +1,15 @@
 package validation
 
 import (
+	"net/url"
 	"path/filepath"
 	"regexp"
 	"strings"
+	_ "time/tzdata" // dữ liệu múi giờ cho tag timezone khi máy chủ không có /usr/share/zoneinfo
 	"chat-app/internal/utils"
 
 	"github.com/go-playground/validator/v10"
+	"golang.org/x/text/language"
 )
 
 func RegisterCustomValidation(v *validator.Validate) error {
@@ -64,5 +67,19 @@ func RegisterCustomValidation(v *validator.Validate) error {
 		}
 		return false
 	})
+	// URL http(s) hoặc chuỗi rỗng (dùng để xóa giá trị)
+	v.RegisterValidation("url_or_empty", func(fl validator.FieldLevel) bool {
+		value := fl.Field().String()
+		if value == "" {
+			return true
+		}
+		u, err := url.Parse(value)
+		return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+	})
+	// locale theo BCP 47 vd: vi-VN, en
+	v.RegisterValidation("locale", func(fl validator.FieldLevel) bool {
+		_, err := language.Parse(fl.Field().String())
+		return err == nil
+	})
 	return nil
-}
\ No newline at end of file
+}
\ No newline at end of file
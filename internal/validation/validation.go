@@ -53,6 +53,14 @@ func HandleValidationError(err error) gin.H {
 				errors[e.Field()] = fmt.Sprintf("Trường %s phải đúng định dạng YYYY-MM-DD", e.Field())
 			case "password_strong":
 				errors[e.Field()] = fmt.Sprintf(" %s phải có ít nhất 8 kí tự phải (chữ thường,chữ hoa,số và kí tự đặc biệt)", e.Field())
+			case "url":
+				errors[e.Field()] = fmt.Sprintf("Trường %s phải là URL hợp lệ", e.Field())
+			case "url_or_empty":
+				errors[e.Field()] = fmt.Sprintf("Trường %s phải là URL http(s) hợp lệ hoặc để trống", e.Field())
+			case "timezone":
+				errors[e.Field()] = fmt.Sprintf("%s phải là tên múi giờ IANA hợp lệ (vd: Asia/Ho_Chi_Minh)", e.Field())
+			case "locale":
+				errors[e.Field()] = fmt.Sprintf("%s phải là mã ngôn ngữ BCP 47 hợp lệ (vd: vi-VN)", e.Field())
 			case "file_ext":
 				allowdValues := strings.Join(strings.Split(e.Param(), " "), ",")
 				errors[e.Field()] = fmt.Sprintf("Trường %s phải có phần mở rộng thuộc %s", e.Field(), allowdValues)
@@ -64,4 +72,4 @@ func HandleValidationError(err error) gin.H {
 		"error": "Validation failed- yêu cầu không hợp lệ " ,
 		"details": err.Error(),
 	}
-}
\ No newline at end of file
+}
\ No newline at end of file
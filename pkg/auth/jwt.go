@@ -13,12 +13,22 @@ var (
 	jwtSecret = []byte(utils.GetEnv("JWT_SECRET", "your_secret_key_here")) // Lấy secret key từ biến môi trường
 )
 
+const (
+	tokenTTL = 24 * time.Hour
+
+	// Key lưu thời điểm thu hồi token của user (nano giây), token phát hành trước thời điểm này bị từ chối
+	revokedBeforeKeyPrefix = "auth:revoked_before:"
+)
+
 // UserClaims chứa thông tin user trong JWT token
 type UserClaims struct {
 	UserUUID string `json:"user_uuid"`
 	Email    string `json:"email"`
 	Fullname string `json:"fullname"`
 	Role     string `json:"role"`
+	// Thời điểm phát hành tính bằng nano giây; iat chỉ có độ chính xác giây nên không đủ
+	// để phân biệt token cấp ngay sau khi thu hồi (cùng giây) với token cũ
+	IssuedAtNanos int64 `json:"iat_ns,omitempty"`
 	jwt.RegisteredClaims
 }
 
@@ -52,21 +62,41 @@ func (js *JWTService) ValidateJWTToken(tokenString string) (*UserClaims, error)
 		return nil, utils.NewError("invalid token", utils.ErrorCodeUnauthorized)
 	}
 
+	// Token phát hành trước lần thu hồi gần nhất không còn hiệu lực
+	var revokedBefore int64
+	if err := js.cache.Get(revokedBeforeKeyPrefix+claims.UserUUID, &revokedBefore); err != nil {
+		return nil, utils.WrapError(err, "could not check token revocation", utils.ErrorCodeInternalServer)
+	}
+	if revokedBefore > 0 && claims.issuedAtNanos() < revokedBefore {
+		return nil, utils.NewError("token has been revoked", utils.ErrorCodeUnauthorized)
+	}
+
 	return claims, nil
 }
 
+// RevokeUserTokens thu hồi mọi token đã phát hành cho user (vd: sau khi đổi mật khẩu).
+// Key chỉ cần tồn tại bằng thời hạn token, sau đó các token cũ đã tự hết hạn.
+func (js *JWTService) RevokeUserTokens(userUUID uuid.UUID) error {
+	if err := js.cache.Set(revokedBeforeKeyPrefix+userUUID.String(), time.Now().UnixNano(), tokenTTL); err != nil {
+		return utils.WrapError(err, "could not revoke tokens", utils.ErrorCodeInternalServer)
+	}
+	return nil
+}
+
 // GenerateToken creates a JWT token for a user
 func (js *JWTService) GenerateToken(userUUID uuid.UUID, email, fullname, role string) (string, error) {
-	expirationTime := time.Now().Add(24 * time.Hour)
+	now := time.Now()
+	expirationTime := now.Add(tokenTTL)
 
 	claims := &UserClaims{
-		UserUUID: userUUID.String(),
-		Email:    email,
-		Fullname: fullname,
-		Role:     role,
+		UserUUID:      userUUID.String(),
+		Email:         email,
+		Fullname:      fullname,
+		Role:          role,
+		IssuedAtNanos: now.UnixNano(),
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expirationTime),
-			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			IssuedAt:  jwt.NewNumericDate(now),
 			Subject:   userUUID.String(), // Standard JWT subject
 		},
 	}
@@ -79,3 +109,15 @@ func (js *JWTService) GenerateToken(userUUID uuid.UUID, email, fullname, role st
 
 	return tokenString, nil
 }
+
+// issuedAtNanos trả về thời điểm phát hành (nano giây), token không có iat_ns thì dùng iat.
+// Token không có cả hai coi như phát hành từ rất lâu để luôn bị từ chối khi đã thu hồi.
+func (c *UserClaims) issuedAtNanos() int64 {
+	if c.IssuedAtNanos > 0 {
+		return c.IssuedAtNanos
+	}
+	if c.IssuedAt != nil {
+		return c.IssuedAt.UnixNano()
+	}
+	return 0
+}
@@ -0,0 +1,90 @@
+package auth
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryCache giữ giá trị dạng JSON giống redisCacheService, bỏ qua thời hạn
+type memoryCache struct {
+	data map[string][]byte
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{data: make(map[string][]byte)}
+}
+
+func (mc *memoryCache) Set(key string, value any, expiration time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	mc.data[key] = data
+	return nil
+}
+
+func (mc *memoryCache) Get(key string, dest any) error {
+	data, ok := mc.data[key]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (mc *memoryCache) Clear(pattern string) error {
+	mc.data = make(map[string][]byte)
+	return nil
+}
+
+func (mc *memoryCache) Exists(key string) (bool, error) {
+	_, ok := mc.data[key]
+	return ok, nil
+}
+
+func TestRevokeThenLoginImmediately(t *testing.T) {
+	js := NewJWTService(newMemoryCache())
+	userUUID := uuid.New()
+
+	oldToken, err := js.GenerateToken(userUUID, "a@example.com", "A", "user")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if _, err := js.ValidateJWTToken(oldToken); err != nil {
+		t.Fatalf("token before revoke should be valid: %v", err)
+	}
+
+	if err := js.RevokeUserTokens(userUUID); err != nil {
+		t.Fatalf("RevokeUserTokens: %v", err)
+	}
+	// Đăng nhập lại ngay, thường vẫn trong cùng giây với lần thu hồi
+	newToken, err := js.GenerateToken(userUUID, "a@example.com", "A", "user")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+
+	if _, err := js.ValidateJWTToken(oldToken); err == nil {
+		t.Error("token issued before revoke should be rejected")
+	}
+	if _, err := js.ValidateJWTToken(newToken); err != nil {
+		t.Errorf("token issued right after revoke should be valid: %v", err)
+	}
+}
+
+func TestRevokeOnlyAffectsThatUser(t *testing.T) {
+	js := NewJWTService(newMemoryCache())
+	revoked, other := uuid.New(), uuid.New()
+
+	otherToken, err := js.GenerateToken(other, "b@example.com", "B", "user")
+	if err != nil {
+		t.Fatalf("GenerateToken: %v", err)
+	}
+	if err := js.RevokeUserTokens(revoked); err != nil {
+		t.Fatalf("RevokeUserTokens: %v", err)
+	}
+	if _, err := js.ValidateJWTToken(otherToken); err != nil {
+		t.Errorf("other user's token should stay valid: %v", err)
+	}
+}
@@ -0,0 +1,206 @@
+// Package chatimport đọc file export của Slack và Discord thành một dạng chung để import vào app.
+// Tin nhắn được đọc theo từng kênh và trả qua callback nên bộ nhớ không tăng theo độ dài lịch sử.
+package chatimport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+)
+
+const (
+	SourceSlack   = "slack"
+	SourceDiscord = "discord"
+)
+
+// maxJSONFileSize giới hạn dung lượng giải nén của một file JSON trong ZIP (chống zip bomb)
+const maxJSONFileSize = 512 << 20
+
+var (
+	// ErrUnsupportedSource trả về khi nguồn không phải slack hoặc discord
+	ErrUnsupportedSource = errors.New("chatimport: unsupported source")
+	// ErrInvalidArchive trả về khi file ZIP không đúng cấu trúc export của nguồn
+	ErrInvalidArchive = errors.New("chatimport: invalid export archive")
+)
+
+// User là một tài khoản trong workspace gốc, được ghép với user của app qua email
+type User struct {
+	ExternalID string
+	Email      string
+	Name       string
+}
+
+// Channel là một kênh/cuộc trò chuyện gốc, sẽ thành một phòng
+type Channel struct {
+	ExternalID string
+	Name       string
+	IsDirect   bool
+	CreatorID  string   // id gốc của người tạo, rỗng nếu nguồn không có
+	MemberIDs  []string // id gốc của thành viên, rỗng nếu nguồn không có
+
+	file string // thư mục (Slack) hoặc file (Discord) chứa tin nhắn trong ZIP
+}
+
+// Message là một tin nhắn văn bản đã chuyển về dạng text thuần
+type Message struct {
+	ExternalID string
+	AuthorID   string
+	Text       string
+	CreatedAt  time.Time
+}
+
+// Archive là một file export đã mở
+type Archive interface {
+	Users() []User
+	Channels() []Channel
+	// Messages đọc tin nhắn của channel theo thứ tự thời gian và gọi fn cho từng tin.
+	// Lỗi do fn trả về được trả nguyên cho người gọi.
+	Messages(channel Channel, fn func(Message) error) error
+}
+
+// Open mở file ZIP export của source ("slack" hoặc "discord")
+func Open(source string, r io.ReaderAt, size int64) (Archive, error) {
+	zr, err := zip.NewReader(r, size)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidArchive, err)
+	}
+
+	switch source {
+	case SourceSlack:
+		return openSlack(zr)
+	case SourceDiscord:
+		return openDiscord(zr)
+	default:
+		return nil, ErrUnsupportedSource
+	}
+}
+
+// findFile tìm file theo tên ở thư mục gốc của export (cho phép ZIP có thêm một thư mục bọc ngoài)
+func findFile(zr *zip.Reader, name string) *zip.File {
+	for _, f := range zr.File {
+		if f.Name == name {
+			return f
+		}
+	}
+	for _, f := range zr.File {
+		if path.Base(f.Name) == name && strings.Count(strings.TrimSuffix(f.Name, "/"), "/") == 1 {
+			return f
+		}
+	}
+	return nil
+}
+
+// decodeFile giải mã toàn bộ một file JSON trong ZIP vào v
+func decodeFile(f *zip.File, v any) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	if err := json.NewDecoder(io.LimitReader(rc, maxJSONFileSize)).Decode(v); err != nil {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, f.Name, err)
+	}
+	return nil
+}
+
+// errStopDecoding dừng decodeObject sớm mà không báo lỗi
+var errStopDecoding = errors.New("stop decoding")
+
+// decodeObject đọc lần lượt các key của object JSON ở gốc file.
+// handle phải đọc hết giá trị của key (hoặc trả false để bỏ qua).
+func decodeObject(f *zip.File, handle func(key string, dec *json.Decoder) (bool, error)) error {
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	dec := json.NewDecoder(io.LimitReader(rc, maxJSONFileSize))
+	invalid := func(err error) error {
+		return fmt.Errorf("%w: %s: %v", ErrInvalidArchive, f.Name, err)
+	}
+
+	if err := expectDelim(dec, '{'); err != nil {
+		return invalid(err)
+	}
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return invalid(err)
+		}
+		key, _ := tok.(string)
+
+		handled, err := handle(key, dec)
+		if err != nil {
+			if errors.Is(err, errStopDecoding) {
+				return nil
+			}
+			var fe *formatError
+			if errors.As(err, &fe) {
+				return invalid(fe.err)
+			}
+			return err
+		}
+		if !handled {
+			var skip json.RawMessage
+			if err := dec.Decode(&skip); err != nil {
+				return invalid(err)
+			}
+		}
+	}
+	return nil
+}
+
+// formatError đánh dấu lỗi do file JSON sai định dạng, phân biệt với lỗi do callback trả về
+type formatError struct {
+	err error
+}
+
+func (e *formatError) Error() string { return e.err.Error() }
+
+func (e *formatError) Unwrap() error { return e.err }
+
+// decodeValue đọc giá trị JSON tiếp theo trong dec vào v
+func decodeValue(dec *json.Decoder, v any) error {
+	if err := dec.Decode(v); err != nil {
+		return &formatError{err: err}
+	}
+	return nil
+}
+
+// decodeArray gọi fn cho từng phần tử của mảng JSON tiếp theo trong dec
+func decodeArray[T any](dec *json.Decoder, fn func(T) error) error {
+	if err := expectDelim(dec, '['); err != nil {
+		return &formatError{err: err}
+	}
+	for dec.More() {
+		var item T
+		if err := decodeValue(dec, &item); err != nil {
+			return err
+		}
+		if err := fn(item); err != nil {
+			return err
+		}
+	}
+	if _, err := dec.Token(); err != nil { // ']'
+		return &formatError{err: err}
+	}
+	return nil
+}
+
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if d, ok := tok.(json.Delim); !ok || d != delim {
+		return fmt.Errorf("expected %q", delim)
+	}
+	return nil
+}
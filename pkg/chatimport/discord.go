@@ -0,0 +1,184 @@
+package chatimport
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"path"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// Export Discord theo định dạng JSON của DiscordChatExporter: mỗi kênh một file
+// {"guild": {...}, "channel": {...}, "messages": [...]}.
+// Discord không cho xuất email nên ZIP cần thêm users.json ở thư mục gốc: [{"id": "...", "email": "..."}]
+
+type discordUser struct {
+	ID    string `json:"id"`
+	Email string `json:"email"`
+	Name  string `json:"name"`
+}
+
+type discordChannel struct {
+	ID       string `json:"id"`
+	Type     string `json:"type"`
+	Category string `json:"category"`
+	Name     string `json:"name"`
+}
+
+type discordMessage struct {
+	ID        string `json:"id"`
+	Type      string `json:"type"`
+	Timestamp string `json:"timestamp"`
+	Content   string `json:"content"`
+	Author    struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Nickname string `json:"nickname"`
+	} `json:"author"`
+	Attachments []struct {
+		FileName string `json:"fileName"`
+	} `json:"attachments"`
+	Mentions []struct {
+		ID       string `json:"id"`
+		Name     string `json:"name"`
+		Nickname string `json:"nickname"`
+	} `json:"mentions"`
+}
+
+// discordImportedTypes là các loại tin nhắn của người dùng; tin hệ thống (ghim, vào server...) bị bỏ qua
+var discordImportedTypes = map[string]bool{
+	"Default": true,
+	"Reply":   true,
+}
+
+var discordMentionRegex = regexp.MustCompile(`<@!?(\d+)>`)
+
+type discordArchive struct {
+	zr       *zip.Reader
+	users    []User
+	channels []Channel
+}
+
+func openDiscord(zr *zip.Reader) (Archive, error) {
+	usersFile := findFile(zr, "users.json")
+	if usersFile == nil {
+		return nil, fmt.Errorf("%w: users.json (discord id to email mapping) not found", ErrInvalidArchive)
+	}
+
+	a := &discordArchive{zr: zr}
+
+	var users []discordUser
+	if err := decodeFile(usersFile, &users); err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		a.users = append(a.users, User{ExternalID: u.ID, Email: u.Email, Name: u.Name})
+	}
+
+	// Chỉ đọc phần "channel" ở đầu mỗi file, tin nhắn được đọc sau theo từng kênh
+	for _, f := range zr.File {
+		if f == usersFile || f.FileInfo().IsDir() || path.Ext(f.Name) != ".json" {
+			continue
+		}
+
+		var channel *discordChannel
+		err := decodeObject(f, func(key string, dec *json.Decoder) (bool, error) {
+			if key != "channel" {
+				return false, nil
+			}
+			channel = &discordChannel{}
+			if err := decodeValue(dec, channel); err != nil {
+				return true, err
+			}
+			return true, errStopDecoding
+		})
+		if err != nil {
+			return nil, err
+		}
+		if channel == nil || channel.ID == "" {
+			return nil, fmt.Errorf("%w: %s: channel not found", ErrInvalidArchive, f.Name)
+		}
+
+		c := Channel{
+			ExternalID: channel.ID,
+			IsDirect:   channel.Type == "DirectTextChat",
+			file:       f.Name,
+		}
+		if !c.IsDirect {
+			c.Name = channel.Name
+			if channel.Category != "" && channel.Type != "DirectGroupTextChat" {
+				c.Name = channel.Category + " / " + channel.Name
+			}
+		}
+		a.channels = append(a.channels, c)
+	}
+	if len(a.channels) == 0 {
+		return nil, fmt.Errorf("%w: no channels found", ErrInvalidArchive)
+	}
+
+	return a, nil
+}
+
+func (a *discordArchive) Users() []User { return a.users }
+
+func (a *discordArchive) Channels() []Channel { return a.channels }
+
+func (a *discordArchive) Messages(channel Channel, fn func(Message) error) error {
+	var file *zip.File
+	for _, f := range a.zr.File {
+		if f.Name == channel.file {
+			file = f
+			break
+		}
+	}
+	if file == nil {
+		return fmt.Errorf("%w: %s not found", ErrInvalidArchive, channel.file)
+	}
+
+	// DiscordChatExporter ghi tin nhắn theo thứ tự thời gian nên chỉ cần đọc tuần tự
+	return decodeObject(file, func(key string, dec *json.Decoder) (bool, error) {
+		if key != "messages" {
+			return false, nil
+		}
+		return true, decodeArray(dec, func(m discordMessage) error {
+			if !discordImportedTypes[m.Type] || m.Author.ID == "" {
+				return nil
+			}
+			createdAt, err := time.Parse(time.RFC3339Nano, m.Timestamp)
+			if err != nil {
+				return nil
+			}
+
+			text := formatDiscordText(m)
+			if strings.TrimSpace(text) == "" {
+				return nil
+			}
+
+			return fn(Message{ExternalID: m.ID, AuthorID: m.Author.ID, Text: text, CreatedAt: createdAt.UTC()})
+		})
+	})
+}
+
+// formatDiscordText đổi <@123> còn sót trong nội dung thành @tên và thêm tên file đính kèm
+func formatDiscordText(m discordMessage) string {
+	names := make(map[string]string, len(m.Mentions))
+	for _, mention := range m.Mentions {
+		names[mention.ID] = firstNonEmpty(mention.Nickname, mention.Name)
+	}
+
+	text := discordMentionRegex.ReplaceAllStringFunc(m.Content, func(token string) string {
+		id := discordMentionRegex.FindStringSubmatch(token)[1]
+		if name := names[id]; name != "" {
+			return "@" + name
+		}
+		return token
+	})
+	for _, attachment := range m.Attachments {
+		if attachment.FileName != "" {
+			text = strings.TrimSpace(text + "\n[file] " + attachment.FileName)
+		}
+	}
+	return text
+}
@@ -0,0 +1,249 @@
+package chatimport
+
+import (
+	"archive/zip"
+	"fmt"
+	"html"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Cấu trúc export của Slack: users.json, channels.json (kênh public), groups.json (kênh private),
+// dms.json (chat 1-1), mpims.json (nhóm chat) ở thư mục gốc, và mỗi kênh một thư mục <tên hoặc id>/YYYY-MM-DD.json
+
+type slackUser struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	RealName string `json:"real_name"`
+	Profile  struct {
+		Email       string `json:"email"`
+		RealName    string `json:"real_name"`
+		DisplayName string `json:"display_name"`
+	} `json:"profile"`
+}
+
+type slackChannel struct {
+	ID      string   `json:"id"`
+	Name    string   `json:"name"`
+	Creator string   `json:"creator"`
+	Members []string `json:"members"`
+}
+
+type slackMessage struct {
+	Type    string `json:"type"`
+	Subtype string `json:"subtype"`
+	User    string `json:"user"`
+	Text    string `json:"text"`
+	TS      string `json:"ts"`
+	Files   []struct {
+		Name string `json:"name"`
+	} `json:"files"`
+}
+
+// slackImportedSubtypes là các subtype là tin nhắn của người dùng; join/leave, bot... bị bỏ qua
+var slackImportedSubtypes = map[string]bool{
+	"":                 true,
+	"thread_broadcast": true,
+	"file_share":       true,
+	"me_message":       true,
+}
+
+var slackTokenRegex = regexp.MustCompile(`<([^<>]+)>`)
+
+type slackArchive struct {
+	zr       *zip.Reader
+	root     string
+	users    []User
+	names    map[string]string // user id -> tên hiển thị, dùng khi đổi <@U123> thành @tên
+	channels []Channel
+}
+
+func openSlack(zr *zip.Reader) (Archive, error) {
+	usersFile := findFile(zr, "users.json")
+	if usersFile == nil {
+		return nil, fmt.Errorf("%w: users.json not found", ErrInvalidArchive)
+	}
+
+	a := &slackArchive{
+		zr:    zr,
+		root:  path.Dir(usersFile.Name),
+		names: make(map[string]string),
+	}
+	if a.root == "." {
+		a.root = ""
+	} else {
+		a.root += "/"
+	}
+
+	var users []slackUser
+	if err := decodeFile(usersFile, &users); err != nil {
+		return nil, err
+	}
+	for _, u := range users {
+		name := firstNonEmpty(u.Profile.DisplayName, u.Profile.RealName, u.RealName, u.Name)
+		a.names[u.ID] = name
+		a.users = append(a.users, User{ExternalID: u.ID, Email: u.Profile.Email, Name: name})
+	}
+
+	// Kênh public/private/nhóm chat nằm trong thư mục theo tên, chat 1-1 theo id
+	lists := []struct {
+		file     string
+		isDirect bool
+		dirByID  bool
+	}{
+		{"channels.json", false, false},
+		{"groups.json", false, false},
+		{"mpims.json", false, false},
+		{"dms.json", true, true},
+	}
+	for _, list := range lists {
+		f := findFile(zr, list.file)
+		if f == nil {
+			continue
+		}
+		var channels []slackChannel
+		if err := decodeFile(f, &channels); err != nil {
+			return nil, err
+		}
+		for _, c := range channels {
+			dir := c.Name
+			if list.dirByID {
+				dir = c.ID
+			}
+			channel := Channel{
+				ExternalID: c.ID,
+				IsDirect:   list.isDirect,
+				CreatorID:  c.Creator,
+				MemberIDs:  c.Members,
+				file:       a.root + dir + "/",
+			}
+			if !list.isDirect {
+				channel.Name = c.Name
+			}
+			a.channels = append(a.channels, channel)
+		}
+	}
+	if len(a.channels) == 0 {
+		return nil, fmt.Errorf("%w: no channels found", ErrInvalidArchive)
+	}
+
+	return a, nil
+}
+
+func (a *slackArchive) Users() []User { return a.users }
+
+func (a *slackArchive) Channels() []Channel { return a.channels }
+
+func (a *slackArchive) Messages(channel Channel, fn func(Message) error) error {
+	// Mỗi ngày một file, tên YYYY-MM-DD.json nên sắp theo tên là theo thời gian
+	var days []*zip.File
+	for _, f := range a.zr.File {
+		if path.Dir(f.Name)+"/" == channel.file && strings.HasSuffix(f.Name, ".json") {
+			days = append(days, f)
+		}
+	}
+	sort.Slice(days, func(i, j int) bool { return days[i].Name < days[j].Name })
+
+	for _, day := range days {
+		var messages []slackMessage
+		if err := decodeFile(day, &messages); err != nil {
+			return err
+		}
+		sort.SliceStable(messages, func(i, j int) bool { return slackTSLess(messages[i].TS, messages[j].TS) })
+
+		for _, m := range messages {
+			if m.Type != "message" || !slackImportedSubtypes[m.Subtype] || m.User == "" {
+				continue
+			}
+			createdAt, err := parseSlackTS(m.TS)
+			if err != nil {
+				continue
+			}
+
+			text := a.formatText(m.Text)
+			for _, file := range m.Files {
+				if file.Name != "" {
+					text = strings.TrimSpace(text + "\n[file] " + file.Name)
+				}
+			}
+			if strings.TrimSpace(text) == "" {
+				continue
+			}
+
+			if err := fn(Message{ExternalID: m.TS, AuthorID: m.User, Text: text, CreatedAt: createdAt}); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// formatText đổi cú pháp mrkdwn của Slack về text thuần:
+// <@U123> -> @tên, <#C123|general> -> #general, <!here> -> @here, <url|nhãn> -> nhãn (url)
+func (a *slackArchive) formatText(text string) string {
+	text = slackTokenRegex.ReplaceAllStringFunc(text, func(token string) string {
+		inner := token[1 : len(token)-1]
+		target, label, _ := strings.Cut(inner, "|")
+
+		switch {
+		case strings.HasPrefix(target, "@"):
+			if name, ok := a.names[target[1:]]; ok && name != "" {
+				return "@" + name
+			}
+			return "@" + firstNonEmpty(label, target[1:])
+		case strings.HasPrefix(target, "#"):
+			return "#" + firstNonEmpty(label, target[1:])
+		case strings.HasPrefix(target, "!"):
+			switch command, _, _ := strings.Cut(target[1:], "^"); command {
+			case "here", "channel", "everyone":
+				return "@" + command
+			default: // nhóm người dùng, ngày giờ...: dùng nhãn dự phòng Slack gửi kèm
+				return label
+			}
+		case label != "" && label != target:
+			return label + " (" + target + ")"
+		default:
+			return target
+		}
+	})
+	return html.UnescapeString(text)
+}
+
+// parseSlackTS đổi ts dạng "1512085950.000216" (giây.micro giây) sang thời gian UTC
+func parseSlackTS(ts string) (time.Time, error) {
+	secPart, fracPart, _ := strings.Cut(ts, ".")
+	sec, err := strconv.ParseInt(secPart, 10, 64)
+	if err != nil {
+		return time.Time{}, err
+	}
+	var micros int64
+	if fracPart != "" {
+		fracPart = (fracPart + "000000")[:6]
+		if micros, err = strconv.ParseInt(fracPart, 10, 64); err != nil {
+			return time.Time{}, err
+		}
+	}
+	return time.Unix(sec, micros*int64(time.Microsecond)).UTC(), nil
+}
+
+func slackTSLess(a, b string) bool {
+	ta, errA := parseSlackTS(a)
+	tb, errB := parseSlackTS(b)
+	if errA != nil || errB != nil {
+		return a < b
+	}
+	return ta.Before(tb)
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, v := range values {
+		if strings.TrimSpace(v) != "" {
+			return v
+		}
+	}
+	return ""
+}
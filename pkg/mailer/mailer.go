@@ -0,0 +1,16 @@
+package mailer
+
+import "context"
+
+// Message là một email gồm bản text và HTML (client tự chọn bản hiển thị)
+type Message struct {
+	To      string
+	Subject string
+	Text    string
+	HTML    string
+}
+
+// Mailer gửi email. SMTPMailer dùng được với SMTP thật lẫn SMTP giả lập cục bộ (Mailpit, MailHog...)
+type Mailer interface {
+	Send(ctx context.Context, message Message) error
+}
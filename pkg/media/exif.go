@@ -0,0 +1,294 @@
+package media
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+)
+
+// JPEGMetadataPrefix là số byte đầu file cần đọc để chắc chắn chứa hết các segment APPn (mỗi segment tối đa 64KB)
+const JPEGMetadataPrefix = 256 << 10
+
+var (
+	exifHeader   = []byte("Exif\x00\x00")
+	xmpHeader    = []byte("http://ns.adobe.com/xap/1.0/\x00")
+	pngSignature = []byte("\x89PNG\r\n\x1a\n")
+	xmpKeyword   = []byte("XML:com.adobe.xmp\x00")
+)
+
+// StripLocation xóa dữ liệu vị trí trong ảnh JPEG, PNG hoặc WebP, sửa trực tiếp trên data.
+// Kích thước file không đổi. Với JPEG chỉ cần phần đầu file (JPEGMetadataPrefix),
+// PNG và WebP cần cả file vì metadata có thể nằm sau dữ liệu ảnh.
+// Trả về true nếu có thay đổi.
+func StripLocation(contentType string, data []byte) bool {
+	switch contentType {
+	case "image/jpeg":
+		return StripJPEGLocation(data)
+	case "image/png":
+		return StripPNGLocation(data)
+	case "image/webp":
+		return StripWebPLocation(data)
+	}
+	return false
+}
+
+// StripJPEGLocation xóa dữ liệu vị trí trong phần đầu (prefix) của file JPEG, sửa trực tiếp trên data.
+// Kích thước file không đổi nên có thể ghép lại với phần còn lại của file:
+//   - GPS IFD trong EXIF bị xóa trắng và thành IFD rỗng (các tag khác như Orientation được giữ nguyên)
+//   - gói XMP (có thể chứa exif:GPSLatitude...) bị ghi đè bằng khoảng trắng
+//
+// Trả về true nếu có thay đổi.
+func StripJPEGLocation(data []byte) bool {
+	changed := false
+	walkJPEGSegments(data, func(marker byte, segment []byte) bool {
+		if marker != 0xE1 {
+			return true
+		}
+		switch {
+		case bytes.HasPrefix(segment, exifHeader):
+			if stripGPSIFD(segment[len(exifHeader):]) {
+				changed = true
+			}
+		case bytes.HasPrefix(segment, xmpHeader):
+			blank(segment[len(xmpHeader):])
+			changed = true
+		}
+		return true
+	})
+	return changed
+}
+
+// StripPNGLocation xóa GPS trong chunk eXIf và gói XMP (iTXt/tEXt/zTXt "XML:com.adobe.xmp") của file PNG.
+// Chunk XMP được đổi thành tEXt chỉ chứa khoảng trắng để không phải nén lại; CRC được tính lại.
+func StripPNGLocation(data []byte) bool {
+	if !bytes.HasPrefix(data, pngSignature) {
+		return false
+	}
+
+	changed := false
+	i := len(pngSignature)
+	for i+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if end > len(data) {
+			break
+		}
+		typ := data[i+4 : i+8]
+		chunk := data[i+8 : i+8+length]
+
+		chunkChanged := false
+		switch string(typ) {
+		case "eXIf":
+			chunkChanged = stripGPSIFD(bytes.TrimPrefix(chunk, exifHeader))
+		case "iTXt", "tEXt", "zTXt":
+			if bytes.HasPrefix(chunk, xmpKeyword) {
+				copy(typ, "tEXt")
+				blank(chunk[len(xmpKeyword):])
+				chunkChanged = true
+			}
+		case "IEND":
+			return changed
+		}
+		if chunkChanged {
+			binary.BigEndian.PutUint32(data[i+8+length:], crc32.ChecksumIEEE(data[i+4:i+8+length]))
+			changed = true
+		}
+		i = end
+	}
+	return changed
+}
+
+// StripWebPLocation xóa GPS trong chunk EXIF và ghi đè chunk "XMP " bằng khoảng trắng của file WebP
+func StripWebPLocation(data []byte) bool {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return false
+	}
+
+	changed := false
+	i := 12
+	for i+8 <= len(data) {
+		size := int(binary.LittleEndian.Uint32(data[i+4:]))
+		end := i + 8 + size
+		if end > len(data) {
+			break
+		}
+		chunk := data[i+8 : end]
+
+		switch string(data[i : i+4]) {
+		case "EXIF":
+			if stripGPSIFD(bytes.TrimPrefix(chunk, exifHeader)) {
+				changed = true
+			}
+		case "XMP ":
+			blank(chunk)
+			changed = true
+		}
+		i = end + size%2 // chunk có độ dài lẻ được đệm 1 byte
+	}
+	return changed
+}
+
+// Orientation đọc tag Orientation (1-8) trong EXIF của ảnh JPEG hoặc PNG, trả về 1 nếu không có
+func Orientation(data []byte) int {
+	var tiff []byte
+	switch {
+	case bytes.HasPrefix(data, pngSignature):
+		tiff = pngExif(data)
+	default:
+		walkJPEGSegments(data, func(marker byte, segment []byte) bool {
+			if marker == 0xE1 && bytes.HasPrefix(segment, exifHeader) {
+				tiff = segment[len(exifHeader):]
+				return false
+			}
+			return true
+		})
+	}
+
+	order, ifd0, ok := tiffIFD0(tiff)
+	if !ok {
+		return 1
+	}
+	entries := int(order.Uint16(tiff[ifd0:]))
+	for k := 0; k < entries; k++ {
+		entry := ifd0 + 2 + 12*k
+		if entry+12 > len(tiff) {
+			break
+		}
+		if order.Uint16(tiff[entry:]) == 0x0112 && order.Uint16(tiff[entry+2:]) == 3 { // Orientation, SHORT
+			if v := int(order.Uint16(tiff[entry+8:])); v >= 1 && v <= 8 {
+				return v
+			}
+			break
+		}
+	}
+	return 1
+}
+
+// walkJPEGSegments gọi fn với từng segment trước dữ liệu ảnh (SOS), dừng khi fn trả về false
+func walkJPEGSegments(data []byte, fn func(marker byte, segment []byte) bool) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return
+	}
+
+	i := 2
+	for i+4 <= len(data) {
+		if data[i] != 0xFF {
+			break
+		}
+		marker := data[i+1]
+		if marker == 0xFF { // byte đệm
+			i++
+			continue
+		}
+		if marker == 0xDA || marker == 0xD9 { // bắt đầu dữ liệu ảnh / hết file
+			break
+		}
+		if marker == 0x01 || (marker >= 0xD0 && marker <= 0xD7) { // marker không có độ dài
+			i += 2
+			continue
+		}
+
+		length := int(binary.BigEndian.Uint16(data[i+2:]))
+		end := i + 2 + length
+		if length < 2 || end > len(data) {
+			break
+		}
+		if !fn(marker, data[i+4:end]) {
+			return
+		}
+		i = end
+	}
+}
+
+// pngExif trả về nội dung chunk eXIf (TIFF) nằm trước dữ liệu ảnh
+func pngExif(data []byte) []byte {
+	i := len(pngSignature)
+	for i+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[i:]))
+		end := i + 12 + length
+		if end > len(data) {
+			break
+		}
+		switch string(data[i+4 : i+8]) {
+		case "eXIf":
+			return bytes.TrimPrefix(data[i+8:i+8+length], exifHeader)
+		case "IDAT", "IEND":
+			return nil
+		}
+		i = end
+	}
+	return nil
+}
+
+func blank(data []byte) {
+	for k := range data {
+		data[k] = ' '
+	}
+}
+
+// kích thước (byte) của từng kiểu dữ liệu TIFF
+var tiffTypeSize = map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 5: 8, 6: 1, 7: 1, 8: 2, 9: 4, 10: 8, 11: 4, 12: 8}
+
+// tiffIFD0 đọc byte order và vị trí IFD đầu tiên của dữ liệu TIFF
+func tiffIFD0(tiff []byte) (binary.ByteOrder, int, bool) {
+	if len(tiff) < 8 {
+		return nil, 0, false
+	}
+	var order binary.ByteOrder
+	switch string(tiff[:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return nil, 0, false
+	}
+
+	ifd0 := int(order.Uint32(tiff[4:]))
+	if ifd0+2 > len(tiff) {
+		return nil, 0, false
+	}
+	return order, ifd0, true
+}
+
+func stripGPSIFD(tiff []byte) bool {
+	order, ifd0, ok := tiffIFD0(tiff)
+	if !ok {
+		return false
+	}
+	entries := int(order.Uint16(tiff[ifd0:]))
+	for k := 0; k < entries; k++ {
+		entry := ifd0 + 2 + 12*k
+		if entry+12 > len(tiff) {
+			return false
+		}
+		if order.Uint16(tiff[entry:]) != 0x8825 { // GPSInfo
+			continue
+		}
+
+		gps := int(order.Uint32(tiff[entry+8:]))
+		if gps+2 > len(tiff) {
+			return false
+		}
+		gpsEntries := int(order.Uint16(tiff[gps:]))
+		for g := 0; g < gpsEntries; g++ {
+			gpsEntry := gps + 2 + 12*g
+			if gpsEntry+12 > len(tiff) {
+				break
+			}
+			typ := order.Uint16(tiff[gpsEntry+2:])
+			count := int(order.Uint32(tiff[gpsEntry+4:]))
+			// Giá trị lớn hơn 4 byte nằm ngoài entry, xóa luôn phần dữ liệu đó
+			if size := tiffTypeSize[typ] * count; size > 4 {
+				offset := int(order.Uint32(tiff[gpsEntry+8:]))
+				if offset >= 0 && offset+size <= len(tiff) {
+					clear(tiff[offset : offset+size])
+				}
+			}
+			clear(tiff[gpsEntry : gpsEntry+12])
+		}
+		order.PutUint16(tiff[gps:], 0) // IFD rỗng
+		return true
+	}
+	return false
+}
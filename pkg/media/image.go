@@ -0,0 +1,196 @@
+package media
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/jpeg"
+	"image/png"
+	"io"
+)
+
+// MaxImagePixels giới hạn số pixel được giải mã để tránh "decompression bomb"
+const MaxImagePixels = 50_000_000
+
+// ErrUnsupportedImage trả về khi định dạng ảnh không giải mã được (vd: webp)
+var ErrUnsupportedImage = errors.New("media: unsupported image format")
+
+// Thumbnail là kết quả xử lý một ảnh
+type Thumbnail struct {
+	Width       int // kích thước ảnh gốc
+	Height      int
+	Data        []byte // ảnh thu nhỏ đã encode
+	ContentType string
+	ThumbWidth  int
+	ThumbHeight int
+}
+
+// CanDecode cho biết MIME type có được hỗ trợ tạo thumbnail không
+func CanDecode(contentType string) bool {
+	switch contentType {
+	case "image/jpeg", "image/png", "image/gif":
+		return true
+	}
+	return false
+}
+
+// MakeThumbnail giải mã ảnh, thu nhỏ để cạnh dài nhất không vượt quá maxSize.
+// Thumbnail được xoay theo tag EXIF Orientation và encode lại nên không còn metadata (EXIF...) của ảnh gốc;
+// Width/Height là kích thước ảnh gốc sau khi xoay (như khi hiển thị).
+func MakeThumbnail(data []byte, maxSize int) (Thumbnail, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return Thumbnail{}, ErrUnsupportedImage
+	}
+	if cfg.Width*cfg.Height > MaxImagePixels {
+		return Thumbnail{}, fmt.Errorf("media: image too large (%dx%d)", cfg.Width, cfg.Height)
+	}
+
+	var src image.Image
+	switch format {
+	case "jpeg":
+		src, err = jpeg.Decode(bytes.NewReader(data))
+	case "png":
+		src, err = png.Decode(bytes.NewReader(data))
+	case "gif":
+		src, err = gif.Decode(bytes.NewReader(data)) // chỉ lấy frame đầu
+	default:
+		return Thumbnail{}, ErrUnsupportedImage
+	}
+	if err != nil {
+		return Thumbnail{}, err
+	}
+
+	orientation := Orientation(data)
+	thumb := orient(resize(src, maxSize), orientation)
+	width, height := cfg.Width, cfg.Height
+	if orientation >= 5 { // xoay 90 độ: đổi chiều rộng và cao
+		width, height = height, width
+	}
+
+	// Giữ nền trong suốt cho png/gif, còn lại dùng jpeg cho nhẹ
+	var buf bytes.Buffer
+	contentType := "image/jpeg"
+	if format == "jpeg" {
+		err = jpeg.Encode(&buf, thumb, &jpeg.Options{Quality: 80})
+	} else {
+		contentType = "image/png"
+		err = png.Encode(&buf, thumb)
+	}
+	if err != nil {
+		return Thumbnail{}, err
+	}
+
+	bounds := thumb.Bounds()
+	return Thumbnail{
+		Width:       width,
+		Height:      height,
+		Data:        buf.Bytes(),
+		ContentType: contentType,
+		ThumbWidth:  bounds.Dx(),
+		ThumbHeight: bounds.Dy(),
+	}, nil
+}
+
+// resize thu nhỏ bằng box filter (trung bình các pixel nguồn rơi vào mỗi pixel đích)
+func resize(src image.Image, maxSize int) *image.NRGBA {
+	bounds := src.Bounds()
+	srcW, srcH := bounds.Dx(), bounds.Dy()
+	if srcW <= maxSize && srcH <= maxSize {
+		dst := image.NewNRGBA(image.Rect(0, 0, srcW, srcH))
+		for y := 0; y < srcH; y++ {
+			for x := 0; x < srcW; x++ {
+				dst.Set(x, y, src.At(bounds.Min.X+x, bounds.Min.Y+y))
+			}
+		}
+		return dst
+	}
+
+	dstW, dstH := maxSize, maxSize
+	if srcW > srcH {
+		dstH = max(1, srcH*maxSize/srcW)
+	} else {
+		dstW = max(1, srcW*maxSize/srcH)
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for dy := 0; dy < dstH; dy++ {
+		y0 := bounds.Min.Y + dy*srcH/dstH
+		y1 := max(y0+1, bounds.Min.Y+(dy+1)*srcH/dstH)
+		for dx := 0; dx < dstW; dx++ {
+			x0 := bounds.Min.X + dx*srcW/dstW
+			x1 := max(x0+1, bounds.Min.X+(dx+1)*srcW/dstW)
+
+			var r, g, b, a, n uint64
+			for y := y0; y < y1; y++ {
+				for x := x0; x < x1; x++ {
+					cr, cg, cb, ca := src.At(x, y).RGBA()
+					r += uint64(cr)
+					g += uint64(cg)
+					b += uint64(cb)
+					a += uint64(ca)
+					n++
+				}
+			}
+			dst.Set(dx, dy, color.RGBA64{
+				R: uint16(r / n),
+				G: uint16(g / n),
+				B: uint16(b / n),
+				A: uint16(a / n),
+			})
+		}
+	}
+	return dst
+}
+
+// orient xoay/lật ảnh theo giá trị EXIF Orientation (1 = giữ nguyên)
+func orient(src *image.NRGBA, orientation int) *image.NRGBA {
+	if orientation < 2 || orientation > 8 {
+		return src
+	}
+	w, h := src.Bounds().Dx(), src.Bounds().Dy()
+	dstW, dstH := w, h
+	if orientation >= 5 {
+		dstW, dstH = h, w
+	}
+
+	dst := image.NewNRGBA(image.Rect(0, 0, dstW, dstH))
+	for y := 0; y < dstH; y++ {
+		for x := 0; x < dstW; x++ {
+			var sx, sy int
+			switch orientation {
+			case 2: // lật ngang
+				sx, sy = w-1-x, y
+			case 3: // xoay 180
+				sx, sy = w-1-x, h-1-y
+			case 4: // lật dọc
+				sx, sy = x, h-1-y
+			case 5: // lật ngang rồi xoay 270 (transpose)
+				sx, sy = y, x
+			case 6: // xoay 90 theo chiều kim đồng hồ
+				sx, sy = y, h-1-x
+			case 7: // lật ngang rồi xoay 90 (transverse)
+				sx, sy = w-1-y, h-1-x
+			case 8: // xoay 90 ngược chiều kim đồng hồ
+				sx, sy = w-1-y, x
+			}
+			dst.SetNRGBA(x, y, src.NRGBAAt(sx, sy))
+		}
+	}
+	return dst
+}
+
+// ReadAllLimit đọc toàn bộ reader nhưng không quá limit byte
+func ReadAllLimit(r io.Reader, limit int64) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, limit+1))
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > limit {
+		return nil, fmt.Errorf("media: file larger than %d bytes", limit)
+	}
+	return data, nil
+}
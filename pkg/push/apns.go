@@ -0,0 +1,142 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// APNsConfig dùng token-based auth (.p8 key) của Apple
+type APNsConfig struct {
+	KeyFile    string
+	KeyID      string
+	TeamID     string
+	Topic      string // bundle id của app
+	Production bool
+}
+
+type apnsProvider struct {
+	client     *http.Client
+	config     APNsConfig
+	privateKey *ecdsa.PrivateKey
+	baseURL    string
+
+	mu       sync.Mutex
+	token    string
+	issuedAt time.Time
+}
+
+// NewAPNsProvider gửi push qua APNs HTTP/2 API
+func NewAPNsProvider(config APNsConfig) (Provider, error) {
+	raw, err := os.ReadFile(config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("push: could not read APNs key: %w", err)
+	}
+
+	privateKey, err := jwt.ParseECPrivateKeyFromPEM(raw)
+	if err != nil {
+		return nil, fmt.Errorf("push: invalid APNs key: %w", err)
+	}
+
+	baseURL := "https://api.sandbox.push.apple.com"
+	if config.Production {
+		baseURL = "https://api.push.apple.com"
+	}
+
+	return &apnsProvider{
+		client:     &http.Client{Timeout: 30 * time.Second},
+		config:     config,
+		privateKey: privateKey,
+		baseURL:    baseURL,
+	}, nil
+}
+
+func (p *apnsProvider) Send(ctx context.Context, device Device, notification Notification) error {
+	token, err := p.providerToken()
+	if err != nil {
+		return err
+	}
+
+	payload := map[string]interface{}{
+		"aps": map[string]interface{}{
+			"alert": map[string]string{
+				"title": notification.Title,
+				"body":  notification.Body,
+			},
+			"sound": "default",
+		},
+	}
+	for key, value := range notification.Data {
+		payload[key] = value
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.baseURL+"/3/device/"+device.Token, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Authorization", "bearer "+token)
+	req.Header.Set("apns-topic", p.config.Topic)
+	req.Header.Set("apns-push-type", "alert")
+	req.Header.Set("apns-priority", "10")
+	if notification.CollapseKey != "" {
+		req.Header.Set("apns-collapse-id", notification.CollapseKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	// APNs trả 400 BadDeviceToken cho token sai môi trường/định dạng
+	if resp.StatusCode == http.StatusBadRequest {
+		var reason struct {
+			Reason string `json:"reason"`
+		}
+		_ = json.NewDecoder(resp.Body).Decode(&reason)
+		if reason.Reason == "BadDeviceToken" || reason.Reason == "DeviceTokenNotForTopic" {
+			return fmt.Errorf("%w (apns: %s)", ErrInvalidToken, reason.Reason)
+		}
+		return Permanent(fmt.Errorf("push: apns returned 400: %s", reason.Reason))
+	}
+	return statusError("apns", resp)
+}
+
+// providerToken trả về JWT ký bằng .p8 key, Apple yêu cầu làm mới sau 20-60 phút
+func (p *apnsProvider) providerToken() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.token != "" && time.Since(p.issuedAt) < 50*time.Minute {
+		return p.token, nil
+	}
+
+	now := time.Now()
+	token := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"iss": p.config.TeamID,
+		"iat": now.Unix(),
+	})
+	token.Header["kid"] = p.config.KeyID
+
+	signed, err := token.SignedString(p.privateKey)
+	if err != nil {
+		return "", Permanent(err)
+	}
+
+	p.token = signed
+	p.issuedAt = now
+	return signed, nil
+}
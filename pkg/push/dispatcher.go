@@ -0,0 +1,136 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"log"
+	"math/rand/v2"
+	"time"
+)
+
+// DispatcherConfig cấu hình worker pool và retry
+type DispatcherConfig struct {
+	Workers     int
+	QueueSize   int
+	MaxAttempts int           // tổng số lần gửi, tính cả lần đầu
+	BaseBackoff time.Duration // lần retry thứ n chờ BaseBackoff * 2^(n-1) (+ jitter)
+	SendTimeout time.Duration
+}
+
+func DefaultDispatcherConfig() DispatcherConfig {
+	return DispatcherConfig{
+		Workers:     4,
+		QueueSize:   1000,
+		MaxAttempts: 5,
+		BaseBackoff: 2 * time.Second,
+		SendTimeout: 15 * time.Second,
+	}
+}
+
+type job struct {
+	device       Device
+	notification Notification
+	attempt      int
+}
+
+// Dispatcher gửi push bất đồng bộ qua provider của từng nền tảng, lỗi tạm thời được gửi lại với exponential backoff
+type Dispatcher struct {
+	config    DispatcherConfig
+	providers map[string]Provider
+	queue     chan job
+	quit      chan bool
+
+	// Callback khi token không còn hợp lệ (vd: xóa thiết bị khỏi DB)
+	onInvalidToken func(device Device)
+}
+
+func NewDispatcher(config DispatcherConfig) *Dispatcher {
+	return &Dispatcher{
+		config:    config,
+		providers: make(map[string]Provider),
+		queue:     make(chan job, config.QueueSize),
+		quit:      make(chan bool),
+	}
+}
+
+// Register gắn provider cho một nền tảng
+func (d *Dispatcher) Register(platform string, provider Provider) {
+	d.providers[platform] = provider
+}
+
+// Supports cho biết nền tảng đã được cấu hình provider chưa
+func (d *Dispatcher) Supports(platform string) bool {
+	_, ok := d.providers[platform]
+	return ok
+}
+
+func (d *Dispatcher) SetInvalidTokenCallback(callback func(device Device)) {
+	d.onInvalidToken = callback
+}
+
+// Start chạy các worker gửi push
+func (d *Dispatcher) Start() {
+	for i := 0; i < d.config.Workers; i++ {
+		go func() {
+			for {
+				select {
+				case j := <-d.queue:
+					d.send(j)
+				case <-d.quit:
+					return
+				}
+			}
+		}()
+	}
+}
+
+// Enqueue đưa push vào hàng đợi, trả về false nếu nền tảng chưa được cấu hình hoặc hàng đợi đầy
+func (d *Dispatcher) Enqueue(device Device, notification Notification) bool {
+	if !d.Supports(device.Platform) {
+		return false
+	}
+	return d.enqueue(job{device: device, notification: notification, attempt: 1})
+}
+
+func (d *Dispatcher) enqueue(j job) bool {
+	select {
+	case d.queue <- j:
+		return true
+	default:
+		log.Printf("⚠️ Push queue full, dropping push to device %d", j.device.ID)
+		return false
+	}
+}
+
+func (d *Dispatcher) send(j job) {
+	ctx, cancel := context.WithTimeout(context.Background(), d.config.SendTimeout)
+	defer cancel()
+
+	err := d.providers[j.device.Platform].Send(ctx, j.device, j.notification)
+	if err == nil {
+		return
+	}
+
+	if errors.Is(err, ErrInvalidToken) {
+		log.Printf("🧹 Push token of device %d is no longer valid: %v", j.device.ID, err)
+		if d.onInvalidToken != nil {
+			d.onInvalidToken(j.device)
+		}
+		return
+	}
+
+	if !IsRetryable(err) || j.attempt >= d.config.MaxAttempts {
+		log.Printf("❌ Push to device %d failed after %d attempt(s): %v", j.device.ID, j.attempt, err)
+		return
+	}
+
+	// Retry với exponential backoff + jitter
+	backoff := d.config.BaseBackoff << (j.attempt - 1)
+	backoff += time.Duration(rand.Int64N(int64(backoff)/2 + 1))
+	log.Printf("⚠️ Push to device %d failed (attempt %d), retrying in %s: %v", j.device.ID, j.attempt, backoff, err)
+
+	j.attempt++
+	time.AfterFunc(backoff, func() {
+		d.enqueue(j)
+	})
+}
@@ -0,0 +1,170 @@
+package push
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+)
+
+// standIn là server nhận push của NewHTTPProvider, trả lần lượt các status cho trước (hết thì trả 200)
+type standIn struct {
+	mu       sync.Mutex
+	statuses []int
+	requests []map[string]any
+	received chan struct{}
+}
+
+func newStandIn(t *testing.T, statuses ...int) (*standIn, *httptest.Server) {
+	s := &standIn{statuses: statuses, received: make(chan struct{}, 100)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]any
+		json.NewDecoder(r.Body).Decode(&body)
+
+		s.mu.Lock()
+		s.requests = append(s.requests, body)
+		status := http.StatusOK
+		if len(s.statuses) > 0 {
+			status, s.statuses = s.statuses[0], s.statuses[1:]
+		}
+		s.mu.Unlock()
+
+		w.WriteHeader(status)
+		s.received <- struct{}{}
+	}))
+	t.Cleanup(server.Close)
+	return s, server
+}
+
+func (s *standIn) count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return len(s.requests)
+}
+
+// waitRequests chờ server nhận đủ n request
+func (s *standIn) waitRequests(t *testing.T, n int) {
+	t.Helper()
+	for i := s.count(); i < n; i++ {
+		select {
+		case <-s.received:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("got %d request(s), want %d", s.count(), n)
+		}
+	}
+}
+
+func newTestDispatcher(t *testing.T, endpoint string, maxAttempts int) *Dispatcher {
+	d := NewDispatcher(DispatcherConfig{
+		Workers:     1,
+		QueueSize:   10,
+		MaxAttempts: maxAttempts,
+		BaseBackoff: time.Millisecond,
+		SendTimeout: time.Second,
+	})
+	d.Register(PlatformFCM, NewHTTPProvider(endpoint))
+	d.Start()
+	t.Cleanup(func() { close(d.quit) })
+	return d
+}
+
+var testDevice = Device{ID: 7, Platform: PlatformFCM, Token: "token-7"}
+
+func TestDispatcherRetriesTemporaryErrors(t *testing.T) {
+	server, ts := newStandIn(t, http.StatusServiceUnavailable, http.StatusTooManyRequests)
+	d := newTestDispatcher(t, ts.URL, 5)
+
+	if !d.Enqueue(testDevice, Notification{Title: "hi"}) {
+		t.Fatal("Enqueue returned false")
+	}
+	// 503, 429 rồi thành công ở lần thứ 3
+	server.waitRequests(t, 3)
+
+	time.Sleep(50 * time.Millisecond)
+	if got := server.count(); got != 3 {
+		t.Errorf("requests = %d, want 3 (no retry after success)", got)
+	}
+	if server.requests[2]["token"] != "token-7" {
+		t.Errorf("retried request lost the device token: %v", server.requests[2])
+	}
+}
+
+func TestDispatcherStopsAfterMaxAttempts(t *testing.T) {
+	server, ts := newStandIn(t, 500, 500, 500, 500, 500)
+	d := newTestDispatcher(t, ts.URL, 3)
+
+	d.Enqueue(testDevice, Notification{Title: "hi"})
+	server.waitRequests(t, 3)
+
+	time.Sleep(50 * time.Millisecond)
+	if got := server.count(); got != 3 {
+		t.Errorf("requests = %d, want 3 (MaxAttempts)", got)
+	}
+}
+
+func TestDispatcherBackoffGrows(t *testing.T) {
+	server, ts := newStandIn(t, 500, 500)
+	d := NewDispatcher(DispatcherConfig{Workers: 1, QueueSize: 10, MaxAttempts: 3, BaseBackoff: 40 * time.Millisecond, SendTimeout: time.Second})
+	d.Register(PlatformFCM, NewHTTPProvider(ts.URL))
+	d.Start()
+	defer close(d.quit)
+
+	start := time.Now()
+	d.Enqueue(testDevice, Notification{Title: "hi"})
+	server.waitRequests(t, 3)
+
+	// Chờ ít nhất 40ms rồi 80ms giữa các lần gửi
+	if elapsed := time.Since(start); elapsed < 120*time.Millisecond {
+		t.Errorf("retries finished after %s, want at least 120ms of backoff", elapsed)
+	}
+}
+
+func TestDispatcherPrunesInvalidTokens(t *testing.T) {
+	for _, status := range []int{http.StatusNotFound, http.StatusGone} {
+		server, ts := newStandIn(t, status)
+		d := newTestDispatcher(t, ts.URL, 5)
+
+		pruned := make(chan Device, 1)
+		d.SetInvalidTokenCallback(func(device Device) { pruned <- device })
+
+		d.Enqueue(testDevice, Notification{Title: "hi"})
+		select {
+		case device := <-pruned:
+			if device.ID != testDevice.ID {
+				t.Errorf("status %d: pruned device %d, want %d", status, device.ID, testDevice.ID)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("status %d: invalid token callback was not called", status)
+		}
+
+		time.Sleep(20 * time.Millisecond)
+		if got := server.count(); got != 1 {
+			t.Errorf("status %d: requests = %d, want 1 (no retry for invalid token)", status, got)
+		}
+	}
+}
+
+func TestDispatcherDoesNotRetryPermanentErrors(t *testing.T) {
+	server, ts := newStandIn(t, http.StatusBadRequest)
+	d := newTestDispatcher(t, ts.URL, 5)
+
+	d.SetInvalidTokenCallback(func(device Device) { t.Errorf("device %d should not be pruned on 400", device.ID) })
+	d.Enqueue(testDevice, Notification{Title: "hi"})
+	server.waitRequests(t, 1)
+
+	time.Sleep(20 * time.Millisecond)
+	if got := server.count(); got != 1 {
+		t.Errorf("requests = %d, want 1", got)
+	}
+}
+
+func TestDispatcherSkipsUnsupportedPlatforms(t *testing.T) {
+	_, ts := newStandIn(t)
+	d := newTestDispatcher(t, ts.URL, 5)
+
+	if d.Enqueue(Device{ID: 1, Platform: PlatformAPNs, Token: "x"}, Notification{}) {
+		t.Error("Enqueue should return false for a platform without provider")
+	}
+}
@@ -0,0 +1,50 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+type httpProvider struct {
+	client   *http.Client
+	endpoint string
+}
+
+// NewHTTPProvider POST mỗi push dưới dạng JSON tới một endpoint cục bộ.
+// Dùng thay cho nhà cung cấp thật khi phát triển/test (vd: một server nhỏ ghi lại request).
+// Endpoint trả 404/410 để giả lập token hết hạn, 429/5xx để giả lập lỗi tạm thời.
+func NewHTTPProvider(endpoint string) Provider {
+	return &httpProvider{
+		client:   &http.Client{Timeout: 10 * time.Second},
+		endpoint: endpoint,
+	}
+}
+
+func (p *httpProvider) Send(ctx context.Context, device Device, notification Notification) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"device_id":    device.ID,
+		"platform":     device.Platform,
+		"token":        device.Token,
+		"notification": notification,
+	})
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return statusError("http", resp)
+}
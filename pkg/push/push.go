@@ -0,0 +1,81 @@
+package push
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+// Nền tảng của thiết bị nhận push (khớp với chk_push_platform)
+const (
+	PlatformWebPush = "webpush" // Web Push (VAPID), token là endpoint của subscription
+	PlatformFCM     = "fcm"     // Firebase Cloud Messaging HTTP v1
+	PlatformAPNs    = "apns"    // Apple Push Notification service (token-based auth)
+)
+
+// ErrInvalidToken trả về khi nhà cung cấp báo token/subscription không còn hợp lệ, thiết bị nên bị xóa
+var ErrInvalidToken = errors.New("push: device token is no longer valid")
+
+// Device là thiết bị nhận push của user
+type Device struct {
+	ID       int64
+	Platform string
+	Token    string
+	P256dh   string // Web Push: public key của subscription (base64url)
+	Auth     string // Web Push: auth secret của subscription (base64url)
+}
+
+// Notification là nội dung push, Data được gửi kèm để client mở đúng phòng/tin nhắn
+type Notification struct {
+	Title       string            `json:"title"`
+	Body        string            `json:"body"`
+	Data        map[string]string `json:"data,omitempty"`
+	CollapseKey string            `json:"collapse_key,omitempty"` // gộp các push cùng khóa (vd: cùng phòng)
+}
+
+// Provider gửi push tới một nền tảng cụ thể
+type Provider interface {
+	Send(ctx context.Context, device Device, notification Notification) error
+}
+
+// permanentError là lỗi không nên gửi lại (payload sai, cấu hình sai...)
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent đánh dấu lỗi không cần retry
+func Permanent(err error) error {
+	return &permanentError{err: err}
+}
+
+// IsRetryable cho biết job có nên được đưa lại vào hàng đợi không
+func IsRetryable(err error) bool {
+	var permanent *permanentError
+	return err != nil && !errors.Is(err, ErrInvalidToken) && !errors.As(err, &permanent)
+}
+
+// statusError chuyển HTTP status của nhà cung cấp thành lỗi:
+// 404/410 = token hết hạn, 429/5xx = retry, các lỗi 4xx khác = không retry
+func statusError(provider string, resp *http.Response) error {
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return nil
+	}
+
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	err := fmt.Errorf("push: %s returned %d: %s", provider, resp.StatusCode, strings.TrimSpace(string(body)))
+
+	switch {
+	case resp.StatusCode == http.StatusNotFound || resp.StatusCode == http.StatusGone:
+		return fmt.Errorf("%w (%v)", ErrInvalidToken, err)
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		return err
+	default:
+		return Permanent(err)
+	}
+}
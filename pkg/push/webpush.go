@@ -0,0 +1,203 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	webPushRecordSize = 4096
+	webPushTTL        = 24 * time.Hour // push server giữ tin tối đa 1 ngày nếu trình duyệt offline
+)
+
+// WebPushConfig là cặp khóa VAPID (base64url, định dạng của thư viện web-push) và subject (mailto: hoặc https:)
+type WebPushConfig struct {
+	PublicKey  string
+	PrivateKey string
+	Subject    string
+}
+
+type webPushProvider struct {
+	client     *http.Client
+	privateKey *ecdsa.PrivateKey
+	publicKey  string
+	subject    string
+}
+
+// NewWebPushProvider gửi push theo RFC 8030, payload mã hóa aes128gcm (RFC 8291), xác thực VAPID (RFC 8292)
+func NewWebPushProvider(config WebPushConfig) (Provider, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(config.PrivateKey)
+	if err != nil {
+		return nil, fmt.Errorf("push: invalid VAPID private key: %w", err)
+	}
+	key, err := ecdh.P256().NewPrivateKey(raw)
+	if err != nil {
+		return nil, fmt.Errorf("push: invalid VAPID private key: %w", err)
+	}
+
+	// Public key dạng uncompressed: 0x04 || X || Y
+	public := key.PublicKey().Bytes()
+	if config.PublicKey != "" && config.PublicKey != base64.RawURLEncoding.EncodeToString(public) {
+		return nil, errors.New("push: VAPID public key does not match the private key")
+	}
+
+	privateKey := &ecdsa.PrivateKey{
+		PublicKey: ecdsa.PublicKey{
+			Curve: elliptic.P256(),
+			X:     new(big.Int).SetBytes(public[1:33]),
+			Y:     new(big.Int).SetBytes(public[33:65]),
+		},
+		D: new(big.Int).SetBytes(raw),
+	}
+
+	return &webPushProvider{
+		client:     &http.Client{Timeout: 30 * time.Second},
+		privateKey: privateKey,
+		publicKey:  base64.RawURLEncoding.EncodeToString(public),
+		subject:    config.Subject,
+	}, nil
+}
+
+func (p *webPushProvider) Send(ctx context.Context, device Device, notification Notification) error {
+	endpoint, err := url.Parse(device.Token)
+	if err != nil || endpoint.Scheme != "https" {
+		return fmt.Errorf("%w (invalid endpoint)", ErrInvalidToken)
+	}
+
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	body, err := encryptWebPush(payload, device.P256dh, device.Auth)
+	if err != nil {
+		return fmt.Errorf("%w (%v)", ErrInvalidToken, err)
+	}
+
+	// VAPID JWT, audience là origin của push service
+	token, err := jwt.NewWithClaims(jwt.SigningMethodES256, jwt.MapClaims{
+		"aud": endpoint.Scheme + "://" + endpoint.Host,
+		"exp": time.Now().Add(12 * time.Hour).Unix(),
+		"sub": p.subject,
+	}).SignedString(p.privateKey)
+	if err != nil {
+		return Permanent(err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, device.Token, bytes.NewReader(body))
+	if err != nil {
+		return Permanent(err)
+	}
+	req.Header.Set("Authorization", fmt.Sprintf("vapid t=%s, k=%s", token, p.publicKey))
+	req.Header.Set("Content-Encoding", "aes128gcm")
+	req.Header.Set("Content-Type", "application/octet-stream")
+	req.Header.Set("TTL", strconv.Itoa(int(webPushTTL.Seconds())))
+	req.Header.Set("Urgency", "high")
+	if notification.CollapseKey != "" {
+		req.Header.Set("Topic", notification.CollapseKey)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	return statusError("web push", resp)
+}
+
+// encryptWebPush mã hóa payload theo RFC 8291 (một record duy nhất)
+func encryptWebPush(plaintext []byte, p256dh, authSecret string) ([]byte, error) {
+	// Khóa tạm thời của application server và salt mới cho mỗi tin
+	asPrivate, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	salt := make([]byte, 16)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	return encryptWebPushWithKey(plaintext, p256dh, authSecret, asPrivate, salt)
+}
+
+// encryptWebPushWithKey mã hóa với khóa tạm thời và salt cho trước (tách riêng để kiểm tra bằng test vector của RFC 8291)
+func encryptWebPushWithKey(plaintext []byte, p256dh, authSecret string, asPrivate *ecdh.PrivateKey, salt []byte) ([]byte, error) {
+	uaPublicBytes, err := base64.RawURLEncoding.DecodeString(p256dh)
+	if err != nil {
+		return nil, errors.New("invalid p256dh key")
+	}
+	uaPublic, err := ecdh.P256().NewPublicKey(uaPublicBytes)
+	if err != nil {
+		return nil, errors.New("invalid p256dh key")
+	}
+	auth, err := base64.RawURLEncoding.DecodeString(authSecret)
+	if err != nil || len(auth) != 16 {
+		return nil, errors.New("invalid auth secret")
+	}
+
+	asPublic := asPrivate.PublicKey().Bytes()
+
+	sharedSecret, err := asPrivate.ECDH(uaPublic)
+	if err != nil {
+		return nil, err
+	}
+
+	keyInfo := "WebPush: info\x00" + string(uaPublicBytes) + string(asPublic)
+	ikm, err := hkdf.Key(sha256.New, sharedSecret, auth, keyInfo, 32)
+	if err != nil {
+		return nil, err
+	}
+
+	cek, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+	if err != nil {
+		return nil, err
+	}
+
+	block, err := aes.NewCipher(cek)
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+
+	// 0x02 = delimiter của record cuối cùng
+	record := append(append([]byte{}, plaintext...), 0x02)
+	if len(record)+gcm.Overhead() > webPushRecordSize {
+		return nil, Permanent(errors.New("push: web push payload too large"))
+	}
+
+	// Header: salt (16) || record size (4) || key id length (1) || key id (public key tạm thời)
+	header := make([]byte, 0, 21+len(asPublic))
+	header = append(header, salt...)
+	header = binary.BigEndian.AppendUint32(header, webPushRecordSize)
+	header = append(header, byte(len(asPublic)))
+	header = append(header, asPublic...)
+
+	return gcm.Seal(header, nonce, record, nil), nil
+}
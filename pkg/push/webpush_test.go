@@ -0,0 +1,228 @@
+package push
+
+import (
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+func mustDecode(t *testing.T, s string) []byte {
+	t.Helper()
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		t.Fatalf("decode %q: %v", s, err)
+	}
+	return b
+}
+
+// Test vector ở RFC 8291, Appendix A
+func TestEncryptWebPushRFC8291Vector(t *testing.T) {
+	const (
+		plaintext = "When I grow up, I want to be a watermelon"
+		asPrivate = "yfWPiYE-n46HLnH0KqZOF1fJJU3MYrct3AELtAQ-oRw"
+		asPublic  = "BP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A8"
+		uaPublic  = "BCVxsr7N_eNgVRqvHtD0zTZsEc6-VV-JvLexhqUzORcxaOzi6-AYWXvTBHm4bjyPjs7Vd8pZGH6SRpkNtoIAiw4"
+		salt      = "DGv6ra1nlYgDCS1FRnbzlw"
+		auth      = "BTBZMqHH6r4Tts7J_aSIgg"
+		expected  = "DGv6ra1nlYgDCS1FRnbzlwAAEABBBP4z9KsN6nGRTbVYI_c7VJSPQTBtkgcy27mlmlMoZIIgDll6e3vCYLocInmYWAmS6TlzAC8wEqKK6PBru3jl7A_yl95bQpu6cVPTpK4Mqgkf1CXztLVBSt2Ks3oZwbuwXPXLWyouBWLVWGNWQexSgSxsj_Qulcy4a-fN"
+	)
+
+	key, err := ecdh.P256().NewPrivateKey(mustDecode(t, asPrivate))
+	if err != nil {
+		t.Fatalf("NewPrivateKey: %v", err)
+	}
+	if got := base64.RawURLEncoding.EncodeToString(key.PublicKey().Bytes()); got != asPublic {
+		t.Fatalf("application server public key = %s, want %s", got, asPublic)
+	}
+
+	body, err := encryptWebPushWithKey([]byte(plaintext), uaPublic, auth, key, mustDecode(t, salt))
+	if err != nil {
+		t.Fatalf("encryptWebPushWithKey: %v", err)
+	}
+	if got := base64.RawURLEncoding.EncodeToString(body); got != expected {
+		t.Errorf("encrypted body =\n%s\nwant\n%s", got, expected)
+	}
+}
+
+func TestEncryptWebPushRejectsInvalidSubscription(t *testing.T) {
+	uaKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	validKey := base64.RawURLEncoding.EncodeToString(uaKey.PublicKey().Bytes())
+	validAuth := base64.RawURLEncoding.EncodeToString(make([]byte, 16))
+
+	tests := []struct {
+		name   string
+		p256dh string
+		auth   string
+	}{
+		{"p256dh not base64", "not base64!", validAuth},
+		{"p256dh not a P-256 point", base64.RawURLEncoding.EncodeToString(make([]byte, 65)), validAuth},
+		{"auth too short", validKey, base64.RawURLEncoding.EncodeToString(make([]byte, 8))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := encryptWebPush([]byte("hi"), tt.p256dh, tt.auth); err == nil {
+				t.Error("expected an error")
+			}
+		})
+	}
+
+	if _, err := encryptWebPush(bytes.Repeat([]byte("x"), webPushRecordSize), validKey, validAuth); IsRetryable(err) {
+		t.Errorf("oversized payload should fail permanently, got %v", err)
+	}
+}
+
+// decryptWebPush giải mã body aes128gcm một record bằng khóa của user agent (phía trình duyệt)
+func decryptWebPush(t *testing.T, body []byte, uaKey *ecdh.PrivateKey, auth []byte) []byte {
+	t.Helper()
+	salt := body[:16]
+	keyIDLen := int(body[20])
+	asPublicBytes := body[21 : 21+keyIDLen]
+	ciphertext := body[21+keyIDLen:]
+	if rs := binary.BigEndian.Uint32(body[16:20]); rs != webPushRecordSize {
+		t.Fatalf("record size = %d", rs)
+	}
+
+	asPublic, err := ecdh.P256().NewPublicKey(asPublicBytes)
+	if err != nil {
+		t.Fatalf("invalid key id: %v", err)
+	}
+	shared, err := uaKey.ECDH(asPublic)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ikm, _ := hkdf.Key(sha256.New, shared, auth, "WebPush: info\x00"+string(uaKey.PublicKey().Bytes())+string(asPublicBytes), 32)
+	cek, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: aes128gcm\x00", 16)
+	nonce, _ := hkdf.Key(sha256.New, ikm, salt, "Content-Encoding: nonce\x00", 12)
+
+	block, _ := aes.NewCipher(cek)
+	gcm, _ := cipher.NewGCM(block)
+	record, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		t.Fatalf("decrypt: %v", err)
+	}
+	if record[len(record)-1] != 0x02 {
+		t.Fatalf("missing last record delimiter")
+	}
+	return record[:len(record)-1]
+}
+
+func TestWebPushSendSignsVAPIDAndEncryptsPayload(t *testing.T) {
+	vapidKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	uaKey, err := ecdh.P256().GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	auth := make([]byte, 16)
+	rand.Read(auth)
+
+	var received *http.Request
+	var body []byte
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusCreated)
+	}))
+	defer server.Close()
+
+	provider, err := NewWebPushProvider(WebPushConfig{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(vapidKey.PublicKey().Bytes()),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(vapidKey.Bytes()),
+		Subject:    "mailto:admin@example.com",
+	})
+	if err != nil {
+		t.Fatalf("NewWebPushProvider: %v", err)
+	}
+	provider.(*webPushProvider).client = server.Client()
+
+	notification := Notification{Title: "Room", Body: "hello", CollapseKey: "room-1"}
+	err = provider.Send(context.Background(), Device{
+		ID:       1,
+		Platform: PlatformWebPush,
+		Token:    server.URL + "/push/abc",
+		P256dh:   base64.RawURLEncoding.EncodeToString(uaKey.PublicKey().Bytes()),
+		Auth:     base64.RawURLEncoding.EncodeToString(auth),
+	}, notification)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+
+	if got := received.Header.Get("Content-Encoding"); got != "aes128gcm" {
+		t.Errorf("Content-Encoding = %q", got)
+	}
+	if got := received.Header.Get("Topic"); got != "room-1" {
+		t.Errorf("Topic = %q", got)
+	}
+
+	// Authorization: vapid t=<JWT>, k=<public key>
+	authorization := strings.TrimPrefix(received.Header.Get("Authorization"), "vapid ")
+	var token, publicKey string
+	for _, part := range strings.Split(authorization, ", ") {
+		switch {
+		case strings.HasPrefix(part, "t="):
+			token = strings.TrimPrefix(part, "t=")
+		case strings.HasPrefix(part, "k="):
+			publicKey = strings.TrimPrefix(part, "k=")
+		}
+	}
+	k := mustDecode(t, publicKey)
+	if !bytes.Equal(k, vapidKey.PublicKey().Bytes()) {
+		t.Fatalf("k does not match the VAPID public key")
+	}
+	verifyKey := &ecdsa.PublicKey{Curve: elliptic.P256(), X: new(big.Int).SetBytes(k[1:33]), Y: new(big.Int).SetBytes(k[33:])}
+
+	claims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (any, error) { return verifyKey, nil },
+		jwt.WithValidMethods([]string{"ES256"}), jwt.WithExpirationRequired()); err != nil {
+		t.Fatalf("VAPID JWT does not verify: %v", err)
+	}
+	if claims["aud"] != server.URL {
+		t.Errorf("aud = %v, want %s", claims["aud"], server.URL)
+	}
+	if claims["sub"] != "mailto:admin@example.com" {
+		t.Errorf("sub = %v", claims["sub"])
+	}
+
+	var got Notification
+	if err := json.Unmarshal(decryptWebPush(t, body, uaKey, auth), &got); err != nil {
+		t.Fatalf("payload is not JSON: %v", err)
+	}
+	if got.Title != notification.Title || got.Body != notification.Body {
+		t.Errorf("payload = %+v, want %+v", got, notification)
+	}
+}
+
+func TestNewWebPushProviderRejectsMismatchedKeys(t *testing.T) {
+	a, _ := ecdh.P256().GenerateKey(rand.Reader)
+	b, _ := ecdh.P256().GenerateKey(rand.Reader)
+	_, err := NewWebPushProvider(WebPushConfig{
+		PublicKey:  base64.RawURLEncoding.EncodeToString(b.PublicKey().Bytes()),
+		PrivateKey: base64.RawURLEncoding.EncodeToString(a.Bytes()),
+	})
+	if err == nil {
+		t.Error("expected mismatched VAPID keys to be rejected")
+	}
+}
@@ -0,0 +1,17 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+)
+
+// ErrNotFound trả về khi object không tồn tại trong backend
+var ErrNotFound = errors.New("storage: object not found")
+
+// Storage lưu trữ nội dung file đính kèm (local filesystem, S3/MinIO...)
+type Storage interface {
+	Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error // hàm lưu object
+	Get(ctx context.Context, key string) (io.ReadCloser, error)                                // hàm đọc object, caller phải Close
+	Delete(ctx context.Context, key string) error                                              // hàm xóa object, không lỗi nếu không tồn tại
+}
@@ -0,0 +1,89 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type localStorage struct {
+	baseDir string
+}
+
+// NewLocalStorage lưu object thành file trong baseDir, key dạng "a/b/c" được ánh xạ thành thư mục con
+func NewLocalStorage(baseDir string) (Storage, error) {
+	absDir, err := filepath.Abs(baseDir)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(absDir, 0o755); err != nil {
+		return nil, err
+	}
+	return &localStorage{baseDir: absDir}, nil
+}
+
+func (ls *localStorage) Put(ctx context.Context, key string, body io.Reader, size int64, contentType string) error {
+	path, err := ls.pathFor(key)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	// Ghi ra file tạm rồi rename để không bao giờ đọc được file ghi dở
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	written, err := io.Copy(tmp, body)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if size >= 0 && written != size {
+		return fmt.Errorf("storage: wrote %d bytes, expected %d", written, size)
+	}
+
+	return os.Rename(tmp.Name(), path)
+}
+
+func (ls *localStorage) Get(ctx context.Context, key string) (io.ReadCloser, error) {
+	path, err := ls.pathFor(key)
+	if err != nil {
+		return nil, err
+	}
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return file, err
+}
+
+func (ls *localStorage) Delete(ctx context.Context, key string) error {
+	path, err := ls.pathFor(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// pathFor chặn key thoát ra ngoài baseDir (vd: "../../etc/passwd")
+func (ls *localStorage) pathFor(key string) (string, error) {
+	path := filepath.Join(ls.baseDir, filepath.FromSlash(key))
+	if !strings.HasPrefix(path, ls.baseDir+string(os.PathSeparator)) {
+		return "", fmt.Errorf("storage: invalid key %q", key)
+	}
+	return path, nil
+}
@@ -0,0 +1,40 @@
+package storage
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strconv"
+	"time"
+)
+
+// URLSigner ký URL tải file có thời hạn, để client (thẻ <img>, trình duyệt) tải được mà không cần header Authorization
+type URLSigner struct {
+	secret []byte
+	ttl    time.Duration
+}
+
+func NewURLSigner(secret string, ttl time.Duration) *URLSigner {
+	return &URLSigner{secret: []byte(secret), ttl: ttl}
+}
+
+// Sign trả về thời điểm hết hạn (unix) và chữ ký cho resource (vd: "attachments/42")
+func (s *URLSigner) Sign(resource string) (int64, string) {
+	expires := time.Now().Add(s.ttl).Unix()
+	return expires, s.sign(resource, expires)
+}
+
+// Verify kiểm tra chữ ký và thời hạn, expires là unix timestamp lấy từ query string
+func (s *URLSigner) Verify(resource string, expires int64, signature string) bool {
+	if time.Now().Unix() > expires {
+		return false
+	}
+	expected := s.sign(resource, expires)
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+func (s *URLSigner) sign(resource string, expires int64) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(resource + "\n" + strconv.FormatInt(expires, 10)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
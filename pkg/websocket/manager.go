@@ -55,7 +55,8 @@ type Manager struct {
 	mu                sync.RWMutex
 	clients           map[string]*Client
 	rooms             map[int64]map[string]*Client
-	userConnections   map[string]map[int64]*Client // userUUID -> roomID -> client
+	userConnections   map[string]map[int64]*Client  // userUUID -> roomID -> client
+	userClients       map[string]map[string]*Client // userUUID -> clientID -> client (mọi kết nối, kể cả chưa join phòng)
 	roomMessageQueues map[int64]chan Message
 
 	// Channels for async operations
@@ -70,6 +71,12 @@ type Manager struct {
 	// Room membership callback function
 	roomMembershipCallback RoomMembershipCheckFunc
 
+	// Callback khi user có kết nối đầu tiên (online) hoặc đóng kết nối cuối cùng (offline)
+	presenceChangeCallback PresenceChangeFunc
+
+	// Callback chọn những user không nhận tin nhắn broadcast trong phòng
+	recipientFilterCallback RecipientFilterFunc
+
 	// Cleanup tracking
 	roomCleanup map[int64]*time.Timer
 }
@@ -77,6 +84,12 @@ type Manager struct {
 // RoomMembershipCheckFunc callback để kiểm tra quyền phòng
 type RoomMembershipCheckFunc func(userUUID uuid.UUID, roomID int64) (bool, error)
 
+// PresenceChangeFunc callback khi trạng thái online của user thay đổi
+type PresenceChangeFunc func(userUUID uuid.UUID, online bool)
+
+// RecipientFilterFunc trả về những user không được nhận message (vd: đã chặn người gửi), nil nếu gửi cho tất cả
+type RecipientFilterFunc func(message Message) map[uuid.UUID]bool
+
 // NewManager creates a new WebSocket manager
 func NewManager() *Manager {
 	return NewManagerWithConfig(DefaultManagerConfig())
@@ -88,6 +101,7 @@ func NewManagerWithConfig(config ManagerConfig) *Manager {
 		clients:           make(map[string]*Client),
 		rooms:             make(map[int64]map[string]*Client),
 		userConnections:   make(map[string]map[int64]*Client),
+		userClients:       make(map[string]map[string]*Client),
 		roomMessageQueues: make(map[int64]chan Message),
 		register:          make(chan *Client),
 		unregister:        make(chan *Client),
@@ -105,6 +119,7 @@ func (m *Manager) Run() {
 		case client := <-m.register:
 			m.mu.Lock()
 			m.clients[client.ID] = client
+			m.addUserClient(client)
 			m.mu.Unlock()
 			log.Printf("Client registered: %s", client.ID)
 
@@ -156,11 +171,40 @@ func (m *Manager) removeClientSafely(client *Client) {
 
 	// Remove client
 	delete(m.clients, client.ID)
+	m.removeUserClient(client)
 	close(client.Send)
 
 	log.Printf("🧹 Client %s unregistered (user: %s)", client.ID, userUUID)
 }
 
+// addUserClient/removeUserClient cập nhật index kết nối theo user (caller giữ m.mu)
+func (m *Manager) addUserClient(client *Client) {
+	userUUID := client.UserUUID.String()
+	if m.userClients[userUUID] == nil {
+		m.userClients[userUUID] = make(map[string]*Client)
+		m.notifyPresenceChange(client.UserUUID, true)
+	}
+	m.userClients[userUUID][client.ID] = client
+}
+
+func (m *Manager) removeUserClient(client *Client) {
+	userUUID := client.UserUUID.String()
+	if userClients, exists := m.userClients[userUUID]; exists {
+		delete(userClients, client.ID)
+		if len(userClients) == 0 {
+			delete(m.userClients, userUUID)
+			m.notifyPresenceChange(client.UserUUID, false)
+		}
+	}
+}
+
+// notifyPresenceChange gọi callback ngoài goroutine hiện tại vì caller đang giữ m.mu
+func (m *Manager) notifyPresenceChange(userUUID uuid.UUID, online bool) {
+	if m.presenceChangeCallback != nil {
+		go m.presenceChangeCallback(userUUID, online)
+	}
+}
+
 // cleanupEmptyRoom cleans up empty room resources
 func (m *Manager) cleanupEmptyRoom(roomID int64) {
 	m.mu.Lock()
@@ -210,6 +254,7 @@ func (m *Manager) AddClientToRoom(roomID int64, client *Client) {
 		close(existingClient.Send)
 		existingClient.Conn.Close()
 		delete(m.clients, existingClient.ID)
+		m.removeUserClient(existingClient)
 	}
 
 	// Add new connection
@@ -323,9 +368,18 @@ func (m *Manager) processBroadcastMessage(message Message) {
 
 	log.Printf("Broadcasting to %d clients in room %d", len(clientList), roomID)
 
+	var excluded map[uuid.UUID]bool
+	if m.recipientFilterCallback != nil {
+		excluded = m.recipientFilterCallback(message)
+	}
+
 	// Send to clients without holding lock
 	var failedClients []*Client
 	for _, client := range clientList {
+		if excluded[client.UserUUID] {
+			continue
+		}
+
 		select {
 		case client.Send <- data:
 			// Message sent successfully
@@ -375,6 +429,72 @@ func (m *Manager) RemoveFailedClients(failedClients []*Client) {
 	}
 }
 
+// SendToUser gửi tin nhắn tới mọi kết nối của user, không phụ thuộc user đã join phòng nào
+func (m *Manager) SendToUser(userUUID uuid.UUID, message Message) {
+	data, err := json.Marshal(message)
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return
+	}
+
+	m.mu.RLock()
+	clientList := make([]*Client, 0, len(m.userClients[userUUID.String()]))
+	for _, client := range m.userClients[userUUID.String()] {
+		clientList = append(clientList, client)
+	}
+	m.mu.RUnlock()
+
+	var failedClients []*Client
+	for _, client := range clientList {
+		select {
+		case client.Send <- data:
+		default:
+			failedClients = append(failedClients, client)
+		}
+	}
+
+	if len(failedClients) > 0 {
+		m.RemoveFailedClients(failedClients)
+	}
+}
+
+// DisconnectUser gửi thông báo cuối cùng rồi đóng mọi kết nối của user (vd: khi phiên đăng nhập bị thu hồi)
+func (m *Manager) DisconnectUser(userUUID uuid.UUID, notification Message) {
+	data, err := json.Marshal(notification)
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return
+	}
+
+	m.mu.RLock()
+	clientList := make([]*Client, 0, len(m.userClients[userUUID.String()]))
+	for _, client := range m.userClients[userUUID.String()] {
+		clientList = append(clientList, client)
+	}
+	m.mu.RUnlock()
+
+	for _, client := range clientList {
+		select {
+		case client.Send <- data:
+		default:
+		}
+		// Đóng Send, writePump sẽ gửi close frame và đóng kết nối
+		m.Unregister(client)
+	}
+
+	if len(clientList) > 0 {
+		log.Printf("🔌 Disconnected %d client(s) of user %s", len(clientList), userUUID)
+	}
+}
+
+// IsUserOnline cho biết user có ít nhất một kết nối WebSocket đang mở
+func (m *Manager) IsUserOnline(userUUID uuid.UUID) bool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return len(m.userClients[userUUID.String()]) > 0
+}
+
 // IsClientInRoom checks if client is in a specific room
 func (m *Manager) IsClientInRoom(roomID int64, clientID string) bool {
 	m.mu.RLock()
@@ -438,10 +558,42 @@ func (m *Manager) LeaveRoom(roomID int64, client *Client) {
 	m.SendToRoom(notification)
 }
 
+// CloseRoom gửi thông báo cuối cùng tới mọi client trong phòng rồi đẩy họ ra khỏi phòng
+// (dùng khi phòng bị xóa). Gửi trực tiếp thay vì qua room queue vì queue sẽ bị dọn ngay sau đó.
+func (m *Manager) CloseRoom(notification Message) {
+	roomID := notification.RoomID
+
+	data, err := json.Marshal(notification)
+	if err != nil {
+		log.Printf("Error marshaling message: %v", err)
+		return
+	}
+
+	clientList := m.GetClientsInRoom(roomID)
+	for _, client := range clientList {
+		select {
+		case client.Send <- data:
+		default:
+			log.Printf("⚠️ Could not notify client %s that room %d was closed", client.ID, roomID)
+		}
+		m.RemoveClientFromRoom(roomID, client)
+	}
+
+	log.Printf("🚪 Room %d closed - %d clients removed", roomID, len(clientList))
+}
+
 func (m *Manager) SetRoomMembershipCallback(callback RoomMembershipCheckFunc) {
 	m.roomMembershipCallback = callback
 }
 
+func (m *Manager) SetPresenceChangeCallback(callback PresenceChangeFunc) {
+	m.presenceChangeCallback = callback
+}
+
+func (m *Manager) SetRecipientFilterCallback(callback RecipientFilterFunc) {
+	m.recipientFilterCallback = callback
+}
+
 // GetRoomInfo trả về thông tin clients trong phòng một cách an toàn
 func (m *Manager) GetRoomInfo(roomID int64) (map[string]*Client, bool) {
 	m.mu.RLock()